@@ -45,6 +45,7 @@ const (
 	EditorGroup     = "editor"
 	ConnectionGroup = "connection"
 	SidebarGroup    = "sidebar"
+	ActivityGroup   = "activity"
 )
 
 // Define a global KeymapSystem object with default keybinds
@@ -57,6 +58,7 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Code: tcell.KeyCtrlS}, Cmd: cmd.Save, Description: "Execute pending changes"},
 			Bind{Key: Key{Char: 'q'}, Cmd: cmd.Quit, Description: "Quit"},
 			Bind{Key: Key{Code: tcell.KeyBackspace2}, Cmd: cmd.SwitchToConnectionsView, Description: "Switch to connections list"},
+			Bind{Key: Key{Char: 'A'}, Cmd: cmd.ShowActivity, Description: "Show server activity"},
 			Bind{Key: Key{Char: '?'}, Cmd: cmd.HelpPopup, Description: "Help"},
 		},
 		ConnectionGroup: {
@@ -65,6 +67,7 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Code: tcell.KeyEnter}, Cmd: cmd.Connect, Description: "Connect to database"},
 			Bind{Key: Key{Char: 'e'}, Cmd: cmd.EditConnection, Description: "Edit a database connection"},
 			Bind{Key: Key{Char: 'd'}, Cmd: cmd.DeleteConnection, Description: "Delete a database connection"},
+			Bind{Key: Key{Char: '/'}, Cmd: cmd.Search, Description: "Search connections"},
 			Bind{Key: Key{Char: 'q'}, Cmd: cmd.Quit, Description: "Quit"},
 		},
 		TreeGroup: {
@@ -80,6 +83,16 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Char: 'p'}, Cmd: cmd.PreviousFoundNode, Description: "Go to previous found node"},
 			Bind{Key: Key{Char: 'c'}, Cmd: cmd.TreeCollapseAll, Description: "Collapse all"},
 			Bind{Key: Key{Char: 'e'}, Cmd: cmd.ExpandAll, Description: "Expand all"},
+			Bind{Key: Key{Char: 'D'}, Cmd: cmd.ShowDefinition, Description: "Show definition"},
+			Bind{Key: Key{Char: 'E'}, Cmd: cmd.ShowDiagram, Description: "Show ER diagram"},
+			Bind{Key: Key{Char: 'S'}, Cmd: cmd.CompareSchema, Description: "Compare schema"},
+			Bind{Key: Key{Char: 'i'}, Cmd: cmd.ShowDatabaseStats, Description: "Show database statistics"},
+			Bind{Key: Key{Char: 'R'}, Cmd: cmd.Refresh, Description: "Refresh the database, or the users and roles"},
+			Bind{Key: Key{Char: 'a'}, Cmd: cmd.CreateTable, Description: "Create table"},
+			Bind{Key: Key{Char: 'r'}, Cmd: cmd.RenameTable, Description: "Rename table"},
+			Bind{Key: Key{Char: 'T'}, Cmd: cmd.TruncateTable, Description: "Truncate table"},
+			Bind{Key: Key{Char: 'd'}, Cmd: cmd.DropTable, Description: "Drop table"},
+			Bind{Key: Key{Char: 'y'}, Cmd: cmd.DuplicateTable, Description: "Duplicate table"},
 		},
 		TreeFilterGroup: {
 			Bind{Key: Key{Code: tcell.KeyEscape}, Cmd: cmd.UnfocusTreeFilter, Description: "Unfocus tree filter"},
@@ -87,18 +100,46 @@ var Keymaps = KeymapSystem{
 		},
 		TableGroup: {
 			Bind{Key: Key{Char: '/'}, Cmd: cmd.Search, Description: "Search"},
-			Bind{Key: Key{Char: 'c'}, Cmd: cmd.Edit, Description: "Change cell"},
-			Bind{Key: Key{Char: 'd'}, Cmd: cmd.Delete, Description: "Delete row"},
+			Bind{Key: Key{Char: 'c'}, Cmd: cmd.Edit, Description: "Change cell or alter column"},
+			Bind{Key: Key{Char: 'd'}, Cmd: cmd.Delete, Description: "Delete row, column or index"},
 			Bind{Key: Key{Char: 'w'}, Cmd: cmd.GotoNext, Description: "Go to next cell"},
 			Bind{Key: Key{Char: 'b'}, Cmd: cmd.GotoPrev, Description: "Go to previous cell"},
 			Bind{Key: Key{Char: '$'}, Cmd: cmd.GotoEnd, Description: "Go to last cell"},
 			Bind{Key: Key{Char: '0'}, Cmd: cmd.GotoStart, Description: "Go to first cell"},
 			Bind{Key: Key{Char: 'y'}, Cmd: cmd.Copy, Description: "Copy cell to clipboard"},
-			Bind{Key: Key{Char: 'o'}, Cmd: cmd.AppendNewRow, Description: "Append new row"},
+			Bind{Key: Key{Char: 'o'}, Cmd: cmd.AppendNewRow, Description: "Append new row, column or index"},
+			Bind{Key: Key{Char: 'O'}, Cmd: cmd.AppendNewRowForm, Description: "Add a row with a form"},
+			Bind{Key: Key{Char: 'E'}, Cmd: cmd.EditRowForm, Description: "Edit the row in a form"},
 			Bind{Key: Key{Char: 'J'}, Cmd: cmd.SortDesc, Description: "Sort descending"},
 			Bind{Key: Key{Char: 'R'}, Cmd: cmd.Refresh, Description: "Refresh the current table"},
 			Bind{Key: Key{Char: 'K'}, Cmd: cmd.SortAsc, Description: "Sort ascending"},
+			Bind{Key: Key{Char: 'J', Mod: tcell.ModAlt}, Cmd: cmd.AddSortDesc, Description: "Add a descending sort column"},
+			Bind{Key: Key{Char: 'K', Mod: tcell.ModAlt}, Cmd: cmd.AddSortAsc, Description: "Add an ascending sort column"},
 			Bind{Key: Key{Char: 'C'}, Cmd: cmd.SetValue, Description: "Toggle value menu to put values like NULL, EMPTY or DEFAULT"},
+			Bind{Key: Key{Char: 'e'}, Cmd: cmd.ViewCell, Description: "View or edit the cell in full screen"},
+			Bind{Key: Key{Code: tcell.KeyCtrlSpace}, Cmd: cmd.OpenInExternalEditor, Description: "Edit the cell in an external editor"},
+			// Quick filters
+			Bind{Key: Key{Char: '='}, Cmd: cmd.FilterByValue, Description: "Filter the rows by the value of the cell"},
+			Bind{Key: Key{Char: '!'}, Cmd: cmd.ExcludeValue, Description: "Exclude the rows with the value of the cell"},
+			Bind{Key: Key{Char: 'n'}, Cmd: cmd.FilterNull, Description: "Filter the rows where the column is NULL"},
+			Bind{Key: Key{Char: 'N'}, Cmd: cmd.FilterNotNull, Description: "Filter the rows where the column is not NULL"},
+			Bind{Key: Key{Char: '-'}, Cmd: cmd.RemoveFilter, Description: "Remove the last quick filter"},
+			// Column layout
+			Bind{Key: Key{Char: 'x'}, Cmd: cmd.HideColumn, Description: "Hide the column"},
+			Bind{Key: Key{Char: 'v'}, Cmd: cmd.ShowColumns, Description: "Choose the visible columns"},
+			Bind{Key: Key{Char: 'h', Mod: tcell.ModAlt}, Cmd: cmd.MoveColumnLeft, Description: "Move the column left"},
+			Bind{Key: Key{Char: 'l', Mod: tcell.ModAlt}, Cmd: cmd.MoveColumnRight, Description: "Move the column right"},
+			Bind{Key: Key{Char: 'p'}, Cmd: cmd.PinColumns, Description: "Pin the columns up to this one, or unpin them"},
+			Bind{Key: Key{Char: '+'}, Cmd: cmd.WidenColumn, Description: "Widen the column"},
+			Bind{Key: Key{Char: '_'}, Cmd: cmd.NarrowColumn, Description: "Narrow the column"},
+			Bind{Key: Key{Char: 'a'}, Cmd: cmd.AutoFitColumn, Description: "Fit the column to its values"},
+			// Foreign keys
+			Bind{Key: Key{Char: 'f'}, Cmd: cmd.FollowForeignKey, Description: "Open the row referenced by the cell"},
+			Bind{Key: Key{Code: tcell.KeyCtrlP}, Cmd: cmd.PickForeignKey, Description: "Pick the value of the cell from the referenced rows"},
+			Bind{Key: Key{Char: 'F'}, Cmd: cmd.ShowReferences, Description: "List the rows that reference the row"},
+			Bind{Key: Key{Code: tcell.KeyCtrlO}, Cmd: cmd.NavigateBack, Description: "Go back to the previous row"},
+			Bind{Key: Key{Code: tcell.KeyTab}, Cmd: cmd.NavigateForward, Description: "Go forward to the next row"},
+			Bind{Key: Key{Char: 'D'}, Cmd: cmd.CompareData, Description: "Compare the rows with another table"},
 			// Tabs
 			Bind{Key: Key{Char: '['}, Cmd: cmd.TabPrev, Description: "Switch to previous tab"},
 			Bind{Key: Key{Char: ']'}, Cmd: cmd.TabNext, Description: "Switch to next tab"},
@@ -113,6 +154,8 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Char: '3'}, Cmd: cmd.ConstraintsMenu, Description: "Switch to constraints menu"},
 			Bind{Key: Key{Char: '4'}, Cmd: cmd.ForeignKeysMenu, Description: "Switch to foreign keys menu"},
 			Bind{Key: Key{Char: '5'}, Cmd: cmd.IndexesMenu, Description: "Switch to indexes menu"},
+			Bind{Key: Key{Char: '6'}, Cmd: cmd.DDLMenu, Description: "Switch to DDL menu"},
+			Bind{Key: Key{Char: '7'}, Cmd: cmd.StatsMenu, Description: "Switch to stats menu"},
 			// Sidebar
 			Bind{Key: Key{Char: 'S'}, Cmd: cmd.ToggleSidebar, Description: "Toggle sidebar"},
 			Bind{Key: Key{Char: 's'}, Cmd: cmd.FocusSidebar, Description: "Focus sidebar"},
@@ -121,6 +164,9 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Code: tcell.KeyCtrlR}, Cmd: cmd.Execute, Description: "Execute query"},
 			Bind{Key: Key{Code: tcell.KeyEscape}, Cmd: cmd.UnfocusEditor, Description: "Unfocus editor"},
 			Bind{Key: Key{Code: tcell.KeyCtrlSpace}, Cmd: cmd.OpenInExternalEditor, Description: "Open in external editor"},
+			Bind{Key: Key{Code: tcell.KeyCtrlT}, Cmd: cmd.BeginTransaction, Description: "Begin transaction"},
+			Bind{Key: Key{Code: tcell.KeyCtrlG}, Cmd: cmd.CommitTransaction, Description: "Commit transaction"},
+			Bind{Key: Key{Code: tcell.KeyCtrlN}, Cmd: cmd.RollbackTransaction, Description: "Rollback transaction"},
 		},
 		SidebarGroup: {
 			Bind{Key: Key{Char: 's'}, Cmd: cmd.UnfocusSidebar, Description: "Focus table"},
@@ -134,5 +180,14 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Code: tcell.KeyEscape}, Cmd: cmd.DiscardEdit, Description: "Discard edit"},
 			Bind{Key: Key{Char: 'C'}, Cmd: cmd.SetValue, Description: "Toggle value menu to put values like NULL, EMPTY or DEFAULT"},
 		},
+		ActivityGroup: {
+			Bind{Key: Key{Char: 'j'}, Cmd: cmd.MoveDown, Description: "Select next session"},
+			Bind{Key: Key{Char: 'k'}, Cmd: cmd.MoveUp, Description: "Select previous session"},
+			Bind{Key: Key{Code: tcell.KeyEnter}, Cmd: cmd.ShowQuery, Description: "Show the query of the session"},
+			Bind{Key: Key{Char: 'c'}, Cmd: cmd.CancelSession, Description: "Cancel the query of the session"},
+			Bind{Key: Key{Char: 'K'}, Cmd: cmd.TerminateSession, Description: "Terminate the session"},
+			Bind{Key: Key{Char: 'p'}, Cmd: cmd.TogglePause, Description: "Pause or resume the refreshes"},
+			Bind{Key: Key{Char: 'r'}, Cmd: cmd.Refresh, Description: "Refresh the sessions"},
+		},
 	},
 }
@@ -9,6 +9,7 @@ const (
 	SwitchToEditorView
 	SwitchToConnectionsView
 	HelpPopup
+	ShowActivity
 
 	// Movement: Basic
 	MoveUp
@@ -33,6 +34,8 @@ const (
 	ConstraintsMenu
 	ForeignKeysMenu
 	IndexesMenu
+	DDLMenu
+	StatsMenu
 
 	// Tabs
 	TabNext
@@ -54,19 +57,59 @@ const (
 	Quit
 	Execute
 	OpenInExternalEditor
+	BeginTransaction
+	CommitTransaction
+	RollbackTransaction
 	AppendNewRow
 	SortAsc
 	SortDesc
+	AddSortAsc
+	AddSortDesc
+	HideColumn
+	ShowColumns
+	MoveColumnLeft
+	MoveColumnRight
+	PinColumns
+	WidenColumn
+	NarrowColumn
+	AutoFitColumn
+	ViewCell
+	AppendNewRowForm
+	EditRowForm
 	UnfocusTreeFilter
 	CommitTreeFilter
 	NextFoundNode
 	PreviousFoundNode
 	TreeCollapseAll
 	ExpandAll
+	ShowDefinition
+	ShowDiagram
+	CompareSchema
+	ShowDatabaseStats
+	CreateTable
+	RenameTable
+	TruncateTable
+	DropTable
+	DuplicateTable
+	FollowForeignKey
+	PickForeignKey
+	ShowReferences
+	NavigateBack
+	NavigateForward
+	CompareData
 	SetValue
+	FilterByValue
+	ExcludeValue
+	FilterNull
+	FilterNotNull
+	RemoveFilter
 	FocusSidebar
 	UnfocusSidebar
 	ToggleSidebar
+	CancelSession
+	TerminateSession
+	TogglePause
+	ShowQuery
 
 	// Connection
 	NewConnection
@@ -85,6 +128,8 @@ func (c Command) String() string {
 		return "SwitchToEditorView"
 	case SwitchToConnectionsView:
 		return "SwitchToConnectionsView"
+	case ShowActivity:
+		return "ShowActivity"
 	case HelpPopup:
 		return "HelpPopup"
 
@@ -146,12 +191,44 @@ func (c Command) String() string {
 		return "Execute"
 	case OpenInExternalEditor:
 		return "OpenInExternalEditor"
+	case BeginTransaction:
+		return "BeginTransaction"
+	case CommitTransaction:
+		return "CommitTransaction"
+	case RollbackTransaction:
+		return "RollbackTransaction"
 	case AppendNewRow:
 		return "AppendNewRow"
 	case SortAsc:
 		return "SortAsc"
 	case SortDesc:
 		return "SortDesc"
+	case AddSortAsc:
+		return "AddSortAsc"
+	case AddSortDesc:
+		return "AddSortDesc"
+	case HideColumn:
+		return "HideColumn"
+	case ShowColumns:
+		return "ShowColumns"
+	case MoveColumnLeft:
+		return "MoveColumnLeft"
+	case MoveColumnRight:
+		return "MoveColumnRight"
+	case PinColumns:
+		return "PinColumns"
+	case WidenColumn:
+		return "WidenColumn"
+	case NarrowColumn:
+		return "NarrowColumn"
+	case AutoFitColumn:
+		return "AutoFitColumn"
+	case ViewCell:
+		return "ViewCell"
+	case AppendNewRowForm:
+		return "AppendNewRowForm"
+	case EditRowForm:
+		return "EditRowForm"
 	case NewConnection:
 		return "NewConnection"
 	case Connect:
@@ -174,6 +251,10 @@ func (c Command) String() string {
 		return "ForeignKeysMenu"
 	case IndexesMenu:
 		return "IndexesMenu"
+	case DDLMenu:
+		return "DDLMenu"
+	case StatsMenu:
+		return "StatsMenu"
 	case UnfocusTreeFilter:
 		return "UnfocusTreeFilter"
 	case CommitTreeFilter:
@@ -186,14 +267,62 @@ func (c Command) String() string {
 		return "TreeCollapseAll"
 	case ExpandAll:
 		return "ExpandAll"
+	case ShowDefinition:
+		return "ShowDefinition"
+	case ShowDiagram:
+		return "ShowDiagram"
+	case CompareSchema:
+		return "CompareSchema"
+	case ShowDatabaseStats:
+		return "ShowDatabaseStats"
+	case CompareData:
+		return "CompareData"
+	case CreateTable:
+		return "CreateTable"
+	case RenameTable:
+		return "RenameTable"
+	case TruncateTable:
+		return "TruncateTable"
+	case DropTable:
+		return "DropTable"
+	case DuplicateTable:
+		return "DuplicateTable"
+	case FollowForeignKey:
+		return "FollowForeignKey"
+	case PickForeignKey:
+		return "PickForeignKey"
+	case ShowReferences:
+		return "ShowReferences"
+	case NavigateBack:
+		return "NavigateBack"
+	case NavigateForward:
+		return "NavigateForward"
 	case SetValue:
 		return "SetValue"
+	case FilterByValue:
+		return "FilterByValue"
+	case ExcludeValue:
+		return "ExcludeValue"
+	case FilterNull:
+		return "FilterNull"
+	case FilterNotNull:
+		return "FilterNotNull"
+	case RemoveFilter:
+		return "RemoveFilter"
 	case FocusSidebar:
 		return "FocusSidebar"
 	case ToggleSidebar:
 		return "ToggleSidebar"
 	case UnfocusSidebar:
 		return "UnfocusSidebar"
+	case CancelSession:
+		return "CancelSession"
+	case TerminateSession:
+		return "TerminateSession"
+	case TogglePause:
+		return "TogglePause"
+	case ShowQuery:
+		return "ShowQuery"
 	case CommitEdit:
 		return "CommitEdit"
 	case DiscardEdit:
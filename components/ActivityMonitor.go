@@ -0,0 +1,297 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// activityRefreshInterval is how often the activity monitor lists the sessions again.
+const activityRefreshInterval = 2 * time.Second
+
+var activityColumns = []string{"ID", "User", "Database", "State", "Duration", "Blocked by", "Query"}
+
+// ActivityMonitor lists the sessions of the server and refreshes them on an interval, the selected
+// session can be cancelled or terminated.
+type ActivityMonitor struct {
+	*tview.Table
+	home     *Home
+	sessions []models.Session
+	paused   atomic.Bool
+	stop     chan struct{}
+}
+
+// NewActivityMonitor returns an empty activity monitor of the connection of a home.
+func NewActivityMonitor(home *Home) *ActivityMonitor {
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetBorderColor(app.Styles.PrimaryTextColor)
+	table.SetFixed(1, 0)
+	table.SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
+
+	for i, column := range activityColumns {
+		table.SetCell(0, i, tview.NewTableCell(column).SetTextColor(app.Styles.PrimaryTextColor).SetSelectable(false))
+	}
+
+	monitor := &ActivityMonitor{
+		Table: table,
+		home:  home,
+		stop:  make(chan struct{}),
+	}
+
+	monitor.setTitle("")
+	table.SetInputCapture(monitor.inputCapture)
+
+	return monitor
+}
+
+// Start keeps refreshing the sessions in the background until the monitor is closed.
+func (monitor *ActivityMonitor) Start() {
+	go func() {
+		ticker := time.NewTicker(activityRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-monitor.stop:
+				return
+			case <-ticker.C:
+				if monitor.paused.Load() {
+					continue
+				}
+
+				sessions, err := monitor.home.DBDriver.GetSessions()
+
+				App.QueueUpdateDraw(func() {
+					monitor.setSessions(sessions, err)
+				})
+			}
+		}
+	}()
+}
+
+// Refresh lists the sessions again.
+func (monitor *ActivityMonitor) Refresh() {
+	sessions, err := monitor.home.DBDriver.GetSessions()
+	monitor.setSessions(sessions, err)
+}
+
+// Close stops the refreshes and removes the monitor.
+func (monitor *ActivityMonitor) Close() {
+	close(monitor.stop)
+	MainPages.RemovePage(pageNameActivityMonitor)
+}
+
+func (monitor *ActivityMonitor) setTitle(message string) {
+	state := fmt.Sprintf("refreshing every %s", activityRefreshInterval)
+	if monitor.paused.Load() {
+		state = "paused"
+	}
+
+	if message != "" {
+		message += ", "
+	}
+
+	monitor.SetTitle(fmt.Sprintf(" Activity of %s, %d sessions, %s (%sc cancel, K terminate, p pause, r refresh, Enter query, Esc close) ", monitor.home.Connection.Name, len(monitor.sessions), state, message))
+}
+
+// setSessions shows the sessions, keeping the selected one selected when it is still there.
+func (monitor *ActivityMonitor) setSessions(sessions []models.Session, err error) {
+	if err != nil {
+		monitor.setTitle(err.Error())
+		return
+	}
+
+	selected, hasSelected := monitor.selectedSession()
+
+	monitor.sessions = sessions
+
+	for row := monitor.GetRowCount() - 1; row > 0; row-- {
+		monitor.RemoveRow(row)
+	}
+
+	blocking := map[string]bool{}
+	for _, session := range sessions {
+		for _, id := range session.BlockedBy {
+			blocking[id] = true
+		}
+	}
+
+	selectedRow := 1
+
+	for i, session := range sessions {
+		row := i + 1
+
+		color := app.Styles.PrimaryTextColor
+		switch {
+		case len(session.BlockedBy) > 0:
+			color = tcell.ColorRed
+		case blocking[session.ID]:
+			color = tcell.ColorYellow
+		}
+
+		values := []string{
+			session.ID,
+			session.User,
+			session.Database,
+			session.State,
+			formatDuration(session.Duration),
+			strings.Join(session.BlockedBy, ", "),
+			strings.Join(strings.Fields(session.Query), " "),
+		}
+
+		for column, value := range values {
+			cell := tview.NewTableCell(tview.Escape(value)).SetTextColor(color)
+			if column == len(values)-1 {
+				cell.SetMaxWidth(120)
+			}
+
+			monitor.SetCell(row, column, cell)
+		}
+
+		if hasSelected && session.ID == selected.ID {
+			selectedRow = row
+		}
+	}
+
+	if len(sessions) > 0 {
+		monitor.Select(selectedRow, 0)
+	}
+
+	monitor.setTitle("")
+}
+
+func (monitor *ActivityMonitor) selectedSession() (models.Session, bool) {
+	row, _ := monitor.GetSelection()
+	if row < 1 || row > len(monitor.sessions) {
+		return models.Session{}, false
+	}
+
+	return monitor.sessions[row-1], true
+}
+
+// signalSession asks for confirmation before cancelling or terminating the selected session.
+func (monitor *ActivityMonitor) signalSession(terminate bool) {
+	session, ok := monitor.selectedSession()
+	if !ok {
+		return
+	}
+
+	if monitor.home.Connection.ReadOnly {
+		monitor.setTitle("this connection is read-only")
+		return
+	}
+
+	action, signal := "Cancel the query of", monitor.home.DBDriver.CancelSession
+	if terminate {
+		action, signal = "Terminate", monitor.home.DBDriver.TerminateSession
+	}
+
+	confirmationModal := NewConfirmationModal(fmt.Sprintf("%s session %s of %s?", action, session.ID, session.User))
+	confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+		MainPages.RemovePage(pageNameConfirmation)
+
+		if buttonLabel != "Yes" {
+			return
+		}
+
+		if err := signal(session.ID); err != nil {
+			monitor.setTitle(err.Error())
+			return
+		}
+
+		monitor.Refresh()
+	})
+
+	MainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+}
+
+// showQuery shows the whole query of the selected session.
+func (monitor *ActivityMonitor) showQuery() {
+	session, ok := monitor.selectedSession()
+	if !ok {
+		return
+	}
+
+	query := tview.NewTextView()
+	query.SetBorder(true)
+	query.SetBorderColor(app.Styles.PrimaryTextColor)
+	query.SetTextColor(app.Styles.PrimaryTextColor)
+	query.SetTitle(fmt.Sprintf(" Query of session %s (Esc to close) ", session.ID))
+	query.SetText(session.Query)
+	query.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyEnter {
+			MainPages.RemovePage(pageNameSessionQuery)
+			return nil
+		}
+
+		return event
+	})
+
+	MainPages.AddPage(pageNameSessionQuery, centeredModal(query, 100, 20), true, true)
+}
+
+func (monitor *ActivityMonitor) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	if command := app.Keymaps.Group(app.HomeGroup).Resolve(event); command == commands.Quit || event.Key() == tcell.KeyEsc {
+		monitor.Close()
+		return nil
+	}
+
+	switch app.Keymaps.Group(app.ActivityGroup).Resolve(event) {
+	case commands.ShowQuery:
+		monitor.showQuery()
+	case commands.CancelSession:
+		monitor.signalSession(false)
+	case commands.TerminateSession:
+		monitor.signalSession(true)
+	case commands.Refresh:
+		monitor.Refresh()
+	case commands.TogglePause:
+		monitor.paused.Store(!monitor.paused.Load())
+		monitor.setTitle("")
+	case commands.MoveDown:
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case commands.MoveUp:
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	default:
+		return event
+	}
+
+	return nil
+}
+
+// formatDuration formats the duration of a session with a precision of a second.
+func formatDuration(duration time.Duration) string {
+	return duration.Round(time.Second).String()
+}
+
+// showActivityMonitor opens the activity monitor of the connection.
+func (home *Home) showActivityMonitor() {
+	sessions, err := home.DBDriver.GetSessions()
+	if err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	monitor := NewActivityMonitor(home)
+	monitor.setSessions(sessions, nil)
+
+	MainPages.AddPage(pageNameActivityMonitor, tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(monitor, 0, 12, true).
+			AddItem(nil, 0, 1, false), 0, 12, true).
+		AddItem(nil, 0, 1, false), true, true)
+
+	monitor.Start()
+}
@@ -0,0 +1,431 @@
+package components
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/lib"
+)
+
+// Pages of the cell viewer
+const (
+	cellViewerPageView = "view"
+	cellViewerPageEdit = "edit"
+)
+
+// cellViewerViews are the views of the value offered for each format, the first one by default.
+var cellViewerViews = map[string][]string{
+	helpers.CellFormatJSON:   {helpers.CellFormatJSON, helpers.CellFormatText, helpers.CellFormatBinary},
+	helpers.CellFormatXML:    {helpers.CellFormatXML, helpers.CellFormatText, helpers.CellFormatBinary},
+	helpers.CellFormatText:   {helpers.CellFormatText, helpers.CellFormatBinary},
+	helpers.CellFormatBinary: {helpers.CellFormatBinary, helpers.CellFormatText},
+}
+
+var cellViewerViewNames = map[string]string{
+	helpers.CellFormatJSON:   "JSON",
+	helpers.CellFormatXML:    "XML",
+	helpers.CellFormatText:   "Text",
+	helpers.CellFormatBinary: "Hex",
+}
+
+// CellViewer shows the value of a cell in full screen: JSON and XML are pretty-printed and can
+// be folded, text is wrapped and binary values are shown as a hex dump. The value can be edited
+// when the cell belongs to the records of a table that accepts changes.
+type CellViewer struct {
+	*tview.Pages
+	table    *ResultsTable
+	textView *tview.TextView
+	textArea *tview.TextArea
+	row      int
+	col      int
+	column   string
+	value    string
+	format   string
+	view     string
+	editable bool
+	// lines are the lines of the pretty-printed value, of which visible are left after folding
+	lines   []string
+	visible []helpers.TextLine
+	folded  map[int]bool
+	cursor  int
+	// editing is the text the edition started with
+	editing string
+}
+
+func NewCellViewer(table *ResultsTable, row, col int) *CellViewer {
+	cell := table.GetCell(row, col)
+
+	value := cell.Text
+	if reference, ok := cell.GetReference().(string); ok && (reference == "NULL&" || reference == "EMPTY&" || reference == "DEFAULT&") {
+		value = ""
+	}
+
+	textView := tview.NewTextView()
+	textView.SetBorder(true)
+	textView.SetBorderPadding(0, 0, 1, 1)
+	textView.SetBorderColor(app.Styles.PrimaryTextColor)
+	textView.SetTextColor(app.Styles.PrimaryTextColor)
+	textView.SetDynamicColors(true)
+
+	textArea := tview.NewTextArea()
+	textArea.SetBorder(true)
+	textArea.SetBorderPadding(0, 0, 1, 1)
+	textArea.SetBorderColor(app.Styles.PrimaryTextColor)
+	textArea.SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.PrimaryTextColor))
+
+	viewer := &CellViewer{
+		Pages:    tview.NewPages(),
+		table:    table,
+		textView: textView,
+		textArea: textArea,
+		row:      row,
+		col:      col,
+		column:   table.GetColumnNameByIndex(col),
+		value:    value,
+		format:   helpers.DetectCellFormat(value),
+		editable: !table.GetIsReadOnly() && !table.GetIsView() && table.isShowingTableRecords(),
+	}
+
+	textView.SetInputCapture(viewer.viewInputCapture)
+	textArea.SetInputCapture(viewer.editInputCapture)
+
+	viewer.AddPage(cellViewerPageView, textView, true, true)
+	viewer.AddPage(cellViewerPageEdit, textArea, true, false)
+
+	viewer.setView(cellViewerViews[viewer.format][0])
+
+	return viewer
+}
+
+// showCellViewer opens the selected cell in the cell viewer.
+func (table *ResultsTable) showCellViewer(row, col int) {
+	MainPages.AddPage(pageNameCellViewer, NewCellViewer(table, row, col), true, true)
+}
+
+// editCellInExternalEditor opens the value of the cell in the external editor and queues its
+// update when it changed.
+func (table *ResultsTable) editCellInExternalEditor(row, col int) {
+	if table.denyIfReadOnly() || !table.isShowingTableRecords() {
+		return
+	}
+
+	value := table.GetCell(row, col).Text
+	format := helpers.DetectCellFormat(value)
+
+	if format == helpers.CellFormatBinary {
+		table.SetError("Binary values can't be edited as text", nil)
+		return
+	}
+
+	table.setCellValue(row, col, editCellText(format, value))
+
+	if table.GetShowSidebar() {
+		table.UpdateSidebar()
+	}
+}
+
+// editCellText edits the text of a cell in the external editor, in a temporary file with the
+// extension of its format.
+func editCellText(format, text string) string {
+	path := "./lazysql-cell.txt"
+	if format == helpers.CellFormatJSON || format == helpers.CellFormatXML {
+		path = "./lazysql-cell." + format
+	}
+
+	newText := openExternalEditor(path, text)
+
+	// Editors end the files they save with a newline
+	if !strings.HasSuffix(text, "\n") {
+		newText = strings.TrimSuffix(newText, "\n")
+	}
+
+	return newText
+}
+
+// setView shows the value as JSON, XML, wrapped text or a hex dump.
+func (viewer *CellViewer) setView(view string) {
+	viewer.view = view
+	viewer.textView.SetRegions(false)
+	viewer.textView.Highlight()
+
+	switch view {
+	case helpers.CellFormatJSON, helpers.CellFormatXML:
+		pretty, err := helpers.PrettyJSON(viewer.value)
+		if view == helpers.CellFormatXML {
+			pretty, err = helpers.PrettyXML(viewer.value)
+		}
+
+		if err != nil {
+			viewer.setView(helpers.CellFormatText)
+			return
+		}
+
+		viewer.lines = strings.Split(pretty, "\n")
+		viewer.folded = map[int]bool{}
+		viewer.cursor = 0
+
+		viewer.textView.SetWrap(false)
+		viewer.textView.SetRegions(true)
+		viewer.render(0)
+	case helpers.CellFormatText:
+		viewer.textView.SetWrap(true)
+		viewer.textView.SetWordWrap(true)
+		viewer.textView.SetText(tview.Escape(viewer.value))
+	case helpers.CellFormatBinary:
+		viewer.textView.SetWrap(false)
+		viewer.textView.SetText(tview.Escape(helpers.HexDump(viewer.value)))
+	}
+
+	viewer.textView.ScrollToBeginning()
+	viewer.setTitle("")
+}
+
+// setTitle shows the column, the view and the keys in the title, or the message if there is one.
+func (viewer *CellViewer) setTitle(message string) {
+	if message != "" {
+		viewer.textView.SetTitle(fmt.Sprintf(" %s ", message))
+		return
+	}
+
+	keys := "Tab to switch view, y to copy, Esc to close"
+	if viewer.view == helpers.CellFormatJSON || viewer.view == helpers.CellFormatXML {
+		keys = "Space to fold, z/Z to fold/unfold all, " + keys
+	}
+	if viewer.editable {
+		keys = "e to edit, " + keys
+	}
+
+	viewer.textView.SetTitle(fmt.Sprintf(" %s · %s (%s) ", viewer.column, cellViewerViewNames[viewer.view], keys))
+}
+
+// render shows the lines left visible by the folding and puts the cursor on the line of the
+// value with the given number, or on the visible line that folds it.
+func (viewer *CellViewer) render(number int) {
+	viewer.visible = helpers.FoldLines(viewer.lines, viewer.folded)
+
+	var text strings.Builder
+
+	for i, line := range viewer.visible {
+		if line.Number <= number {
+			viewer.cursor = i
+		}
+
+		gutter := "  "
+		switch {
+		case line.Hidden > 0:
+			gutter = "▸ "
+		case line.Foldable:
+			gutter = "▾ "
+		}
+
+		fmt.Fprintf(&text, "[\"%d\"]%s%s[\"\"]\n", i, gutter, tview.Escape(line.Text))
+	}
+
+	viewer.textView.SetText(text.String())
+	viewer.moveCursor(0)
+}
+
+// moveCursor moves the cursor by the given number of visible lines.
+func (viewer *CellViewer) moveCursor(lines int) {
+	viewer.cursor += lines
+
+	if viewer.cursor >= len(viewer.visible) {
+		viewer.cursor = len(viewer.visible) - 1
+	}
+	if viewer.cursor < 0 {
+		viewer.cursor = 0
+	}
+
+	viewer.textView.Highlight(strconv.Itoa(viewer.cursor))
+	viewer.textView.ScrollToHighlight()
+}
+
+// toggleFold folds or unfolds the block opened at the line of the cursor.
+func (viewer *CellViewer) toggleFold() {
+	line := viewer.visible[viewer.cursor]
+	if !line.Foldable {
+		return
+	}
+
+	viewer.folded[line.Number] = !viewer.folded[line.Number]
+	viewer.render(line.Number)
+}
+
+// foldAll folds every block but the outermost ones, or unfolds them all.
+func (viewer *CellViewer) foldAll(fold bool) {
+	number := viewer.visible[viewer.cursor].Number
+	viewer.folded = map[int]bool{}
+
+	if fold {
+		for _, line := range helpers.FoldLines(viewer.lines, nil) {
+			if line.Foldable && strings.HasPrefix(line.Text, " ") {
+				viewer.folded[line.Number] = true
+			}
+		}
+	}
+
+	viewer.render(number)
+}
+
+func (viewer *CellViewer) close() {
+	MainPages.RemovePage(pageNameCellViewer)
+	App.SetFocus(viewer.table)
+}
+
+// edit starts editing the value as it is shown, or as it is when it is shown as a hex dump.
+func (viewer *CellViewer) edit() {
+	if !viewer.editable {
+		viewer.setTitle("This cell can't be edited")
+		return
+	}
+
+	if viewer.format == helpers.CellFormatBinary {
+		viewer.setTitle("Binary values can't be edited as text")
+		return
+	}
+
+	viewer.editing = viewer.value
+	if viewer.view == helpers.CellFormatJSON || viewer.view == helpers.CellFormatXML {
+		viewer.editing = strings.Join(viewer.lines, "\n")
+	}
+
+	viewer.textArea.SetText(viewer.editing, false)
+	viewer.textArea.SetTitle(fmt.Sprintf(" Editing %s (Ctrl+S to keep the change, Esc to discard it) ", viewer.column))
+
+	viewer.SwitchToPage(cellViewerPageEdit)
+	App.SetFocus(viewer.textArea)
+}
+
+// keep puts the edited text in the cell and queues the change. JSON that was stored compact is
+// compacted again.
+func (viewer *CellViewer) keep(text string) {
+	if text == viewer.editing {
+		return
+	}
+
+	if viewer.format == helpers.CellFormatJSON && !strings.Contains(viewer.value, "\n") {
+		var compact bytes.Buffer
+		if json.Compact(&compact, []byte(text)) == nil {
+			text = compact.String()
+		}
+	}
+
+	viewer.table.setCellValue(viewer.row, viewer.col, text)
+
+	if viewer.table.GetShowSidebar() {
+		viewer.table.UpdateSidebar()
+	}
+
+	viewer.value = text
+	viewer.format = helpers.DetectCellFormat(text)
+	viewer.setView(cellViewerViews[viewer.format][0])
+}
+
+func (viewer *CellViewer) stopEditing() {
+	viewer.SwitchToPage(cellViewerPageView)
+	App.SetFocus(viewer.textView)
+}
+
+func (viewer *CellViewer) viewInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+
+	switch {
+	case command == commands.Quit || event.Key() == tcell.KeyEsc:
+		viewer.close()
+		return nil
+	case event.Key() == tcell.KeyTab:
+		views := cellViewerViews[viewer.format]
+		for i, view := range views {
+			if view == viewer.view {
+				viewer.setView(views[(i+1)%len(views)])
+				break
+			}
+		}
+
+		return nil
+	case event.Rune() == 'y':
+		err := lib.NewClipboard().Write(viewer.value)
+		if err != nil {
+			viewer.setTitle(err.Error())
+		} else {
+			viewer.setTitle("Copied to clipboard")
+		}
+
+		return nil
+	case event.Rune() == 'e':
+		viewer.edit()
+		return nil
+	case event.Key() == tcell.KeyCtrlSpace && runtime.GOOS == "linux":
+		// ----- THIS IS A LINUX-ONLY FEATURE, for now
+		if !viewer.editable || viewer.format == helpers.CellFormatBinary {
+			viewer.setTitle("This cell can't be edited")
+			return nil
+		}
+
+		viewer.editing = viewer.value
+		viewer.keep(editCellText(viewer.format, viewer.value))
+
+		return nil
+	}
+
+	if viewer.view != helpers.CellFormatJSON && viewer.view != helpers.CellFormatXML {
+		return event
+	}
+
+	switch {
+	case event.Rune() == 'j' || event.Key() == tcell.KeyDown:
+		viewer.moveCursor(1)
+	case event.Rune() == 'k' || event.Key() == tcell.KeyUp:
+		viewer.moveCursor(-1)
+	case event.Key() == tcell.KeyPgDn:
+		_, _, _, height := viewer.textView.GetInnerRect()
+		viewer.moveCursor(height)
+	case event.Key() == tcell.KeyPgUp:
+		_, _, _, height := viewer.textView.GetInnerRect()
+		viewer.moveCursor(-height)
+	case event.Rune() == 'g' || event.Key() == tcell.KeyHome:
+		viewer.moveCursor(-len(viewer.visible))
+	case event.Rune() == 'G' || event.Key() == tcell.KeyEnd:
+		viewer.moveCursor(len(viewer.visible))
+	case event.Rune() == ' ' || event.Key() == tcell.KeyEnter:
+		viewer.toggleFold()
+	case event.Rune() == 'z':
+		viewer.foldAll(true)
+	case event.Rune() == 'Z':
+		viewer.foldAll(false)
+	default:
+		return event
+	}
+
+	return nil
+}
+
+func (viewer *CellViewer) editInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEsc:
+		viewer.stopEditing()
+		return nil
+	case tcell.KeyCtrlS:
+		viewer.keep(viewer.textArea.GetText())
+		viewer.stopEditing()
+		return nil
+	case tcell.KeyCtrlSpace:
+		if runtime.GOOS == "linux" {
+			viewer.textArea.SetText(editCellText(viewer.format, viewer.textArea.GetText()), false)
+		}
+
+		return nil
+	}
+
+	return event
+}
@@ -0,0 +1,478 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	// columnWidthStep is how much a column is widened or narrowed at a time.
+	columnWidthStep = 4
+	// minColumnWidth is the narrowest a column gets, enough for a character and the truncation
+	// marker.
+	minColumnWidth = 3
+)
+
+// layoutContent holds the cells of a results table like the default content of tview, but shows
+// only the first visibleColumns columns. The hidden columns of the layout are arranged after the
+// visible ones, so their cells are still there for the code that looks them up.
+type layoutContent struct {
+	cells [][]*tview.TableCell
+	// lastColumn is the rightmost column of the cells
+	lastColumn int
+	// visibleColumns is the number of columns shown, 0 shows all of them
+	visibleColumns int
+}
+
+func newLayoutContent() *layoutContent {
+	return &layoutContent{lastColumn: -1}
+}
+
+func (content *layoutContent) Clear() {
+	content.cells = nil
+	content.lastColumn = -1
+}
+
+func (content *layoutContent) SetCell(row, column int, cell *tview.TableCell) {
+	if row >= len(content.cells) {
+		content.cells = append(content.cells, make([][]*tview.TableCell, row-len(content.cells)+1)...)
+	}
+
+	rowLength := len(content.cells[row])
+	if column >= rowLength {
+		content.cells[row] = append(content.cells[row], make([]*tview.TableCell, column-rowLength+1)...)
+		for c := rowLength; c < column; c++ {
+			content.cells[row][c] = &tview.TableCell{}
+		}
+	}
+
+	content.cells[row][column] = cell
+
+	if column > content.lastColumn {
+		content.lastColumn = column
+	}
+}
+
+func (content *layoutContent) RemoveRow(row int) {
+	if row < 0 || row >= len(content.cells) {
+		return
+	}
+
+	content.cells = append(content.cells[:row], content.cells[row+1:]...)
+}
+
+func (content *layoutContent) RemoveColumn(column int) {
+	for row := range content.cells {
+		if column < 0 || column >= len(content.cells[row]) {
+			continue
+		}
+
+		content.cells[row] = append(content.cells[row][:column], content.cells[row][column+1:]...)
+	}
+
+	if column >= 0 && column <= content.lastColumn {
+		content.lastColumn--
+	}
+}
+
+func (content *layoutContent) InsertRow(row int) {
+	if row >= len(content.cells) {
+		return
+	}
+
+	content.cells = append(content.cells, nil)
+	copy(content.cells[row+1:], content.cells[row:])
+	content.cells[row] = nil
+}
+
+func (content *layoutContent) InsertColumn(column int) {
+	for row := range content.cells {
+		if column >= len(content.cells[row]) {
+			continue
+		}
+
+		content.cells[row] = append(content.cells[row], nil)
+		copy(content.cells[row][column+1:], content.cells[row][column:])
+		content.cells[row][column] = &tview.TableCell{}
+	}
+}
+
+func (content *layoutContent) GetCell(row, column int) *tview.TableCell {
+	if row < 0 || column < 0 || row >= len(content.cells) || column >= len(content.cells[row]) {
+		return nil
+	}
+
+	return content.cells[row][column]
+}
+
+func (content *layoutContent) GetRowCount() int {
+	return len(content.cells)
+}
+
+func (content *layoutContent) GetColumnCount() int {
+	if len(content.cells) == 0 {
+		return 0
+	}
+
+	if content.visibleColumns > 0 && content.visibleColumns <= content.lastColumn {
+		return content.visibleColumns
+	}
+
+	return content.lastColumn + 1
+}
+
+// LoadLayout loads the saved layout of the records of the table, key is the one returned by
+// helpers.TableLayoutKey.
+func (table *ResultsTable) LoadLayout(key string) {
+	layout, err := helpers.LoadTableLayout(key)
+	if err != nil {
+		logger.Error("LoadLayout", map[string]any{"error": err.Error()})
+	}
+
+	table.state.layoutKey = key
+	table.state.layout = layout
+}
+
+func (table *ResultsTable) GetLayout() models.TableLayout {
+	return table.state.layout
+}
+
+// setLayout remembers a new layout of the records.
+func (table *ResultsTable) setLayout(layout models.TableLayout) {
+	table.state.layout = layout
+
+	if table.state.layoutKey == "" {
+		return
+	}
+
+	if err := helpers.SaveTableLayout(table.state.layoutKey, layout); err != nil {
+		table.SetError(fmt.Sprintf("Could not save the layout of the columns: %s", err.Error()), nil)
+	}
+}
+
+// arrangeColumns returns the records with their columns in the order of the layout, and sets
+// the number of columns to show.
+func (table *ResultsTable) arrangeColumns(records [][]string) [][]string {
+	if len(records) == 0 {
+		return records
+	}
+
+	order, visible := helpers.ArrangeColumns(records[0], table.state.layout)
+	table.state.visibleColumns = visible
+
+	arranged := make([][]string, len(records))
+	for i, record := range records {
+		row := make([]string, 0, len(record))
+		for _, column := range order {
+			if column < len(record) {
+				row = append(row, record[column])
+			}
+		}
+
+		arranged[i] = row
+	}
+
+	return arranged
+}
+
+// applyLayout hides, pins and sizes the columns of the records shown.
+func (table *ResultsTable) applyLayout() {
+	layout := table.state.layout
+	records := table.GetRecords()
+
+	table.state.content.visibleColumns = table.state.visibleColumns
+
+	pinned := layout.Pinned
+	if pinned > table.state.visibleColumns {
+		pinned = table.state.visibleColumns
+	}
+
+	table.SetFixed(1, pinned)
+
+	if len(records) == 0 {
+		return
+	}
+
+	for column, name := range records[0] {
+		width := layout.Widths[name]
+
+		for row := 0; row < table.GetRowCount(); row++ {
+			if cell := table.GetCell(row, column); cell != nil {
+				cell.SetMaxWidth(width)
+
+				// An expanding column would be wider than its width when there is room
+				if width > 0 {
+					cell.SetExpansion(0)
+				} else {
+					cell.SetExpansion(1)
+				}
+			}
+		}
+	}
+}
+
+// isShowingTableRecords tells whether the records of a table are shown, which are the ones
+// that have a layout.
+func (table *ResultsTable) isShowingTableRecords() bool {
+	return table.Menu != nil && table.Menu.GetSelectedOption() == 1 && table.state.layoutKey != ""
+}
+
+// showRecords shows the records laid out.
+func (table *ResultsTable) showRecords() {
+	table.UpdateRows(table.GetRecords())
+	table.applyLayout()
+	table.setSortIndicators()
+}
+
+// hasPendingChanges tells whether the records of the table have changes that are not saved.
+func (table *ResultsTable) hasPendingChanges() bool {
+	for _, change := range *table.state.listOfDbChanges {
+		if change.Database == table.GetDatabaseName() && change.Table == table.GetTableName() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rearrangeColumns changes the order or the visibility of the columns. The cells of the pending
+// changes are tracked by their position, so the columns can't be moved until they are saved.
+func (table *ResultsTable) rearrangeColumns(layout models.TableLayout) bool {
+	if table.hasPendingChanges() {
+		table.SetError("Save or discard the pending changes of the table before rearranging its columns", nil)
+		return false
+	}
+
+	table.setLayout(layout)
+	table.state.records = table.arrangeColumns(table.GetRecords())
+	table.showRecords()
+
+	return true
+}
+
+// copyLayout returns a copy of the layout of the records that can be changed without changing
+// the current one.
+func (table *ResultsTable) copyLayout() models.TableLayout {
+	layout := table.state.layout
+
+	layout.Order = append([]string(nil), layout.Order...)
+	layout.Hidden = append([]string(nil), layout.Hidden...)
+
+	widths := make(map[string]int, len(layout.Widths))
+	for column, width := range layout.Widths {
+		widths[column] = width
+	}
+
+	layout.Widths = widths
+
+	return layout
+}
+
+// hideColumn hides a column of the records, keeping one visible at least.
+func (table *ResultsTable) hideColumn(row, column int) {
+	records := table.GetRecords()
+	if len(records) == 0 || column >= len(records[0]) {
+		return
+	}
+
+	if table.state.visibleColumns <= 1 {
+		table.SetError("The last visible column can't be hidden", nil)
+		return
+	}
+
+	layout := table.copyLayout()
+	layout.Hidden = append(layout.Hidden, records[0][column])
+
+	if !table.rearrangeColumns(layout) {
+		return
+	}
+
+	if column >= table.state.visibleColumns {
+		column = table.state.visibleColumns - 1
+	}
+
+	table.Select(row, column)
+}
+
+// moveColumn moves a column of the records one position to the left or to the right.
+func (table *ResultsTable) moveColumn(row, column, offset int) {
+	records := table.GetRecords()
+	target := column + offset
+
+	if len(records) == 0 || target < 0 || target >= table.state.visibleColumns {
+		return
+	}
+
+	layout := table.copyLayout()
+	layout.Order = append([]string(nil), records[0]...)
+	layout.Order[column], layout.Order[target] = layout.Order[target], layout.Order[column]
+
+	if table.rearrangeColumns(layout) {
+		table.Select(row, target)
+	}
+}
+
+// pinColumns keeps the columns up to the given one in view while scrolling, or unpins them when
+// they already are.
+func (table *ResultsTable) pinColumns(column int) {
+	layout := table.copyLayout()
+
+	if layout.Pinned == column+1 {
+		layout.Pinned = 0
+	} else {
+		layout.Pinned = column + 1
+	}
+
+	table.setLayout(layout)
+	table.applyLayout()
+}
+
+// resizeColumn widens or narrows a column. A column without a width is as wide as its longest
+// value, so narrowing starts from there.
+func (table *ResultsTable) resizeColumn(column, offset int) {
+	records := table.GetRecords()
+	if len(records) == 0 || column >= len(records[0]) {
+		return
+	}
+
+	name := records[0][column]
+	layout := table.copyLayout()
+
+	width, ok := layout.Widths[name]
+	if !ok {
+		if offset > 0 {
+			return
+		}
+
+		width = table.columnContentWidth(column)
+	}
+
+	width += offset
+	if width < minColumnWidth {
+		width = minColumnWidth
+	}
+
+	if width >= table.columnContentWidth(column) {
+		delete(layout.Widths, name)
+	} else {
+		layout.Widths[name] = width
+	}
+
+	table.setLayout(layout)
+	table.applyLayout()
+}
+
+// autoFitColumn fits a column to its longest value.
+func (table *ResultsTable) autoFitColumn(column int) {
+	records := table.GetRecords()
+	if len(records) == 0 || column >= len(records[0]) {
+		return
+	}
+
+	layout := table.copyLayout()
+	delete(layout.Widths, records[0][column])
+
+	table.setLayout(layout)
+	table.applyLayout()
+}
+
+// columnContentWidth returns the width of the longest value of a column.
+func (table *ResultsTable) columnContentWidth(column int) int {
+	width := 0
+
+	for row := 0; row < table.GetRowCount(); row++ {
+		if cell := table.GetCell(row, column); cell != nil {
+			if cellWidth := tview.TaggedStringWidth(cell.Text); cellWidth > width {
+				width = cellWidth
+			}
+		}
+	}
+
+	return width
+}
+
+// showColumnsList shows the columns of the records, Space shows or hides the selected one.
+func (table *ResultsTable) showColumnsList() {
+	records := table.GetRecords()
+	if len(records) == 0 {
+		return
+	}
+
+	layout := table.copyLayout()
+	hidden := map[string]bool{}
+	for _, column := range layout.Hidden {
+		hidden[column] = true
+	}
+
+	list := tview.NewList()
+	list.ShowSecondaryText(false)
+	list.SetBorder(true)
+	list.SetBorderColor(app.Styles.PrimaryTextColor)
+	list.SetMainTextColor(app.Styles.PrimaryTextColor)
+	list.SetSelectedStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
+	list.SetTitle(" Columns (Space show or hide, Esc close) ")
+
+	itemText := func(column string) string {
+		if hidden[column] {
+			return "  " + tview.Escape(column)
+		}
+
+		return "✓ " + tview.Escape(column)
+	}
+
+	for _, column := range records[0] {
+		list.AddItem(itemText(column), "", 0, nil)
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+
+		switch {
+		case command == commands.Quit || event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyEnter:
+			MainPages.RemovePage(pageNameColumnLayout)
+
+			layout.Hidden = nil
+			for _, column := range records[0] {
+				if hidden[column] {
+					layout.Hidden = append(layout.Hidden, column)
+				}
+			}
+
+			if len(layout.Hidden) == len(records[0]) {
+				table.SetError("The last visible column can't be hidden", nil)
+				return nil
+			}
+
+			App.SetFocus(table)
+			table.rearrangeColumns(layout)
+		case event.Rune() == ' ':
+			index := list.GetCurrentItem()
+			column := records[0][index]
+			hidden[column] = !hidden[column]
+			list.SetItemText(index, itemText(column), "")
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	height := len(records[0]) + 2
+	if height > 20 {
+		height = 20
+	}
+
+	MainPages.AddPage(pageNameColumnLayout, centeredModal(list, 50, height), true, true)
+}
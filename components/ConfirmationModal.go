@@ -1,6 +1,8 @@
 package components
 
 import (
+	"fmt"
+
 	"github.com/rivo/tview"
 
 	"github.com/jorgerojas26/lazysql/app"
@@ -25,3 +27,43 @@ func NewConfirmationModal(confirmationText string) *ConfirmationModal {
 		Modal: modal,
 	}
 }
+
+// TypedConfirmationModal is a confirmation for changes that can't be undone, which asks to type a
+// text like the name of the table before confirming.
+type TypedConfirmationModal struct {
+	tview.Primitive
+	Form *tview.Form
+}
+
+func NewTypedConfirmationModal(confirmationText, expectedText string, done func(confirmed bool)) *TypedConfirmationModal {
+	form := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
+	form.SetBorder(true)
+	form.SetTitle(" Are you sure? ")
+	form.SetBackgroundColor(app.Styles.PrimitiveBackgroundColor)
+
+	form.AddTextView("", confirmationText, 0, 4, false, true)
+	form.AddInputField(fmt.Sprintf("Type %s to confirm", expectedText), "", 0, nil, nil)
+
+	form.AddButton("Yes", func() {
+		if form.GetFormItem(1).(*tview.InputField).GetText() != expectedText {
+			form.SetTitle(fmt.Sprintf(" Type %s to confirm ", expectedText))
+			return
+		}
+
+		done(true)
+	})
+	form.AddButton("No", func() {
+		done(false)
+	})
+	form.SetCancelFunc(func() {
+		done(false)
+	})
+
+	// Start on the input, there is nothing to do on the text
+	form.SetFocus(1)
+
+	return &TypedConfirmationModal{
+		Primitive: centeredModal(form, 70, 13),
+		Form:      form,
+	}
+}
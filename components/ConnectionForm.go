@@ -27,6 +27,9 @@ func NewConnectionForm(connectionPages *models.ConnectionPages) *ConnectionForm
 	addForm := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
 	addForm.AddInputField("Name", "", 0, nil, nil)
 	addForm.AddInputField("URL", "", 0, nil, nil)
+	addForm.AddInputField("Group", "", 0, nil, nil)
+	addForm.AddInputField("Tags", "", 0, nil, nil)
+	addForm.AddCheckbox("Read only", false, nil)
 
 	buttonsWrapper := tview.NewFlex().SetDirection(tview.FlexColumn)
 
@@ -89,28 +92,19 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 
 			connectionString := form.GetFormItem(1).(*tview.InputField).GetText()
 
-			parsed, err := helpers.ParseConnectionString(connectionString)
+			parsedDatabaseData, err := helpers.ConnectionFromURL(connectionName, connectionString)
 			if err != nil {
 				form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
 				return event
 			}
 
+			parsedDatabaseData.Group = strings.TrimSpace(form.GetFormItemByLabel("Group").(*tview.InputField).GetText())
+			parsedDatabaseData.Tags = parseTags(form.GetFormItemByLabel("Tags").(*tview.InputField).GetText())
+			parsedDatabaseData.ReadOnly = form.GetFormItemByLabel("Read only").(*tview.Checkbox).IsChecked()
+
 			databases, _ := helpers.LoadConnections()
 			newDatabases := make([]models.Connection, len(databases))
 
-			DBName := strings.Split(parsed.Normalize(",", "NULL", 0), ",")[3]
-
-			if DBName == "NULL" {
-				DBName = ""
-			}
-
-			parsedDatabaseData := models.Connection{
-				Name:     connectionName,
-				Provider: parsed.Driver,
-				DBName:   DBName,
-				URL:      connectionString,
-			}
-
 			switch form.Action {
 			case actionNewConnection:
 
@@ -123,10 +117,10 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 
 			case actionEditConnection:
 				newDatabases = make([]models.Connection, len(databases))
-				row, _ := ConnectionListTable.GetSelection()
+				_, selectedIndex, _ := ConnectionListTable.GetSelectedConnection()
 
 				for i, database := range databases {
-					if i == row {
+					if i == selectedIndex {
 						newDatabases[i] = parsedDatabaseData
 
 						// newDatabases[i].Name = connectionName
@@ -149,6 +143,15 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 					return event
 
 				}
+
+				// An open connection was made with the old settings, it is opened again with the
+				// new ones
+				if selectedIndex >= 0 && selectedIndex < len(databases) {
+					old := databases[selectedIndex]
+					if old.URL != parsedDatabaseData.URL || old.Name != parsedDatabaseData.Name || old.ReadOnly != parsedDatabaseData.ReadOnly {
+						closeHome(old.URL, parsedDatabaseData.Name)
+					}
+				}
 			}
 
 			ConnectionListTable.SetConnections(newDatabases)
@@ -192,6 +195,21 @@ func (form *ConnectionForm) testConnection(connectionString string) {
 	App.ForceDraw()
 }
 
+// parseTags splits a comma separated list of tags, dropping the empty ones.
+func parseTags(text string) []string {
+	tags := []string{}
+
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
 func (form *ConnectionForm) SetAction(action string) {
 	form.Action = action
 }
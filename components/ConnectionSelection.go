@@ -66,6 +66,23 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 	statusText := tview.NewTextView()
 	statusText.SetBorderPadding(1, 1, 0, 0)
 
+	filterInput := tview.NewInputField()
+	filterInput.SetLabel("Search: ")
+	filterInput.SetLabelColor(app.Styles.TertiaryTextColor)
+	filterInput.SetFieldBackgroundColor(app.Styles.PrimitiveBackgroundColor)
+	filterInput.SetFieldTextColor(app.Styles.PrimaryTextColor)
+	filterInput.SetChangedFunc(func(text string) {
+		ConnectionListTable.SetFilter(text)
+	})
+	filterInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEsc {
+			filterInput.SetText("")
+		}
+
+		App.SetFocus(ConnectionListTable)
+	})
+
+	wrapper.AddItem(filterInput, 1, 0, false)
 	wrapper.AddItem(ConnectionListTable, 0, 1, true)
 	wrapper.AddItem(statusText, 4, 0, false)
 	wrapper.AddItem(buttonsWrapper, 3, 0, false)
@@ -76,14 +93,15 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 	}
 
 	wrapper.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if filterInput.HasFocus() {
+			return event
+		}
+
 		connections := ConnectionListTable.GetConnections()
 
 		command := app.Keymaps.Group(app.ConnectionGroup).Resolve(event)
 
-		if len(connections) != 0 {
-			row, _ := ConnectionListTable.GetSelection()
-			selectedConnection := connections[row]
-
+		if selectedConnection, index, ok := ConnectionListTable.GetSelectedConnection(); ok {
 			switch command {
 			case commands.Connect:
 				go cs.Connect(selectedConnection)
@@ -91,6 +109,9 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 				connectionPages.SwitchToPage(pageNameConnectionForm)
 				connectionForm.GetFormItemByLabel("Name").(*tview.InputField).SetText(selectedConnection.Name)
 				connectionForm.GetFormItemByLabel("URL").(*tview.InputField).SetText(selectedConnection.URL)
+				connectionForm.GetFormItemByLabel("Group").(*tview.InputField).SetText(selectedConnection.Group)
+				connectionForm.GetFormItemByLabel("Tags").(*tview.InputField).SetText(strings.Join(selectedConnection.Tags, ", "))
+				connectionForm.GetFormItemByLabel("Read only").(*tview.Checkbox).SetChecked(selectedConnection.ReadOnly)
 				connectionForm.StatusText.SetText("")
 
 				connectionForm.SetAction(actionEditConnection)
@@ -103,12 +124,13 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 					confirmationModal = nil
 
 					if buttonLabel == "Yes" {
-						newConnections := append(connections[:row], connections[row+1:]...)
+						newConnections := append(connections[:index], connections[index+1:]...)
 
 						err := helpers.SaveConnectionConfig(newConnections)
 						if err != nil {
 							ConnectionListTable.SetError(err.Error())
 						} else {
+							closeHome(selectedConnection.URL, "")
 							ConnectionListTable.SetConnections(newConnections)
 						}
 
@@ -126,11 +148,17 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 			connectionForm.SetAction(actionNewConnection)
 			connectionForm.GetFormItemByLabel("Name").(*tview.InputField).SetText("")
 			connectionForm.GetFormItemByLabel("URL").(*tview.InputField).SetText("")
+			connectionForm.GetFormItemByLabel("Group").(*tview.InputField).SetText("")
+			connectionForm.GetFormItemByLabel("Tags").(*tview.InputField).SetText("")
+			connectionForm.GetFormItemByLabel("Read only").(*tview.Checkbox).SetChecked(false)
 			connectionForm.StatusText.SetText("")
 			connectionPages.SwitchToPage(pageNameConnectionForm)
+		case commands.Search:
+			App.SetFocus(filterInput)
+			return nil
 		case commands.Quit:
 			if wrapper.HasFocus() {
-				app.App.Stop()
+				quitApp()
 			}
 		}
 
@@ -148,42 +176,39 @@ func (cs *ConnectionSelection) Connect(connection models.Connection) {
 		cs.StatusText.SetText("Connecting...").SetTextColor(app.Styles.TertiaryTextColor)
 		App.Draw()
 
-		var newDbDriver drivers.Driver
-
-		switch connection.Provider {
-		case drivers.DriverMySQL:
-			newDbDriver = &drivers.MySQL{}
-		case drivers.DriverPostgres:
-			newDbDriver = &drivers.Postgres{}
-		case drivers.DriverSqlite:
-			newDbDriver = &drivers.SQLite{}
-		}
-
-		err := newDbDriver.Connect(connection.URL)
+		_, err := ConnectToDatabase(connection)
 
 		if err != nil {
 			cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
-			App.Draw()
 		} else {
-			newHome := NewHomePage(connection, newDbDriver)
+			cs.StatusText.SetText("")
+		}
 
-			MainPages.AddAndSwitchToPage(connection.URL, newHome, true)
+		App.Draw()
+	}
+}
 
-			cs.StatusText.SetText("")
-			App.Draw()
+// ConnectToDatabase opens the connection and switches to a new home page for it.
+func ConnectToDatabase(connection models.Connection) (*Home, error) {
+	newDbDriver, err := drivers.Open(connection)
+	if err != nil {
+		return nil, err
+	}
 
-			selectedRow, selectedCol := ConnectionListTable.GetSelection()
-			cell := ConnectionListTable.GetCell(selectedRow, selectedCol)
-			cell.SetText(fmt.Sprintf("[green]* %s", cell.Text))
+	newHome := NewHomePage(connection, newDbDriver)
 
-			ConnectionListTable.SetCell(selectedRow, selectedCol, cell)
+	MainPages.AddAndSwitchToPage(connection.URL, newHome, true)
+	App.Draw()
 
-			MainPages.SwitchToPage(connection.URL)
-			newHome.Tree.SetCurrentNode(newHome.Tree.GetRoot())
-			newHome.Tree.Wrapper.SetTitle(fmt.Sprintf("%s (%s)", connection.Name, strings.ToUpper(connection.Provider)))
-			App.SetFocus(newHome.Tree)
-			App.Draw()
-		}
+	ConnectionListTable.Refresh()
 
-	}
+	MainPages.SwitchToPage(connection.URL)
+	newHome.Tree.SetCurrentNode(newHome.Tree.GetRoot())
+	newHome.Tree.Wrapper.SetTitle(fmt.Sprintf("%s (%s)", connection.Name, strings.ToUpper(connection.Provider)))
+	App.SetFocus(newHome.Tree)
+
+	newHome.restoreWorkspace()
+	App.Draw()
+
+	return newHome, nil
 }
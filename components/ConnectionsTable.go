@@ -1,6 +1,9 @@
 package components
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
@@ -14,7 +17,11 @@ type ConnectionsTable struct {
 	Wrapper       *tview.Flex
 	errorTextView *tview.TextView
 	error         string
+	filter        string
 	connections   []models.Connection
+	tagColors     map[string]string
+	// rowConnections maps every table row to its index in connections, group headers are -1
+	rowConnections []int
 }
 
 func NewConnectionsTable() *ConnectionsTable {
@@ -34,46 +41,162 @@ func NewConnectionsTable() *ConnectionsTable {
 
 	wrapper.AddItem(table, 0, 1, true)
 
-	connections, err := helpers.LoadConnections()
+	config, err := helpers.LoadConfig()
 
 	if err != nil {
 		table.SetError(err.Error())
 	} else {
-		table.SetConnections(connections)
+		table.tagColors = config.TagColors
+		table.SetConnections(config.Connections)
 	}
 
 	return table
 }
 
-func (ct *ConnectionsTable) AddConnection(connection models.Connection) {
-	rowCount := ct.GetRowCount()
+// render draws the connections that match the current filter, grouped by their group name.
+func (ct *ConnectionsTable) render() {
+	_, selectedIndex, _ := ct.GetSelectedConnection()
+
+	ct.Clear()
+	ct.rowConnections = []int{}
+
+	groups := []string{}
+	groupConnections := map[string][]int{}
+
+	for i, connection := range ct.connections {
+		if !ct.matchesFilter(connection) {
+			continue
+		}
+
+		if _, ok := groupConnections[connection.Group]; !ok {
+			groups = append(groups, connection.Group)
+		}
+
+		groupConnections[connection.Group] = append(groupConnections[connection.Group], i)
+	}
+
+	hasGroups := len(groups) > 1 || (len(groups) == 1 && groups[0] != "")
+
+	selectedRow := -1
+
+	for _, group := range groups {
+		if hasGroups {
+			groupName := group
+			if groupName == "" {
+				groupName = "Ungrouped"
+			}
+
+			headerCell := tview.NewTableCell(tview.Escape(groupName))
+			headerCell.SetTextColor(app.Styles.TertiaryTextColor)
+			headerCell.SetSelectable(false)
+
+			ct.SetCell(len(ct.rowConnections), 0, headerCell)
+			ct.rowConnections = append(ct.rowConnections, -1)
+		}
+
+		for _, index := range groupConnections[group] {
+			if index == selectedIndex || selectedRow == -1 {
+				selectedRow = len(ct.rowConnections)
+			}
+
+			ct.SetCellSimple(len(ct.rowConnections), 0, ct.connectionText(ct.connections[index], hasGroups))
+			ct.rowConnections = append(ct.rowConnections, index)
+		}
+	}
+
+	if selectedRow != -1 {
+		ct.Select(selectedRow, 0)
+	}
+}
+
+func (ct *ConnectionsTable) connectionText(connection models.Connection, indent bool) string {
+	text := tview.Escape(connection.Name)
+
+	if MainPages.HasPage(connection.URL) {
+		text = fmt.Sprintf("[green]* %s", text)
+	}
+
+	if indent {
+		text = "  " + text
+	}
+
+	for _, tag := range connection.Tags {
+		color := app.Styles.InverseTextColor
+
+		if tagColor, ok := helpers.TagColor(ct.tagColors, tag); ok {
+			color = tagColor
+		}
 
-	ct.SetCellSimple(rowCount, 0, connection.Name)
+		text += fmt.Sprintf(" [%s][%s[][-]", color.String(), tview.Escape(tag))
+	}
+
+	if connection.ReadOnly {
+		text += fmt.Sprintf(" [%s](read-only)[-]", app.Styles.InverseTextColor.String())
+	}
+
+	return text
+}
+
+func (ct *ConnectionsTable) matchesFilter(connection models.Connection) bool {
+	if ct.filter == "" {
+		return true
+	}
 
+	text := strings.Join(append([]string{connection.Name, connection.Group}, connection.Tags...), " ")
+
+	return helpers.FuzzyMatch(ct.filter, text)
+}
+
+func (ct *ConnectionsTable) AddConnection(connection models.Connection) {
 	ct.connections = append(ct.connections, connection)
+	ct.render()
 }
 
 func (ct *ConnectionsTable) GetConnections() []models.Connection {
 	return ct.connections
 }
 
+// GetSelectedConnection returns the selected connection and its index in the list of connections.
+func (ct *ConnectionsTable) GetSelectedConnection() (models.Connection, int, bool) {
+	row, _ := ct.GetSelection()
+
+	if row < 0 || row >= len(ct.rowConnections) || ct.rowConnections[row] == -1 {
+		return models.Connection{}, -1, false
+	}
+
+	index := ct.rowConnections[row]
+
+	return ct.connections[index], index, true
+}
+
+func (ct *ConnectionsTable) GetTagColors() map[string]string {
+	return ct.tagColors
+}
+
 func (ct *ConnectionsTable) GetError() string {
 	return ct.error
 }
 
 func (ct *ConnectionsTable) SetConnections(connections []models.Connection) {
-	ct.connections = make([]models.Connection, 0)
-
-	ct.Clear()
-
-	for _, connection := range connections {
-		ct.AddConnection(connection)
-	}
+	ct.connections = make([]models.Connection, 0, len(connections))
+	ct.connections = append(ct.connections, connections...)
 
 	ct.Select(0, 0)
+	ct.render()
 	App.ForceDraw()
 }
 
+// SetFilter shows only the connections whose name, group or tags fuzzy match the text.
+func (ct *ConnectionsTable) SetFilter(filter string) {
+	ct.filter = filter
+	ct.render()
+}
+
+// Refresh redraws the connections keeping the current selection.
+func (ct *ConnectionsTable) Refresh() {
+	ct.render()
+}
+
 func (ct *ConnectionsTable) SetError(error string) {
 	ct.error = error
 
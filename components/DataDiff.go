@@ -0,0 +1,258 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// dataDiffRowLimit is the number of rows of each kind of difference that the data diff shows,
+// the sync script has all of them.
+const dataDiffRowLimit = 1000
+
+// NewDataDiffForm returns a modal form to choose the connection, database and table that the rows
+// of a table are compared with. The connection is nil when the current one is chosen.
+func NewDataDiffForm(connections []models.Connection, database, table string, onSubmit func(connection *models.Connection, database, table string)) tview.Primitive {
+	form := newSchemaForm(fmt.Sprintf(" Compare the rows of %s with ", table))
+
+	options := []string{"This connection"}
+	for _, connection := range connections {
+		options = append(options, connection.Name)
+	}
+
+	form.AddDropDown("Connection", options, 0, nil)
+	form.AddInputField("Database", database, 0, nil, nil)
+	form.AddInputField("Table", table, 0, nil, nil)
+
+	form.AddButton("Compare", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+
+		var connection *models.Connection
+		if index, _ := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption(); index > 0 {
+			connection = &connections[index-1]
+		}
+
+		onSubmit(connection, strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText()), strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText()))
+	})
+	form.AddButton("Cancel", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+	})
+
+	return centeredModal(form, 60, 11)
+}
+
+// NewDataDiff returns a modal with the rows that are only on the left, only on the right or
+// changed. Changed rows are shown as a left and a right row with the changed values highlighted.
+func NewDataDiff(left, right string, diff models.TableDataDiff, script string) tview.Primitive {
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetBorderColor(app.Styles.PrimaryTextColor)
+	table.SetFixed(1, 1)
+	table.SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
+
+	title := fmt.Sprintf(" %d only in %s, %d only in %s, %d changed of %d rows read (y to copy the sync script, Esc to close) ", len(diff.OnlyLeft), left, len(diff.OnlyRight), right, len(diff.Changed), diff.Rows)
+	table.SetTitle(title)
+
+	table.SetCell(0, 0, tview.NewTableCell("").SetSelectable(false))
+	for i, column := range diff.Columns {
+		table.SetCell(0, i+1, tview.NewTableCell(tview.Escape(column)).SetTextColor(app.Styles.PrimaryTextColor).SetSelectable(false))
+	}
+
+	addRow := func(marker string, color tcell.Color, row, other []string) {
+		index := table.GetRowCount()
+		table.SetCell(index, 0, tview.NewTableCell(marker).SetTextColor(color))
+
+		for i, value := range row {
+			cell := tview.NewTableCell(tview.Escape(value)).SetTextColor(app.Styles.PrimaryTextColor)
+			if value == "NULL&" || value == "EMPTY&" {
+				cell.SetText(strings.Replace(value, "&", "", 1))
+				cell.SetStyle(tcell.StyleDefault.Italic(true))
+			}
+
+			if other != nil && value != other[i] {
+				cell.SetTextColor(tcell.ColorYellow)
+			}
+
+			table.SetCell(index, i+1, cell)
+		}
+	}
+
+	for i, row := range diff.OnlyLeft {
+		if i == dataDiffRowLimit {
+			break
+		}
+
+		addRow("<", tcell.ColorGreen, row, nil)
+	}
+
+	for i, row := range diff.OnlyRight {
+		if i == dataDiffRowLimit {
+			break
+		}
+
+		addRow(">", tcell.ColorRed, row, nil)
+	}
+
+	for i, changed := range diff.Changed {
+		if i == dataDiffRowLimit {
+			break
+		}
+
+		addRow("~<", tcell.ColorYellow, changed.Left, changed.Right)
+		addRow("~>", tcell.ColorYellow, changed.Right, changed.Left)
+	}
+
+	if table.GetRowCount() == 1 {
+		table.SetCell(1, 1, tview.NewTableCell("The rows are the same").SetTextColor(app.Styles.PrimaryTextColor))
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+
+		switch {
+		case command == commands.Quit || event.Key() == tcell.KeyEsc:
+			MainPages.RemovePage(pageNameDataDiff)
+		case event.Rune() == 'y':
+			if err := lib.NewClipboard().Write(script); err != nil {
+				table.SetTitle(fmt.Sprintf(" %s ", err.Error()))
+			} else {
+				table.SetTitle(" Copied the sync script to the clipboard (Esc to close) ")
+			}
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case event.Rune() == 'h':
+			return tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone)
+		case event.Rune() == 'l':
+			return tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone)
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(table, 0, 12, true).
+			AddItem(nil, 0, 1, false), 0, 12, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// showDataDiffForm asks for the table that the rows of a table are compared with.
+func (home *Home) showDataDiffForm(table *ResultsTable) {
+	connections, err := helpers.LoadConnections()
+	if err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	database := table.GetDatabaseName()
+	tableName := table.GetTableName()
+
+	form := NewDataDiffForm(connections, database, tableName, func(connection *models.Connection, rightDatabase, rightTable string) {
+		home.compareData(database, tableName, connection, rightDatabase, rightTable)
+	})
+
+	MainPages.AddPage(pageNameSchemaForm, form, true, true)
+}
+
+// compareData compares the rows of a table of this connection, the left, with a table of another
+// connection or of this one, the right. The rows are read in the background while a modal shows
+// the progress, closing it stops the comparison. Each side is read through a connection of its
+// own, closed when the comparison ends, so the switches of database of the PostgreSQL driver
+// don't close the connection of the queries of the UI.
+func (home *Home) compareData(database, table string, connection *models.Connection, rightDatabase, rightTable string) {
+	rightConnection := home.Connection
+	if connection != nil {
+		rightConnection = *connection
+	}
+
+	leftName := fmt.Sprintf("%s/%s", home.Connection.Name, table)
+	rightName := fmt.Sprintf("%s/%s", rightConnection.Name, rightTable)
+
+	progress := tview.NewTextView()
+	progress.SetBorder(true)
+	progress.SetBorderColor(app.Styles.PrimaryTextColor)
+	progress.SetTextColor(app.Styles.PrimaryTextColor)
+	progress.SetTitle(" Comparing rows (Esc to cancel) ")
+	progress.SetText(fmt.Sprintf("Comparing %s with %s...", leftName, rightName))
+
+	var cancelled atomic.Bool
+	progress.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			cancelled.Store(true)
+			MainPages.RemovePage(pageNameDataDiff)
+			return nil
+		}
+
+		return event
+	})
+
+	MainPages.AddPage(pageNameDataDiff, centeredModal(progress, 70, 3), true, true)
+
+	go func() {
+		diff, provider, err := diffTableData(home.Connection, rightConnection, database, table, rightDatabase, rightTable, func(rows int) bool {
+			App.QueueUpdateDraw(func() {
+				progress.SetText(fmt.Sprintf("Compared %d rows of %s and %s...", rows, leftName, rightName))
+			})
+
+			return !cancelled.Load()
+		})
+
+		App.QueueUpdateDraw(func() {
+			if cancelled.Load() {
+				return
+			}
+
+			MainPages.RemovePage(pageNameDataDiff)
+
+			if err != nil {
+				home.showError(err.Error())
+				return
+			}
+
+			script := drivers.DataSyncScript(provider, rightTable, diff)
+			MainPages.AddPage(pageNameDataDiff, NewDataDiff(leftName, rightName, diff, script), true, true)
+		})
+	}()
+}
+
+// diffTableData opens a driver for each connection, compares the rows of their tables and closes
+// the drivers. It returns the provider of the right side too, the dialect of the sync script.
+func diffTableData(leftConnection, rightConnection models.Connection, leftDatabase, leftTable, rightDatabase, rightTable string, progress func(rows int) bool) (models.TableDataDiff, string, error) {
+	left, err := drivers.Open(leftConnection)
+	if err != nil {
+		return models.TableDataDiff{}, "", err
+	}
+	defer left.Close()
+
+	right, err := drivers.Open(rightConnection)
+	if err != nil {
+		return models.TableDataDiff{}, "", err
+	}
+	defer right.Close()
+
+	diff, err := drivers.DiffTableData(
+		drivers.TableSource{Driver: left, Database: leftDatabase, Table: leftTable},
+		drivers.TableSource{Driver: right, Database: rightDatabase, Table: rightTable},
+		drivers.DataDiffChunkSize,
+		progress,
+	)
+
+	return diff, right.GetProvider(), err
+}
@@ -0,0 +1,63 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+)
+
+// NewDatabaseStats returns a modal with the statistics of a database, as returned by
+// GetDatabaseStats.
+func NewDatabaseStats(database string, stats [][]string) tview.Primitive {
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetBorderPadding(0, 0, 1, 1)
+	table.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	title := fmt.Sprintf(" Statistics of %s (Esc to close) ", database)
+	table.SetTitle(title)
+
+	width := len(title) + 4
+
+	// The header is left out, the names of the statistics are enough
+	for i, row := range stats {
+		if i == 0 {
+			continue
+		}
+
+		table.SetCell(i-1, 0, tview.NewTableCell(tview.Escape(row[0])).SetTextColor(app.Styles.SecondaryTextColor))
+		table.SetCell(i-1, 1, tview.NewTableCell(tview.Escape(row[1])).SetTextColor(app.Styles.PrimaryTextColor))
+
+		if len(row[0])+len(row[1])+8 > width {
+			width = len(row[0]) + len(row[1]) + 8
+		}
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+
+		if command == commands.Quit || event.Key() == tcell.KeyEsc {
+			MainPages.RemovePage(pageNameDatabaseStats)
+			return nil
+		}
+
+		return event
+	})
+
+	return centeredModal(table, width, len(stats)+1)
+}
+
+// showDatabaseStats shows the statistics of a database of the tree.
+func (home *Home) showDatabaseStats(database string) {
+	stats, err := home.DBDriver.GetDatabaseStats(database)
+	if err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	MainPages.AddPage(pageNameDatabaseStats, NewDatabaseStats(database, stats), true, true)
+}
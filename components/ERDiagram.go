@@ -0,0 +1,257 @@
+package components
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// ERDiagram shows the tables of a database or schema and the foreign keys between them in a
+// modal, where the tables can be selected and opened.
+type ERDiagram struct {
+	tview.Primitive
+	TextView *tview.TextView
+	name     string
+	diagram  models.Diagram
+	layout   helpers.DiagramLayout
+	// order holds the boxes from left to right and from top to bottom
+	order    []int
+	selected int
+}
+
+func NewERDiagram(name string, diagram models.Diagram, onOpen func(table string)) *ERDiagram {
+	layout := helpers.RenderDiagram(diagram)
+
+	order := make([]int, len(layout.Boxes))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := layout.Boxes[order[i]], layout.Boxes[order[j]]
+		if a.X != b.X {
+			return a.X < b.X
+		}
+
+		return a.Y < b.Y
+	})
+
+	textView := tview.NewTextView()
+	textView.SetRegions(true)
+	textView.SetWrap(false)
+	textView.SetBorder(true)
+	textView.SetBorderPadding(0, 0, 1, 1)
+	textView.SetBorderColor(app.Styles.PrimaryTextColor)
+	textView.SetTextColor(app.Styles.PrimaryTextColor)
+
+	erDiagram := &ERDiagram{
+		TextView: textView,
+		name:     name,
+		diagram:  diagram,
+		layout:   layout,
+		order:    order,
+	}
+
+	textView.SetTitle(fmt.Sprintf(" ER diagram of %s (Enter to open, d/m to copy DOT/Mermaid, D/M to save them, Esc to close) ", name))
+	textView.SetText(erDiagram.text())
+
+	if len(layout.Boxes) == 0 {
+		textView.SetText("There are no tables")
+	} else {
+		erDiagram.selectBox(0)
+	}
+
+	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+
+		switch {
+		case command == commands.Quit || event.Key() == tcell.KeyEsc:
+			MainPages.RemovePage(pageNameERDiagram)
+		case event.Key() == tcell.KeyEnter:
+			if len(layout.Boxes) > 0 {
+				MainPages.RemovePage(pageNameERDiagram)
+				onOpen(layout.Boxes[order[erDiagram.selected]].Table)
+			}
+		case event.Rune() == 'j' || event.Key() == tcell.KeyDown:
+			erDiagram.selectBox(erDiagram.selected + 1)
+		case event.Rune() == 'k' || event.Key() == tcell.KeyUp:
+			erDiagram.selectBox(erDiagram.selected - 1)
+		case event.Rune() == 'l' || event.Key() == tcell.KeyRight:
+			erDiagram.selectColumn(1)
+		case event.Rune() == 'h' || event.Key() == tcell.KeyLeft:
+			erDiagram.selectColumn(-1)
+		case event.Rune() == 'd':
+			erDiagram.copy(helpers.DiagramFormatDOT)
+		case event.Rune() == 'm':
+			erDiagram.copy(helpers.DiagramFormatMermaid)
+		case event.Rune() == 'D':
+			erDiagram.showSaveForm(helpers.DiagramFormatDOT, ".dot")
+		case event.Rune() == 'M':
+			erDiagram.showSaveForm(helpers.DiagramFormatMermaid, ".mmd")
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(textView, 0, 12, true).
+			AddItem(nil, 0, 1, false), 0, 12, true).
+		AddItem(nil, 0, 1, false)
+
+	erDiagram.Primitive = wrapper
+
+	return erDiagram
+}
+
+// text returns the lines of the diagram with a region around each part of a table box, so the
+// selected table can be highlighted.
+func (erDiagram *ERDiagram) text() string {
+	lines := make([]string, 0, len(erDiagram.layout.Lines))
+
+	for y, line := range erDiagram.layout.Lines {
+		runes := []rune(line)
+		position := 0
+
+		var text strings.Builder
+
+		for _, i := range erDiagram.order {
+			box := erDiagram.layout.Boxes[i]
+			if y < box.Y || y >= box.Y+box.Height || box.X >= len(runes) {
+				continue
+			}
+
+			end := box.X + box.Width
+			if end > len(runes) {
+				end = len(runes)
+			}
+
+			text.WriteString(tview.Escape(string(runes[position:box.X])))
+			text.WriteString(fmt.Sprintf(`["%d"]%s[""]`, i, tview.Escape(string(runes[box.X:end]))))
+			position = end
+		}
+
+		text.WriteString(tview.Escape(string(runes[position:])))
+		lines = append(lines, text.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// selectBox highlights a table by its position in the order of the boxes.
+func (erDiagram *ERDiagram) selectBox(position int) {
+	if position < 0 || position >= len(erDiagram.order) {
+		return
+	}
+
+	erDiagram.selected = position
+	erDiagram.TextView.Highlight(strconv.Itoa(erDiagram.order[position]))
+	erDiagram.TextView.ScrollToHighlight()
+}
+
+// selectColumn selects the table of the next or previous column that is closest to the selected one.
+func (erDiagram *ERDiagram) selectColumn(direction int) {
+	if len(erDiagram.order) == 0 {
+		return
+	}
+
+	current := erDiagram.layout.Boxes[erDiagram.order[erDiagram.selected]]
+	best := -1
+	bestX, bestDistance := 0, 0
+
+	for position, i := range erDiagram.order {
+		box := erDiagram.layout.Boxes[i]
+		if (direction > 0 && box.X <= current.X) || (direction < 0 && box.X >= current.X) {
+			continue
+		}
+
+		distance := box.Y - current.Y
+		if distance < 0 {
+			distance = -distance
+		}
+
+		// The closest column first, then the closest table in it
+		closerColumn := best < 0 || (direction > 0 && box.X < bestX) || (direction < 0 && box.X > bestX)
+		if closerColumn || (box.X == bestX && distance < bestDistance) {
+			best, bestX, bestDistance = position, box.X, distance
+		}
+	}
+
+	if best >= 0 {
+		erDiagram.selectBox(best)
+	}
+}
+
+// copy copies the diagram to the clipboard in the DOT or Mermaid format.
+func (erDiagram *ERDiagram) copy(format string) {
+	var buffer bytes.Buffer
+
+	err := helpers.ExportDiagram(&buffer, erDiagram.diagram, format)
+	if err == nil {
+		err = lib.NewClipboard().Write(buffer.String())
+	}
+
+	if err != nil {
+		erDiagram.TextView.SetTitle(fmt.Sprintf(" %s ", err.Error()))
+	} else {
+		erDiagram.TextView.SetTitle(fmt.Sprintf(" Copied the %s diagram to the clipboard (Esc to close) ", format))
+	}
+}
+
+// showSaveForm asks for the file the diagram is written to in the DOT or Mermaid format, which
+// also works where there is no clipboard, like over SSH.
+func (erDiagram *ERDiagram) showSaveForm(format, extension string) {
+	form := newSchemaForm(fmt.Sprintf(" Save the %s diagram ", format))
+	form.AddInputField("File", erDiagram.name+extension, 0, nil, nil)
+
+	closeForm := func() {
+		MainPages.RemovePage(pageNameDiagramFile)
+		App.SetFocus(erDiagram.TextView)
+	}
+
+	form.AddButton("Save", func() {
+		path := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		if path == "" {
+			return
+		}
+
+		closeForm()
+		erDiagram.save(path, format)
+	})
+	form.AddButton("Cancel", closeForm)
+	form.SetCancelFunc(closeForm)
+
+	MainPages.AddPage(pageNameDiagramFile, centeredModal(form, 60, 7), true, true)
+}
+
+// save writes the diagram to a file in the DOT or Mermaid format.
+func (erDiagram *ERDiagram) save(path, format string) {
+	var buffer bytes.Buffer
+
+	err := helpers.ExportDiagram(&buffer, erDiagram.diagram, format)
+	if err == nil {
+		err = os.WriteFile(path, buffer.Bytes(), 0o644)
+	}
+
+	if err != nil {
+		erDiagram.TextView.SetTitle(fmt.Sprintf(" %s ", err.Error()))
+	} else {
+		erDiagram.TextView.SetTitle(fmt.Sprintf(" Saved the %s diagram to %s (Esc to close) ", format, path))
+	}
+}
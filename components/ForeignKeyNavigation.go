@@ -0,0 +1,316 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// tableLocation is a table tab that can be reopened from the navigation history.
+type tableLocation struct {
+	database string
+	table    string
+	// where is the condition of the filter, without the WHERE keyword
+	where string
+	// conditions are the quick filters of the tab
+	conditions []models.FilterCondition
+	// sort is the sort of the records of the tab
+	sort []models.OrderBy
+	// offset is the offset of the page of the records
+	offset    int
+	name      string
+	reference string
+	// label describes the location in the list of references
+	label string
+}
+
+// newFilteredTableLocation returns the location of the rows of a table whose columns have the
+// given values, which are filtered by quick filters so the values are bound rather than quoted.
+func newFilteredTableLocation(database, table string, columns, values []string) tableLocation {
+	conditions := make([]models.FilterCondition, 0, len(columns))
+	texts := make([]string, 0, len(columns))
+	pairs := make([]string, 0, len(columns))
+	for i, column := range columns {
+		condition := models.FilterCondition{Column: column, Operator: models.FilterEqual, Value: values[i]}
+		conditions = append(conditions, condition)
+		texts = append(texts, condition.String())
+		pairs = append(pairs, fmt.Sprintf("%s=%s", column, values[i]))
+	}
+
+	return tableLocation{
+		database:   database,
+		table:      table,
+		conditions: conditions,
+		name:       fmt.Sprintf("%s (%s)", table, strings.Join(pairs, ", ")),
+		reference:  fmt.Sprintf("%s.%s WHERE %s", database, table, strings.Join(texts, " AND ")),
+		label:      fmt.Sprintf("%s (%s)", table, strings.Join(columns, ", ")),
+	}
+}
+
+// recordValues returns the values of some columns in a row of the records, which are the ones
+// saved in the database even if the cells show pending changes.
+func (table *ResultsTable) recordValues(row int, columns []string) ([]string, error) {
+	records := table.GetRecords()
+
+	if row <= 0 || row >= len(records) {
+		return nil, fmt.Errorf("the row is not saved yet")
+	}
+
+	values := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		index := -1
+		for i, name := range records[0] {
+			if name == column {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 || index >= len(records[row]) {
+			return nil, fmt.Errorf("column %s is not in the records", column)
+		}
+
+		switch value := records[row][index]; value {
+		case "NULL&":
+			return nil, fmt.Errorf("%s is NULL", column)
+		case "EMPTY&":
+			values = append(values, "")
+		default:
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// referencedLocation returns the location of the row referenced by the foreign key of a cell.
+func (table *ResultsTable) referencedLocation(row, col int) (tableLocation, error) {
+	column := table.GetColumnNameByIndex(col)
+
+	foreignKeys, err := table.DBDriver.GetReferencedForeignKeys(table.GetDatabaseName(), table.GetTableName())
+	if err != nil {
+		return tableLocation{}, err
+	}
+
+	for _, foreignKey := range foreignKeys {
+		for _, foreignKeyColumn := range foreignKey.Columns {
+			if foreignKeyColumn != column {
+				continue
+			}
+
+			values, err := table.recordValues(row, foreignKey.Columns)
+			if err != nil {
+				return tableLocation{}, err
+			}
+
+			return newFilteredTableLocation(table.GetDatabaseName(), foreignKey.ReferencedTable, foreignKey.ReferencedColumns, values), nil
+		}
+	}
+
+	return tableLocation{}, fmt.Errorf("%s is not part of a foreign key", column)
+}
+
+// referencingLocations returns the locations of the rows of other tables that reference a row,
+// one for each foreign key. Keys whose referenced columns are NULL in the row are left out.
+func (table *ResultsTable) referencingLocations(row int) ([]tableLocation, error) {
+	foreignKeys, err := table.DBDriver.GetReferencingForeignKeys(table.GetDatabaseName(), table.GetTableName())
+	if err != nil {
+		return nil, err
+	}
+
+	if row <= 0 || row >= len(table.GetRecords()) {
+		return nil, fmt.Errorf("the row is not saved yet")
+	}
+
+	locations := []tableLocation{}
+
+	for _, foreignKey := range foreignKeys {
+		values, err := table.recordValues(row, foreignKey.ReferencedColumns)
+		if err != nil {
+			continue
+		}
+
+		locations = append(locations, newFilteredTableLocation(table.GetDatabaseName(), foreignKey.Table, foreignKey.Columns, values))
+	}
+
+	return locations, nil
+}
+
+// NewReferencesList returns a modal list of the tables that reference a row. Choosing one opens
+// its rows that reference the row.
+func NewReferencesList(locations []tableLocation, onSelect func(tableLocation)) tview.Primitive {
+	list := tview.NewList()
+	list.ShowSecondaryText(false)
+	list.SetBorder(true)
+	list.SetBorderColor(app.Styles.PrimaryTextColor)
+	list.SetTitle(" Referenced by (Enter to open, Esc to close) ")
+	list.SetMainTextColor(app.Styles.PrimaryTextColor)
+	list.SetSelectedTextColor(tview.Styles.ContrastSecondaryTextColor)
+	list.SetSelectedBackgroundColor(app.Styles.SecondaryTextColor)
+
+	width := len(" Referenced by (Enter to open, Esc to close) ") + 2
+
+	for _, location := range locations {
+		list.AddItem(tview.Escape(location.label), "", 0, nil)
+
+		if len(location.label)+4 > width {
+			width = len(location.label) + 4
+		}
+	}
+
+	list.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		MainPages.RemovePage(pageNameReferences)
+		onSelect(locations[index])
+	})
+	list.SetDoneFunc(func() {
+		MainPages.RemovePage(pageNameReferences)
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+
+		return event
+	})
+
+	height := len(locations) + 2
+	if height > 20 {
+		height = 20
+	}
+
+	return centeredModal(list, width, height)
+}
+
+// foreignKeyNavigationTable returns the table of the current tab when it shows records and has
+// the focus, so a foreign key can be followed from its selected cell.
+func (home *Home) foreignKeyNavigationTable() *ResultsTable {
+	tab := home.TabbedPane.GetCurrentTab()
+	if tab == nil {
+		return nil
+	}
+
+	table := tab.Content
+
+	if table.Menu == nil || table.Menu.GetSelectedOption() != 1 || !table.HasFocus() || table.GetIsEditing() || table.GetIsFiltering() || table.GetIsLoading() {
+		return nil
+	}
+
+	return table
+}
+
+// followForeignKey opens the row referenced by the selected cell.
+func (home *Home) followForeignKey(table *ResultsTable) {
+	row, col := table.GetSelection()
+
+	location, err := table.referencedLocation(row, col)
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	home.navigate(location)
+}
+
+// showReferences lists the tables that reference the selected row.
+func (home *Home) showReferences(table *ResultsTable) {
+	row, _ := table.GetSelection()
+
+	locations, err := table.referencingLocations(row)
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	if len(locations) == 0 {
+		table.SetError(fmt.Sprintf("No foreign keys reference this row of %s", table.GetTableName()), nil)
+		return
+	}
+
+	MainPages.AddPage(pageNameReferences, NewReferencesList(locations, home.navigate), true, true)
+}
+
+// currentLocation returns the location of the current tab.
+func (home *Home) currentLocation() (tableLocation, bool) {
+	tab := home.TabbedPane.GetCurrentTab()
+	if tab == nil || tab.Content.Menu == nil {
+		return tableLocation{}, false
+	}
+
+	return tableLocation{
+		database:   tab.Content.GetDatabaseName(),
+		table:      tab.Content.GetTableName(),
+		where:      strings.TrimPrefix(tab.Content.Filter.GetCurrentFilter(), "WHERE "),
+		conditions: tab.Content.Filter.GetConditions(),
+		sort:       tab.Content.GetCurrentSort(),
+		offset:     tab.Content.Pagination.GetOffset(),
+		name:       tab.Name,
+		reference:  tab.Reference,
+	}, true
+}
+
+// navigate opens a location and adds it to the navigation history after the current tab,
+// dropping the locations that could be reached going forward.
+func (home *Home) navigate(location tableLocation) {
+	if origin, ok := home.currentLocation(); ok {
+		if len(home.history) > 0 {
+			home.history = home.history[:home.historyIndex+1]
+		}
+
+		if len(home.history) == 0 || home.history[home.historyIndex].reference != origin.reference {
+			home.history = append(home.history, origin)
+			home.historyIndex = len(home.history) - 1
+		}
+	}
+
+	if len(home.history) == 0 || home.history[home.historyIndex].reference != location.reference {
+		home.history = append(home.history, location)
+		home.historyIndex = len(home.history) - 1
+	}
+
+	home.openLocation(home.history[home.historyIndex])
+}
+
+// navigateBack opens the previous location of the history. When the current tab is not the
+// current location, because another tab was chosen, the current location is opened instead.
+func (home *Home) navigateBack() {
+	if len(home.history) == 0 {
+		return
+	}
+
+	if current, ok := home.currentLocation(); (!ok || current.reference == home.history[home.historyIndex].reference) && home.historyIndex > 0 {
+		home.historyIndex--
+	}
+
+	home.openLocation(home.history[home.historyIndex])
+}
+
+// navigateForward opens the next location of the history.
+func (home *Home) navigateForward() {
+	if home.historyIndex+1 >= len(home.history) {
+		return
+	}
+
+	home.historyIndex++
+	home.openLocation(home.history[home.historyIndex])
+}
+
+// openLocation switches to the tab of a location, reopening it when it was closed.
+func (home *Home) openLocation(location tableLocation) {
+	if tab := home.TabbedPane.GetTabByReference(location.reference); tab != nil {
+		home.TabbedPane.SwitchToTabByReference(tab.Reference)
+		home.focusRightWrapper()
+		App.ForceDraw()
+		return
+	}
+
+	home.openTableTab(location, false)
+}
@@ -0,0 +1,291 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// foreignKeyPickerLimit is the number of referenced rows the picker lists at once.
+const foreignKeyPickerLimit = 100
+
+// ForeignKeyPicker lists the rows of the table referenced by a foreign key, with their key and a
+// column that describes them, and gives the values of the chosen one for the columns of the key.
+type ForeignKeyPicker struct {
+	tview.Primitive
+	table      *ResultsTable
+	foreignKey models.ForeignKey
+	// column is the referenced column the rows are sorted by
+	column string
+	// display is the column shown next to the key, the rows are searched by both. It is known
+	// once the first rows are loaded, and empty when the table has no other column.
+	display        string
+	displayColumns map[string]string
+	loaded         bool
+	input          *tview.InputField
+	list           *tview.Table
+	records        [][]string
+	// search counts the searches, so the results of an older one are dropped
+	search   int
+	onSelect func(values map[string]string)
+	back     tview.Primitive
+}
+
+// columnForeignKey returns the foreign key a column of the table is part of, among the ones read
+// with the records.
+func (table *ResultsTable) columnForeignKey(column string) (models.ForeignKey, bool) {
+	for _, foreignKey := range table.GetReferencedForeignKeys() {
+		for _, foreignKeyColumn := range foreignKey.Columns {
+			if foreignKeyColumn == column {
+				return foreignKey, true
+			}
+		}
+	}
+
+	return models.ForeignKey{}, false
+}
+
+// setForeignKeyValues queues the update of the columns of a foreign key in a row to the values
+// of the picked row.
+func (table *ResultsTable) setForeignKeyValues(row int, foreignKey models.ForeignKey, values map[string]string) {
+	for _, column := range foreignKey.Columns {
+		value, ok := values[column]
+		col := table.GetColumnIndexByName(column)
+
+		if ok && col >= 0 {
+			table.setCellValue(row, col, value)
+		}
+	}
+
+	if table.GetShowSidebar() {
+		table.UpdateSidebar()
+	}
+}
+
+// showForeignKeyPicker opens the picker of the rows referenced by the foreign key of a column.
+// The chosen row gives its values to onSelect, by column of the key, and the focus goes back to
+// the given primitive when the picker closes.
+func (table *ResultsTable) showForeignKeyPicker(foreignKey models.ForeignKey, column string, back tview.Primitive, onSelect func(values map[string]string)) {
+	referencedColumn := foreignKey.ReferencedColumns[0]
+	for i, foreignKeyColumn := range foreignKey.Columns {
+		if foreignKeyColumn == column && i < len(foreignKey.ReferencedColumns) {
+			referencedColumn = foreignKey.ReferencedColumns[i]
+		}
+	}
+
+	input := tview.NewInputField()
+	input.SetLabel("Search ")
+	input.SetFieldBackgroundColor(app.Styles.InverseTextColor)
+	input.SetFieldTextColor(app.Styles.PrimaryTextColor)
+	input.SetLabelColor(app.Styles.PrimaryTextColor)
+	input.SetPlaceholder(fmt.Sprintf("%s contains", referencedColumn))
+
+	list := tview.NewTable()
+	list.SetSelectable(true, false)
+	list.SetFixed(1, 0)
+	list.SetBorders(true)
+	list.SetBordersColor(app.Styles.InverseTextColor)
+
+	config, _ := helpers.LoadConfig()
+
+	picker := &ForeignKeyPicker{
+		table:          table,
+		foreignKey:     foreignKey,
+		column:         referencedColumn,
+		displayColumns: config.DisplayColumns,
+		input:          input,
+		list:           list,
+		onSelect:       onSelect,
+		back:           back,
+	}
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(list, 0, 1, false)
+	content.SetBorder(true)
+	content.SetBorderPadding(0, 0, 1, 1)
+	content.SetTitle(fmt.Sprintf(" %s (Enter to choose, Esc to close) ", foreignKey.ReferencedTable))
+
+	picker.Primitive = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 12, true).
+			AddItem(nil, 0, 1, false), 0, 12, true).
+		AddItem(nil, 0, 1, false)
+
+	input.SetChangedFunc(func(text string) {
+		picker.load(text)
+	})
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			picker.close()
+		case tcell.KeyEnter, tcell.KeyTab:
+			App.SetFocus(list)
+		}
+	})
+
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyDown {
+			App.SetFocus(list)
+			return nil
+		}
+
+		return event
+	})
+
+	list.SetSelectedFunc(func(row, _ int) {
+		picker.choose(row)
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			picker.close()
+			return nil
+		case event.Rune() == '/' || event.Key() == tcell.KeyBacktab:
+			App.SetFocus(input)
+			return nil
+		}
+
+		return event
+	})
+
+	MainPages.AddPage(pageNameForeignKeyPicker, picker, true, true)
+	App.SetFocus(input)
+
+	picker.load("")
+}
+
+// load lists the referenced rows whose key or display column contains the text.
+func (picker *ForeignKeyPicker) load(text string) {
+	picker.search++
+	search := picker.search
+
+	query := models.RecordsQuery{
+		OrderBy: []models.OrderBy{{Column: picker.column}},
+		Limit:   foreignKeyPickerLimit,
+	}
+
+	if text != "" {
+		condition := models.FilterCondition{Column: picker.column, Operator: models.FilterLike, Value: "%" + text + "%"}
+		if picker.display != "" {
+			condition.OrColumns = []string{picker.display}
+		}
+
+		query.Conditions = []models.FilterCondition{condition}
+	}
+
+	go func() {
+		records, _, err := picker.table.DBDriver.GetRecords(picker.table.GetDatabaseName(), picker.foreignKey.ReferencedTable, query)
+
+		App.QueueUpdateDraw(func() {
+			if search != picker.search {
+				return
+			}
+
+			if err != nil {
+				picker.input.SetLabel(fmt.Sprintf("%s ", err.Error()))
+				return
+			}
+
+			picker.input.SetLabel("Search ")
+			picker.setRecords(records)
+		})
+	}()
+}
+
+// setRecords lists the key and the display column of the referenced rows.
+func (picker *ForeignKeyPicker) setRecords(records [][]string) {
+	picker.records = records
+	picker.list.Clear()
+
+	if len(records) == 0 {
+		return
+	}
+
+	if !picker.loaded {
+		picker.loaded = true
+		picker.display = helpers.DisplayColumn(picker.displayColumns, picker.foreignKey.ReferencedTable, records[0], picker.column)
+
+		if picker.display != "" {
+			picker.input.SetPlaceholder(fmt.Sprintf("%s or %s contains", picker.column, picker.display))
+		}
+	}
+
+	order := []int{}
+	for i, name := range records[0] {
+		if name == picker.column {
+			order = append([]int{i}, order...)
+		} else if name == picker.display {
+			order = append(order, i)
+		}
+	}
+
+	for i, record := range records {
+		for j, index := range order {
+			cell := tview.NewTableCell(record[index])
+			cell.SetExpansion(j)
+			cell.SetMaxWidth(60)
+			cell.SetTextColor(app.Styles.PrimaryTextColor)
+
+			switch {
+			case i == 0:
+				cell.SetSelectable(false)
+				cell.SetTextColor(app.Styles.TertiaryTextColor)
+			case record[index] == "NULL&" || record[index] == "EMPTY&":
+				cell.SetText(record[index][:len(record[index])-1])
+				cell.SetTextColor(app.Styles.InverseTextColor)
+			}
+
+			picker.list.SetCell(i, j, cell)
+		}
+	}
+
+	picker.list.Select(1, 0)
+	picker.list.ScrollToBeginning()
+}
+
+// choose gives the values of the row for the columns of the foreign key and closes the picker.
+func (picker *ForeignKeyPicker) choose(row int) {
+	if row <= 0 || row >= len(picker.records) {
+		return
+	}
+
+	values := map[string]string{}
+
+	for i, column := range picker.foreignKey.Columns {
+		if i >= len(picker.foreignKey.ReferencedColumns) {
+			break
+		}
+
+		for j, name := range picker.records[0] {
+			if name != picker.foreignKey.ReferencedColumns[i] {
+				continue
+			}
+
+			switch value := picker.records[row][j]; value {
+			case "NULL&":
+			case "EMPTY&":
+				values[column] = ""
+			default:
+				values[column] = value
+			}
+		}
+	}
+
+	picker.close()
+	picker.onSelect(values)
+}
+
+func (picker *ForeignKeyPicker) close() {
+	MainPages.RemovePage(pageNameForeignKeyPicker)
+	App.SetFocus(picker.back)
+}
@@ -0,0 +1,238 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+var grantColumns = []string{"On", "Object", "Privileges", "Grantable"}
+
+// grantObjectTypes lists the types of objects each provider grants privileges on, in the order
+// the grant form shows them.
+var grantObjectTypes = map[string][]string{
+	drivers.DriverMySQL:    {models.GrantObjectDatabase, models.GrantObjectTable, models.GrantObjectProcedure, models.GrantObjectFunction, models.GrantObjectGlobal, models.GrantObjectRole},
+	drivers.DriverPostgres: {models.GrantObjectTable, models.GrantObjectSchema, models.GrantObjectDatabase, models.GrantObjectRole},
+}
+
+// Grants lists the privileges of a role, new privileges can be granted and the listed ones revoked.
+type Grants struct {
+	*tview.Table
+	home   *Home
+	role   models.Role
+	grants []models.Grant
+}
+
+// NewGrants returns the list of the privileges of a role.
+func NewGrants(home *Home, role models.Role) *Grants {
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetBorderColor(app.Styles.PrimaryTextColor)
+	table.SetFixed(1, 0)
+	table.SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
+	table.SetTitle(fmt.Sprintf(" Privileges of %s (o grant, d revoke, r refresh, Esc close) ", role))
+
+	for i, column := range grantColumns {
+		table.SetCell(0, i, tview.NewTableCell(column).SetTextColor(app.Styles.PrimaryTextColor).SetSelectable(false))
+	}
+
+	grants := &Grants{
+		Table: table,
+		home:  home,
+		role:  role,
+	}
+
+	table.SetInputCapture(grants.inputCapture)
+
+	return grants
+}
+
+// Refresh lists the privileges of the role again.
+func (grants *Grants) Refresh() error {
+	list, err := grants.home.DBDriver.GetGrants(grants.role)
+	if err != nil {
+		return err
+	}
+
+	grants.grants = list
+
+	for row := grants.GetRowCount() - 1; row > 0; row-- {
+		grants.RemoveRow(row)
+	}
+
+	for i, grant := range list {
+		grantable := ""
+		if grant.Grantable {
+			grantable = "yes"
+		}
+
+		values := []string{grant.ObjectType, grant.Object, strings.Join(grant.Privileges, ", "), grantable}
+
+		for column, value := range values {
+			grants.SetCell(i+1, column, tview.NewTableCell(tview.Escape(value)).SetTextColor(app.Styles.PrimaryTextColor))
+		}
+	}
+
+	if len(list) == 0 {
+		grants.SetCell(1, 0, tview.NewTableCell("No privileges").SetTextColor(app.Styles.InverseTextColor).SetSelectable(false))
+	}
+
+	return nil
+}
+
+func (grants *Grants) selectedGrant() (models.Grant, bool) {
+	row, _ := grants.GetSelection()
+	if row < 1 || row > len(grants.grants) {
+		return models.Grant{}, false
+	}
+
+	return grants.grants[row-1], true
+}
+
+// showForm shows the form to grant privileges to the role, or to revoke the selected ones.
+func (grants *Grants) showForm(revoke bool) {
+	if grants.home.Connection.ReadOnly {
+		grants.home.showError("This connection is read-only, changes are not allowed")
+		return
+	}
+
+	grant := models.Grant{Role: grants.role}
+
+	if revoke {
+		selected, ok := grants.selectedGrant()
+		if !ok {
+			return
+		}
+
+		grant = selected
+	}
+
+	form := NewGrantForm(grants.home.DBDriver.GetProvider(), grant, revoke, func(grant models.Grant) {
+		grants.confirm(grant, revoke)
+	})
+
+	MainPages.AddPage(pageNameSchemaForm, form, true, true)
+}
+
+// confirm shows the GRANT or REVOKE statement and runs it once confirmed.
+func (grants *Grants) confirm(grant models.Grant, revoke bool) {
+	statement, err := grants.home.DBDriver.GetGrantStatement(grant, revoke)
+	if err != nil {
+		grants.home.showError(err.Error())
+		return
+	}
+
+	confirmationModal := NewConfirmationModal(fmt.Sprintf("Run the following statement?\n\n%s;", statement))
+	confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+		MainPages.RemovePage(pageNameConfirmation)
+
+		if buttonLabel != "Yes" {
+			return
+		}
+
+		if _, err := grants.home.DBDriver.ExecuteDMLStatement(statement); err != nil {
+			grants.home.showError(err.Error())
+			return
+		}
+
+		if err := grants.Refresh(); err != nil {
+			grants.home.showError(err.Error())
+		}
+	})
+
+	MainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+}
+
+func (grants *Grants) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+
+	switch {
+	case command == commands.Quit || event.Key() == tcell.KeyEsc:
+		MainPages.RemovePage(pageNameGrants)
+	case event.Rune() == 'o':
+		grants.showForm(false)
+	case event.Rune() == 'd':
+		grants.showForm(true)
+	case event.Rune() == 'r':
+		if err := grants.Refresh(); err != nil {
+			grants.home.showError(err.Error())
+		}
+	case event.Rune() == 'j':
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case event.Rune() == 'k':
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	default:
+		return event
+	}
+
+	return nil
+}
+
+// NewGrantForm returns a modal form to grant privileges to a role, or to revoke them, starting with
+// the values of a grant.
+func NewGrantForm(provider string, grant models.Grant, revoke bool, onSubmit func(grant models.Grant)) tview.Primitive {
+	title := fmt.Sprintf(" Grant privileges to %s ", grant.Role)
+	action := "Grant"
+	if revoke {
+		title = fmt.Sprintf(" Revoke privileges of %s ", grant.Role)
+		action = "Revoke"
+	}
+
+	form := newSchemaForm(title)
+
+	objectTypes := grantObjectTypes[provider]
+	selected := 0
+	for i, objectType := range objectTypes {
+		if objectType == grant.ObjectType {
+			selected = i
+		}
+	}
+
+	form.AddDropDown("On", objectTypes, selected, nil)
+	form.AddInputField("Object", grant.Object, 0, nil, nil)
+	form.AddInputField("Privileges", strings.Join(grant.Privileges, ", "), 0, nil, nil)
+	if !revoke {
+		form.AddCheckbox("With grant option", grant.Grantable, nil)
+	}
+
+	form.AddButton(action, func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+
+		_, grant.ObjectType = form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		grant.Object = strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		grant.Privileges = drivers.SplitPrivileges(form.GetFormItem(2).(*tview.InputField).GetText())
+		grant.Grantable = !revoke && form.GetFormItem(3).(*tview.Checkbox).IsChecked()
+
+		onSubmit(grant)
+	})
+	form.AddButton("Cancel", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+	})
+
+	return centeredModal(form, 70, 13)
+}
+
+// showGrants shows the privileges of a role chosen in the tree.
+func (home *Home) showGrants(role models.Role) {
+	grants := NewGrants(home, role)
+	if err := grants.Refresh(); err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	MainPages.AddPage(pageNameGrants, tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(grants, 0, 12, true).
+			AddItem(nil, 0, 1, false), 0, 12, true).
+		AddItem(nil, 0, 1, false), true, true)
+}
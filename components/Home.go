@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -9,9 +10,14 @@ import (
 	"github.com/jorgerojas26/lazysql/app"
 	"github.com/jorgerojas26/lazysql/commands"
 	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
 	"github.com/jorgerojas26/lazysql/models"
 )
 
+// homes holds the home page of every open connection.
+var homes []*Home
+
 type Home struct {
 	*tview.Flex
 	Tree            *Tree
@@ -21,8 +27,12 @@ type Home struct {
 	HelpStatus      HelpStatus
 	HelpModal       *HelpModal
 	DBDriver        drivers.Driver
+	Connection      models.Connection
 	FocusedWrapper  string
 	ListOfDbChanges []models.DbDmlChange
+	// history holds the tabs visited following foreign keys, historyIndex is the current one
+	history      []tableLocation
+	historyIndex int
 }
 
 func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
@@ -43,8 +53,11 @@ func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
 		HelpModal:       NewHelpModal(),
 		ListOfDbChanges: []models.DbDmlChange{},
 		DBDriver:        dbdriver,
+		Connection:      connection,
 	}
 
+	homes = append(homes, home)
+
 	go home.subscribeToTreeChanges()
 
 	leftWrapper.SetBorderColor(app.Styles.InverseTextColor)
@@ -61,6 +74,14 @@ func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
 	maincontent.AddItem(rightWrapper, 0, 5, false)
 
 	home.AddItem(maincontent, 0, 1, false)
+
+	if tag, color, ok := helpers.EnvironmentColor(connection, ConnectionListTable.GetTagColors()); ok {
+		home.SetBorder(true)
+		home.SetBorderColor(color)
+		home.SetTitle(fmt.Sprintf(" %s ", strings.ToUpper(tag)))
+		home.SetTitleColor(color)
+		tabbedPane.SetHeaderColor(color)
+	}
 	// home.AddItem(home.HelpStatus, 1, 1, false)
 
 	home.SetInputCapture(home.homeInputCapture)
@@ -83,51 +104,299 @@ func (home *Home) subscribeToTreeChanges() {
 	for stateChange := range ch {
 		switch stateChange.Key {
 		case eventTreeSelectedTable:
-			databaseName := home.Tree.GetSelectedDatabase()
-			tableName := stateChange.Value.(string)
+			home.openTable(home.Tree.GetSelectedDatabase(), stateChange.Value.(string), false)
+		case eventTreeSelectedObject:
+			object := stateChange.Value.(models.DatabaseObject)
+
+			if object.IsView() {
+				tableName := object.Name
+				if object.Schema != "" {
+					tableName = fmt.Sprintf("%s.%s", object.Schema, object.Name)
+				}
+
+				home.openTable(object.Database, tableName, true)
+			} else {
+				home.showObjectDefinition(object)
+			}
+		case eventTreeShowDefinition:
+			home.showObjectDefinition(stateChange.Value.(models.DatabaseObject))
+		case eventTreeTableAction:
+			action := stateChange.Value.(treeTableAction)
+			App.QueueUpdateDraw(func() {
+				home.runTableAction(action)
+			})
+		case eventTreeShowDiagram:
+			action := stateChange.Value.(treeTableAction)
+			App.QueueUpdateDraw(func() {
+				home.showDiagram(action.database, action.schema)
+			})
+		case eventTreeCompareSchema:
+			action := stateChange.Value.(treeTableAction)
+			App.QueueUpdateDraw(func() {
+				home.showSchemaDiffForm(action.database, action.schema)
+			})
+		case eventTreeShowStats:
+			action := stateChange.Value.(treeTableAction)
+			App.QueueUpdateDraw(func() {
+				home.showDatabaseStats(action.database)
+			})
+		case eventTreeSelectedRole:
+			role := stateChange.Value.(models.Role)
+			App.QueueUpdateDraw(func() {
+				home.showGrants(role)
+			})
+		case eventTreeIsFiltering:
+			isFiltering := stateChange.Value.(bool)
+			if isFiltering {
+				home.SetInputCapture(nil)
+			} else {
+				home.SetInputCapture(home.homeInputCapture)
+			}
+		}
+	}
+}
 
-			tabReference := fmt.Sprintf("%s.%s", databaseName, tableName)
+// openTable shows the records of a table or view in its tab, opening the tab if needed.
+func (home *Home) openTable(databaseName, tableName string, isView bool) {
+	home.openTableTab(tableLocation{
+		database:  databaseName,
+		table:     tableName,
+		name:      tableName,
+		reference: fmt.Sprintf("%s.%s", databaseName, tableName),
+	}, isView)
+}
 
-			tab := home.TabbedPane.GetTabByReference(tabReference)
+// openTableTab fetches the records of the tab of a location, opening the tab with the filter of
+// the location if needed.
+func (home *Home) openTableTab(location tableLocation, isView bool) {
+	tab := home.TabbedPane.GetTabByReference(location.reference)
 
-			var table *ResultsTable
+	var table *ResultsTable
 
-			if tab != nil {
-				table = tab.Content
-				home.TabbedPane.SwitchToTabByReference(tab.Reference)
-			} else {
-				table = NewResultsTable(&home.ListOfDbChanges, home.Tree, home.DBDriver).WithFilter()
-				table.SetDatabaseName(databaseName)
-				table.SetTableName(tableName)
+	if tab != nil {
+		table = tab.Content
+		home.TabbedPane.SwitchToTabByReference(tab.Reference)
+	} else {
+		table = NewResultsTable(&home.ListOfDbChanges, home.Tree, home.DBDriver).WithFilter()
+		table.SetIsReadOnly(home.Connection.ReadOnly)
+		table.SetIsView(isView)
+		table.SetDatabaseName(location.database)
+		table.SetTableName(location.table)
+
+		if location.where != "" {
+			table.Filter.SetFilter(location.where)
+		}
 
-				home.TabbedPane.AppendTab(tableName, table, tabReference)
+		table.Filter.SetConditions(location.conditions)
+		table.SetCurrentSort(location.sort)
+		table.Pagination.SetOffset(location.offset)
+		table.LoadLayout(helpers.TableLayoutKey(home.Connection.Name, location.database, location.table))
 
-			}
+		home.TabbedPane.AppendTab(location.name, table, location.reference)
 
-			results := table.FetchRecords(func() {
-				home.focusLeftWrapper()
-			})
+	}
 
-			if len(results) > 1 && !table.GetShowSidebar() { // 1 because the row 0 is the column names
-				table.ShowSidebar(true)
-			}
+	results := table.FetchRecords(func() {
+		home.focusLeftWrapper()
+	})
+
+	if len(results) > 1 && !table.GetShowSidebar() { // 1 because the row 0 is the column names
+		table.ShowSidebar(true)
+	}
+
+	if table.state.error == "" {
+		home.focusRightWrapper()
+	}
+
+	app.App.ForceDraw()
+}
+
+// showObjectDefinition shows the definition of a database object in a modal.
+func (home *Home) showObjectDefinition(object models.DatabaseObject) {
+	definition, err := home.DBDriver.GetObjectDefinition(object)
+	if err != nil {
+		definition = fmt.Sprintf("Could not get the definition of %s: %s", object.Name, err.Error())
+	}
+
+	MainPages.AddPage(pageNameObjectDefinition, NewObjectDefinition(object, definition), true, true)
+	app.App.ForceDraw()
+}
+
+// showDiagram shows the ER diagram of a database, or of a schema of it.
+func (home *Home) showDiagram(database, schema string) {
+	diagram, err := drivers.GetDiagram(home.DBDriver, database, schema)
+	if err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	name := database
+	if schema != "" {
+		name = schema
+	}
+
+	erDiagram := NewERDiagram(name, diagram, func(table string) {
+		home.openTable(database, table, false)
+	})
+
+	MainPages.AddPage(pageNameERDiagram, erDiagram, true, true)
+}
+
+// runTableAction shows the form or the confirmation of an action on a table chosen in the tree.
+func (home *Home) runTableAction(action treeTableAction) {
+	if home.Connection.ReadOnly {
+		home.showError("This connection is read-only, changes are not allowed")
+		return
+	}
+
+	change := models.SchemaChange{Database: action.database, Table: action.tableName(action.table)}
+
+	switch action.command {
+	case commands.CreateTable:
+		form := NewCreateTableForm(func(table string, columns []models.ColumnDefinition, primaryKey []string) {
+			change.Type = models.SchemaCreateTable
+			change.Table = action.tableName(table)
+			change.Columns = columns
+			change.PrimaryKey = primaryKey
+			home.confirmTableChange(change, "")
+		})
+
+		MainPages.AddPage(pageNameCreateTable, form, true, true)
+	case commands.RenameTable:
+		form := NewTableNameForm(fmt.Sprintf(" Rename %s ", action.table), action.table, false, func(name string, _ bool) {
+			change.Type = models.SchemaRenameTable
+			change.NewTable = name
+			home.confirmTableChange(change, "")
+		})
+
+		MainPages.AddPage(pageNameSchemaForm, form, true, true)
+	case commands.DuplicateTable:
+		form := NewTableNameForm(fmt.Sprintf(" Duplicate %s ", action.table), action.table+"_copy", true, func(name string, withData bool) {
+			change.Type = models.SchemaDuplicateTable
+			change.NewTable = name
+			change.WithData = withData
+			home.confirmTableChange(change, "")
+		})
+
+		MainPages.AddPage(pageNameSchemaForm, form, true, true)
+	case commands.TruncateTable:
+		change.Type = models.SchemaTruncateTable
+		home.confirmTableChange(change, "")
+	case commands.DropTable:
+		change.Type = models.SchemaDropTable
+		home.confirmTableChange(change, action.table)
+	}
+}
+
+// confirmTableChange shows the statements of a change to a table and runs them once confirmed.
+// When typedConfirmation is set, it has to be typed to confirm.
+func (home *Home) confirmTableChange(change models.SchemaChange, typedConfirmation string) {
+	statements, err := home.DBDriver.GetSchemaChangeStatements(change)
+	if err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	confirmationText := fmt.Sprintf("Run the following statements?\n\n%s;", strings.Join(statements, ";\n"))
+
+	run := func() {
+		if err := home.DBDriver.ExecuteSchemaStatements(change.Database, statements); err != nil {
+			home.showError(err.Error())
+			return
+		}
+
+		home.afterTableChange(change)
+	}
+
+	if typedConfirmation != "" {
+		confirmationModal := NewTypedConfirmationModal(confirmationText, typedConfirmation, func(confirmed bool) {
+			MainPages.RemovePage(pageNameConfirmation)
 
-			if table.state.error == "" {
-				home.focusRightWrapper()
+			if confirmed {
+				run()
 			}
+		})
 
-			app.App.ForceDraw()
-		case eventTreeIsFiltering:
-			isFiltering := stateChange.Value.(bool)
-			if isFiltering {
-				home.SetInputCapture(nil)
-			} else {
-				home.SetInputCapture(home.homeInputCapture)
+		MainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+		return
+	}
+
+	confirmationModal := NewConfirmationModal(confirmationText)
+
+	confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+		MainPages.RemovePage(pageNameConfirmation)
+		confirmationModal = nil
+
+		if buttonLabel == "Yes" {
+			run()
+		}
+	})
+
+	MainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+}
+
+// afterTableChange refreshes the tree and the tab of a table after changing it. The tab and the
+// pending changes of a renamed or dropped table are discarded, its old name no longer exists.
+func (home *Home) afterTableChange(change models.SchemaChange) {
+	if err := home.Tree.RefreshDatabase(change.Database); err != nil {
+		logger.Error(err.Error(), nil)
+	}
+
+	tab := home.tableTab(change.Database, change.Table)
+
+	switch change.Type {
+	case models.SchemaRenameTable, models.SchemaDropTable:
+		pendingChanges := []models.DbDmlChange{}
+		for _, pendingChange := range home.ListOfDbChanges {
+			if pendingChange.Table != change.Table {
+				pendingChanges = append(pendingChanges, pendingChange)
 			}
 		}
+		home.ListOfDbChanges = pendingChanges
+
+		if tab != nil {
+			home.TabbedPane.SwitchToTabByReference(tab.Reference)
+			home.removeCurrentTab()
+		}
+	case models.SchemaTruncateTable:
+		if tab != nil {
+			tab.Content.FetchRecords(nil)
+		}
 	}
 }
 
+// tableTab returns the tab of a table, if it is open.
+func (home *Home) tableTab(database, table string) *Tab {
+	tab := home.TabbedPane.state.FirstTab
+
+	for i := 0; tab != nil && i < home.TabbedPane.state.Length; i++ {
+		// SQLite tables are opened without a database name
+		isSameDatabase := tab.Content.GetDatabaseName() == database || home.DBDriver.GetProvider() == drivers.DriverSqlite
+
+		if tab.Content.Menu != nil && tab.Content.GetTableName() == table && isSameDatabase {
+			return tab
+		}
+		tab = tab.NextTab
+	}
+
+	return nil
+}
+
+// showError shows an error that doesn't belong to a tab.
+func (home *Home) showError(message string) {
+	errorModal := tview.NewModal()
+	errorModal.AddButtons([]string{"Ok"})
+	errorModal.SetText(message)
+	errorModal.SetBackgroundColor(tcell.ColorRed)
+	errorModal.SetTextColor(app.Styles.PrimaryTextColor)
+	errorModal.SetButtonStyle(tcell.StyleDefault.Foreground(app.Styles.PrimaryTextColor))
+	errorModal.SetDoneFunc(func(_ int, _ string) {
+		MainPages.RemovePage(pageNameError)
+	})
+
+	MainPages.AddPage(pageNameError, errorModal, true, true)
+}
+
 func (home *Home) focusRightWrapper() {
 	home.Tree.RemoveHighlight()
 
@@ -161,6 +430,8 @@ func (home *Home) focusTab(tab *Tab) {
 				table.RemoveHighlightTable()
 				App.Draw()
 			}()
+		} else if table.GetShowDDL() {
+			App.SetFocus(table.DDL)
 		} else {
 			table.SetInputCapture(table.tableInputCapture)
 			App.SetFocus(table)
@@ -202,6 +473,31 @@ func (home *Home) rightWrapperInputCapture(event *tcell.EventKey) *tcell.EventKe
 	command := app.Keymaps.Group(app.TableGroup).Resolve(event)
 
 	switch command {
+	case commands.FollowForeignKey:
+		if table := home.foreignKeyNavigationTable(); table != nil {
+			home.followForeignKey(table)
+			return nil
+		}
+	case commands.ShowReferences:
+		if table := home.foreignKeyNavigationTable(); table != nil {
+			home.showReferences(table)
+			return nil
+		}
+	case commands.NavigateBack:
+		if home.foreignKeyNavigationTable() != nil {
+			home.navigateBack()
+			return nil
+		}
+	case commands.NavigateForward:
+		if home.foreignKeyNavigationTable() != nil {
+			home.navigateForward()
+			return nil
+		}
+	case commands.CompareData:
+		if table := home.foreignKeyNavigationTable(); table != nil && !table.GetIsView() {
+			home.showDataDiffForm(table)
+			return nil
+		}
 	case commands.TabPrev:
 		home.focusTab(home.TabbedPane.SwitchToPreviousTab())
 		return nil
@@ -221,10 +517,25 @@ func (home *Home) rightWrapperInputCapture(event *tcell.EventKey) *tcell.EventKe
 			table := tab.Content
 
 			if !table.GetIsFiltering() && !table.GetIsEditing() && !table.GetIsLoading() {
-				home.TabbedPane.RemoveCurrentTab()
+				if table.GetIsInTransaction() {
+					confirmationModal := NewConfirmationModal("This tab has an open transaction, it will be rolled back. Close it anyway?")
+
+					confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+						MainPages.RemovePage(pageNameConfirmation)
+						confirmationModal = nil
+
+						if buttonLabel == "Yes" {
+							table.discardTransaction()
+							home.removeCurrentTab()
+						}
+					})
+
+					MainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+
+					return nil
+				}
 
-				if home.TabbedPane.GetLength() == 0 {
-					home.focusLeftWrapper()
+				if home.removeCurrentTab() {
 					return nil
 				}
 			}
@@ -280,36 +591,41 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 			home.focusRightWrapper()
 		}
 	case commands.SwitchToEditorView:
-		tab := home.TabbedPane.GetTabByName(tabNameEditor)
-
-		if tab != nil {
-			home.TabbedPane.SwitchToTabByName(tabNameEditor)
-			tab.Content.SetIsFiltering(true)
-		} else {
-			tableWithEditor := NewResultsTable(&home.ListOfDbChanges, home.Tree, home.DBDriver).WithEditor()
-			home.TabbedPane.AppendTab(tabNameEditor, tableWithEditor, tabNameEditor)
-			tableWithEditor.SetIsFiltering(true)
-		}
-		home.HelpStatus.SetStatusOnEditorView()
-		home.focusRightWrapper()
-		App.ForceDraw()
+		home.openEditor()
 	case commands.SwitchToConnectionsView:
 		if (table != nil && !table.GetIsEditing() && !table.GetIsFiltering() && !table.GetIsLoading()) || table == nil {
 			MainPages.SwitchToPage(pageNameConnections)
 		}
+	case commands.ShowActivity:
+		if (table != nil && !table.GetIsEditing() && !table.GetIsFiltering() && !table.GetIsLoading()) || table == nil {
+			home.showActivityMonitor()
+		}
 	case commands.Quit:
 		if tab != nil {
 			table := tab.Content
 
 			if !table.GetIsFiltering() && !table.GetIsEditing() {
-				App.Stop()
+				quitApp()
 			}
 		} else {
-			App.Stop()
+			quitApp()
 		}
 	case commands.Save:
+		if table != nil && table.denyIfReadOnly() {
+			break
+		}
+
 		if (len(home.ListOfDbChanges) > 0) && !table.GetIsEditing() {
-			confirmationModal := NewConfirmationModal("")
+			confirmationText := ""
+
+			for _, change := range home.ListOfDbChanges {
+				if change.FullRowMatch {
+					confirmationText = "Some tables have no primary key. Each change applies to the first row matching all of its original values, which may not be unique. Are you sure?"
+					break
+				}
+			}
+
+			confirmationModal := NewConfirmationModal(confirmationText)
 
 			confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
 				MainPages.RemovePage(pageNameConfirmation)
@@ -351,3 +667,131 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 
 	return event
 }
+
+// removeCurrentTab closes the current tab and reports whether it was the last one, in
+// which case the tree is focused.
+func (home *Home) removeCurrentTab() bool {
+	home.TabbedPane.RemoveCurrentTab()
+
+	if home.TabbedPane.GetLength() == 0 {
+		home.focusLeftWrapper()
+		return true
+	}
+
+	return false
+}
+
+// tablesInTransaction returns the tabs of every connection that have an open transaction.
+func tablesInTransaction() []*ResultsTable {
+	tables := []*ResultsTable{}
+
+	for _, home := range homes {
+		tab := home.TabbedPane.state.FirstTab
+
+		for i := 0; tab != nil && i < home.TabbedPane.state.Length; i++ {
+			if tab.Content.GetIsInTransaction() {
+				tables = append(tables, tab.Content)
+			}
+			tab = tab.NextTab
+		}
+	}
+
+	return tables
+}
+
+// quitApp stops the app, asking first when there are open transactions.
+func quitApp() {
+	tables := tablesInTransaction()
+
+	if len(tables) == 0 {
+		App.Stop()
+		return
+	}
+
+	confirmationText := "There is an open transaction, it will be rolled back. Quit anyway?"
+	if len(tables) > 1 {
+		confirmationText = fmt.Sprintf("There are %d open transactions, they will be rolled back. Quit anyway?", len(tables))
+	}
+
+	confirmationModal := NewConfirmationModal(confirmationText)
+
+	confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+		MainPages.RemovePage(pageNameConfirmation)
+		confirmationModal = nil
+
+		if buttonLabel == "Yes" {
+			for _, table := range tables {
+				table.discardTransaction()
+			}
+
+			App.Stop()
+		}
+	})
+
+	MainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+}
+
+// openEditor switches to the SQL editor tab, creating it if needed.
+func (home *Home) openEditor() *ResultsTable {
+	tab := home.TabbedPane.GetTabByName(tabNameEditor)
+
+	var tableWithEditor *ResultsTable
+
+	if tab != nil {
+		tableWithEditor = tab.Content
+		home.TabbedPane.SwitchToTabByName(tabNameEditor)
+		tableWithEditor.SetIsFiltering(true)
+	} else {
+		tableWithEditor = NewResultsTable(&home.ListOfDbChanges, home.Tree, home.DBDriver).WithEditor()
+		tableWithEditor.SetIsReadOnly(home.Connection.ReadOnly)
+		home.TabbedPane.AppendTab(tabNameEditor, tableWithEditor, tabNameEditor)
+		tableWithEditor.SetIsFiltering(true)
+	}
+	home.HelpStatus.SetStatusOnEditorView()
+	home.focusRightWrapper()
+	App.ForceDraw()
+
+	return tableWithEditor
+}
+
+// RunStartupActions selects the database, opens the table and runs the query
+// passed on the command line. Empty values are skipped.
+func (home *Home) RunStartupActions(database string, table string, query string) {
+	// Tables can be qualified by their database, database.table in MySQL and
+	// database.schema.table in PostgreSQL, which overrides the database of the connection
+	qualifiedParts := 0
+	switch home.DBDriver.GetProvider() {
+	case drivers.DriverMySQL:
+		qualifiedParts = 2
+	case drivers.DriverPostgres:
+		qualifiedParts = 3
+	}
+
+	if split := strings.SplitN(table, ".", qualifiedParts); qualifiedParts > 0 && len(split) == qualifiedParts {
+		database = split[0]
+		table = strings.Join(split[1:], ".")
+	}
+
+	if database == "" {
+		database = home.Connection.DBName
+	}
+
+	if database != "" && home.DBDriver.GetProvider() != drivers.DriverSqlite {
+		home.Tree.SelectDatabase(database)
+	}
+
+	if table != "" {
+		if home.DBDriver.GetProvider() == drivers.DriverPostgres && !strings.Contains(table, ".") {
+			table = fmt.Sprintf("public.%s", table)
+		}
+
+		// Open it directly, the tree subscriber of a new home may not be listening yet
+		home.openTable(home.Tree.GetSelectedDatabase(), table, false)
+	}
+
+	if query != "" {
+		tableWithEditor := home.openEditor()
+		tableWithEditor.Editor.SetText(query, true)
+		tableWithEditor.executeEditorQuery(query)
+	}
+}
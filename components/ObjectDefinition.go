@@ -0,0 +1,64 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// ObjectDefinition shows the read-only definition of a database object in a modal.
+type ObjectDefinition struct {
+	tview.Primitive
+	TextView *tview.TextView
+}
+
+func NewObjectDefinition(object models.DatabaseObject, definition string) *ObjectDefinition {
+	textView := tview.NewTextView()
+	textView.SetText(definition)
+	textView.SetWrap(true)
+	textView.SetBorder(true)
+	textView.SetBorderPadding(0, 0, 1, 1)
+	textView.SetBorderColor(app.Styles.PrimaryTextColor)
+	textView.SetTextColor(app.Styles.PrimaryTextColor)
+	textView.SetTitle(fmt.Sprintf(" %s %s (y to copy, Esc to close) ", object.Type, object.Name))
+
+	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+
+		switch {
+		case command == commands.Quit || event.Key() == tcell.KeyEsc:
+			MainPages.RemovePage(pageNameObjectDefinition)
+			return nil
+		case event.Rune() == 'y':
+			err := lib.NewClipboard().Write(definition)
+			if err != nil {
+				textView.SetTitle(fmt.Sprintf(" %s ", err.Error()))
+			} else {
+				textView.SetTitle(" Copied to clipboard ")
+			}
+
+			return nil
+		}
+
+		return event
+	})
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(textView, 0, 8, true).
+			AddItem(nil, 0, 1, false), 0, 8, true).
+		AddItem(nil, 0, 1, false)
+
+	return &ObjectDefinition{
+		Primitive: wrapper,
+		TextView:  textView,
+	}
+}
@@ -1,6 +1,10 @@
 package components
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
@@ -12,9 +16,12 @@ type ResultsTableFilter struct {
 	*tview.Flex
 	Input         *tview.InputField
 	Label         *tview.TextView
+	Chips         *tview.TextView
 	currentFilter string
-	subscribers   []chan models.StateChange
-	filtering     bool
+	// conditions are the quick filters added from the cells, shown as chips before the input
+	conditions  []models.FilterCondition
+	subscribers []chan models.StateChange
+	filtering   bool
 }
 
 func NewResultsFilter() *ResultsTableFilter {
@@ -22,6 +29,7 @@ func NewResultsFilter() *ResultsTableFilter {
 		Flex:  tview.NewFlex(),
 		Input: tview.NewInputField(),
 		Label: tview.NewTextView(),
+		Chips: tview.NewTextView(),
 	}
 	recordsFilter.SetBorder(true)
 	recordsFilter.SetDirection(tview.FlexRowCSS)
@@ -32,6 +40,8 @@ func NewResultsFilter() *ResultsTableFilter {
 	recordsFilter.Label.SetText("WHERE")
 	recordsFilter.Label.SetBorderPadding(0, 0, 0, 1)
 
+	recordsFilter.Chips.SetDynamicColors(true)
+
 	recordsFilter.Input.SetPlaceholder("Enter a WHERE clause to filter the results")
 	recordsFilter.Input.SetPlaceholderStyle(tcell.StyleDefault.Foreground(app.Styles.PrimaryTextColor).Background(tview.Styles.PrimitiveBackgroundColor))
 	recordsFilter.Input.SetFieldBackgroundColor(app.Styles.PrimitiveBackgroundColor)
@@ -47,13 +57,26 @@ func NewResultsFilter() *ResultsTableFilter {
 		case tcell.KeyEscape:
 			recordsFilter.currentFilter = ""
 			recordsFilter.Input.SetText("")
+			recordsFilter.SetConditions(nil)
 			recordsFilter.Publish("")
 
 		}
 	})
 	recordsFilter.Input.SetAutocompleteStyles(app.Styles.PrimitiveBackgroundColor, tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor).Background(tview.Styles.PrimitiveBackgroundColor), tcell.StyleDefault.Foreground(tview.Styles.SecondaryTextColor).Background(tview.Styles.PrimitiveBackgroundColor))
 
+	// Backspace on an empty input removes the last quick filter
+	recordsFilter.Input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if (event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2) && recordsFilter.Input.GetText() == "" && len(recordsFilter.conditions) > 0 {
+			recordsFilter.RemoveLastCondition()
+			recordsFilter.Publish(recordsFilter.currentFilter)
+			return nil
+		}
+
+		return event
+	})
+
 	recordsFilter.AddItem(recordsFilter.Label, 6, 0, false)
+	recordsFilter.AddItem(recordsFilter.Chips, 0, 0, false)
 	recordsFilter.AddItem(recordsFilter.Input, 0, 1, false)
 
 	return recordsFilter
@@ -82,6 +105,52 @@ func (filter *ResultsTableFilter) GetCurrentFilter() string {
 	return filter.currentFilter
 }
 
+// SetFilter shows a WHERE condition in the filter and applies it to the next fetch of the records.
+func (filter *ResultsTableFilter) SetFilter(condition string) {
+	filter.Input.SetText(condition)
+	filter.currentFilter = "WHERE " + condition
+}
+
+// GetConditions returns the quick filters added from the cells.
+func (filter *ResultsTableFilter) GetConditions() []models.FilterCondition {
+	return filter.conditions
+}
+
+// AddCondition adds a quick filter after the others, unless it is already there.
+func (filter *ResultsTableFilter) AddCondition(condition models.FilterCondition) {
+	for _, current := range filter.conditions {
+		if reflect.DeepEqual(current, condition) {
+			return
+		}
+	}
+
+	filter.SetConditions(append(filter.conditions[:len(filter.conditions):len(filter.conditions)], condition))
+}
+
+// RemoveLastCondition removes the quick filter that was added last.
+func (filter *ResultsTableFilter) RemoveLastCondition() {
+	if len(filter.conditions) > 0 {
+		filter.SetConditions(filter.conditions[:len(filter.conditions)-1])
+	}
+}
+
+// SetConditions replaces the quick filters and their chips.
+func (filter *ResultsTableFilter) SetConditions(conditions []models.FilterCondition) {
+	filter.conditions = conditions
+
+	chips := make([]string, len(conditions))
+	width := 0
+
+	for i, condition := range conditions {
+		text := condition.String()
+		chips[i] = fmt.Sprintf("[black:%s] %s [-:-]", app.Styles.SecondaryTextColor.Name(), tview.Escape(text))
+		width += len(text) + 3
+	}
+
+	filter.Chips.SetText(strings.Join(chips, " "))
+	filter.ResizeItem(filter.Chips, width, 0)
+}
+
 func (filter *ResultsTableFilter) SetIsFiltering(filtering bool) {
 	filter.filtering = filtering
 }
@@ -2,6 +2,8 @@ package components
 
 import (
 	"fmt"
+	"reflect"
+	"runtime"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -17,22 +19,42 @@ import (
 	"github.com/jorgerojas26/lazysql/models"
 )
 
+// maxSortColumns is the number of columns the records can be sorted by at once.
+const maxSortColumns = 3
+
 type ResultsTableState struct {
 	listOfDbChanges       *[]models.DbDmlChange
 	error                 string
-	currentSort           string
+	currentSort           []models.OrderBy
 	databaseName          string
 	tableName             string
 	primaryKeyColumnNames []string
 	columns               [][]string
 	constraints           [][]string
 	foreignKeys           [][]string
+	// referencedForeignKeys are the foreign keys of the table, read with the records for the
+	// foreign key picker
+	referencedForeignKeys []models.ForeignKey
 	indexes               [][]string
+	ddl                   string
 	records               [][]string
-	isEditing             bool
-	isFiltering           bool
-	isLoading             bool
-	showSidebar           bool
+	// rowIDs are the identities of the records of a table without a key, by row, when the
+	// driver has a RowIDColumn
+	rowIDs         []string
+	transaction    drivers.Transaction
+	isEditing      bool
+	isFiltering    bool
+	isFullRowMatch bool
+	isLoading      bool
+	isReadOnly     bool
+	isView         bool
+	showSidebar    bool
+	// content holds the cells, showing only the visible columns of the layout
+	content *layoutContent
+	// layoutKey is the key of the layout in the state file, layouts without one aren't saved
+	layoutKey      string
+	layout         models.TableLayout
+	visibleColumns int
 }
 
 type ResultsTable struct {
@@ -41,6 +63,8 @@ type ResultsTable struct {
 	Page        *tview.Pages
 	Wrapper     *tview.Flex
 	Menu        *ResultsTableMenu
+	DDL         *tview.TextView
+	MenuPages   *tview.Pages
 	Filter      *ResultsTableFilter
 	Error       *tview.Modal
 	Loading     *tview.Modal
@@ -64,6 +88,7 @@ func NewResultsTable(listOfDbChanges *[]models.DbDmlChange, tree *Tree, dbdriver
 		isLoading:       false,
 		listOfDbChanges: listOfDbChanges,
 		showSidebar:     false,
+		content:         newLayoutContent(),
 	}
 
 	wrapper := tview.NewFlex()
@@ -105,6 +130,7 @@ func NewResultsTable(listOfDbChanges *[]models.DbDmlChange, tree *Tree, dbdriver
 		Sidebar:    sidebar,
 	}
 
+	table.SetContent(state.content)
 	table.SetSelectable(true, true)
 	table.SetBorders(true)
 	table.SetFixed(1, 0)
@@ -129,12 +155,27 @@ func (table *ResultsTable) WithFilter() *ResultsTable {
 	menu := NewResultsTableMenu()
 	filter := NewResultsFilter()
 
+	ddl := tview.NewTextView()
+	ddl.SetDynamicColors(true)
+	ddl.SetWrap(true)
+	ddl.SetBorder(true)
+	ddl.SetBorderPadding(0, 0, 1, 1)
+	ddl.SetTextColor(app.Styles.PrimaryTextColor)
+	ddl.SetInputCapture(table.ddlInputCapture)
+
+	// The DDL replaces the table instead of being shown in it
+	menuPages := tview.NewPages()
+	menuPages.AddPage(pageNameTableRecords, table, true, true)
+	menuPages.AddPage(pageNameTableDDL, ddl, true, false)
+
 	table.Menu = menu
 	table.Filter = filter
+	table.DDL = ddl
+	table.MenuPages = menuPages
 
 	table.Wrapper.AddItem(menu.Flex, 3, 0, false)
 	table.Wrapper.AddItem(filter.Flex, 3, 0, false)
-	table.Wrapper.AddItem(table, 0, 1, true)
+	table.Wrapper.AddItem(menuPages, 0, 1, true)
 	table.Wrapper.AddItem(table.Pagination, 3, 0, false)
 
 	go table.subscribeToFilterChanges()
@@ -215,6 +256,11 @@ func (table *ResultsTable) subscribeToSidebarChanges() {
 			table.SetInputCapture(table.tableInputCapture)
 			table.SetIsEditing(false)
 
+			if table.denyIfReadOnly() {
+				table.UpdateSidebar()
+				continue
+			}
+
 			row, _ := table.GetSelection()
 			changedColumnIndex := table.GetColumnIndexByName(params.ColumnName)
 			tableCell := table.GetCell(row, changedColumnIndex)
@@ -327,17 +373,21 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 
 	command := app.Keymaps.Group(app.TableGroup).Resolve(event)
 
-	menuCommands := []commands.Command{commands.RecordsMenu, commands.ColumnsMenu, commands.ConstraintsMenu, commands.ForeignKeysMenu, commands.IndexesMenu, commands.Refresh}
+	menuCommands := []commands.Command{commands.RecordsMenu, commands.ColumnsMenu, commands.ConstraintsMenu, commands.ForeignKeysMenu, commands.IndexesMenu, commands.DDLMenu, commands.StatsMenu, commands.Refresh}
 
 	if helpers.ContainsCommand(menuCommands, command) {
 		table.Select(1, 0)
+
+		if command != commands.DDLMenu {
+			table.HideDDL()
+		}
 	}
 
 	if table.Menu != nil {
 		switch command {
 		case commands.RecordsMenu:
 			table.Menu.SetSelectedOption(1)
-			table.UpdateRows(table.GetRecords())
+			table.showRecords()
 			table.AddInsertedRows()
 		case commands.ColumnsMenu:
 			table.Menu.SetSelectedOption(2)
@@ -351,6 +401,10 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 		case commands.IndexesMenu:
 			table.Menu.SetSelectedOption(5)
 			table.UpdateRows(table.GetIndexes())
+		case commands.DDLMenu:
+			table.ShowDDL()
+		case commands.StatsMenu:
+			table.ShowStats()
 		case commands.Refresh:
 			if table.Loading != nil {
 				app.App.SetFocus(table.Loading)
@@ -364,11 +418,33 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 
 	switch command {
 	case commands.AppendNewRow:
-		if table.Menu.GetSelectedOption() == 1 {
+		if table.Menu == nil || table.denyIfReadOnly() {
+			break
+		}
+
+		switch table.Menu.GetSelectedOption() {
+		case 1:
 			table.appendNewRow()
+		case 2:
+			table.showColumnForm(models.SchemaAddColumn, models.ColumnDefinition{Nullable: true})
+		case 5:
+			table.showIndexForm()
 		}
+	case commands.AppendNewRowForm:
+		table.showRowForm(-1)
 	case commands.Search:
 		table.search()
+	case commands.ShowColumns:
+		if table.isShowingTableRecords() {
+			table.showColumnsList()
+		}
+	case commands.RemoveFilter:
+		// Removing a quick filter works when it left no rows too
+		if table.Filter != nil && len(table.Filter.GetConditions()) > 0 {
+			table.Filter.RemoveLastCondition()
+			table.Pagination.SetOffset(0)
+			table.FetchRecords(nil)
+		}
 	}
 
 	if rowCount == 1 || colCount == 0 {
@@ -376,11 +452,49 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 	}
 
 	if command == commands.Edit {
+		if table.denyIfReadOnly() {
+			return nil
+		}
+
+		if table.Menu != nil && table.Menu.GetSelectedOption() == 2 {
+			table.showColumnForm(models.SchemaAlterColumn, drivers.ColumnDefinitionFromRow(table.DBDriver.GetProvider(), table.GetColumns(), selectedRowIndex))
+			return nil
+		}
+
+		// Only the records can be edited cell by cell
+		if table.Menu != nil && table.Menu.GetSelectedOption() != 1 {
+			return nil
+		}
+
 		table.StartEditingCell(selectedRowIndex, selectedColumnIndex, func(_ string, _, _ int) {
 			if table.GetShowSidebar() {
 				table.UpdateSidebar()
 			}
 		})
+	} else if command == commands.PickForeignKey {
+		if table.denyIfReadOnly() {
+			return nil
+		}
+
+		// Only the records have foreign key values
+		if table.Menu == nil || table.Menu.GetSelectedOption() != 1 {
+			return nil
+		}
+
+		column := table.GetColumnNameByIndex(selectedColumnIndex)
+
+		if foreignKey, ok := table.columnForeignKey(column); ok {
+			table.showForeignKeyPicker(foreignKey, column, table, func(values map[string]string) {
+				table.setForeignKeyValues(selectedRowIndex, foreignKey, values)
+			})
+		}
+	} else if command == commands.EditRowForm {
+		table.showRowForm(selectedRowIndex)
+	} else if command == commands.ViewCell {
+		table.showCellViewer(selectedRowIndex, selectedColumnIndex)
+	} else if command == commands.OpenInExternalEditor && runtime.GOOS == "linux" {
+		// ----- THIS IS A LINUX-ONLY FEATURE, for now
+		table.editCellInExternalEditor(selectedRowIndex, selectedColumnIndex)
 	} else if command == commands.GotoNext {
 		if selectedColumnIndex+1 < colCount {
 			table.Select(selectedRowIndex, selectedColumnIndex+1)
@@ -410,7 +524,13 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 			go table.Select(selectedRowIndex-7, selectedColumnIndex)
 		}
 	} else if command == commands.Delete {
-		if table.Menu.GetSelectedOption() == 1 {
+		if table.Menu != nil && table.Menu.GetSelectedOption() == 2 && !table.denyIfReadOnly() {
+			column := drivers.ColumnDefinitionFromRow(table.DBDriver.GetProvider(), table.GetColumns(), selectedRowIndex)
+			table.confirmSchemaChange(models.SchemaChange{Type: models.SchemaDropColumn, Column: column})
+		} else if table.Menu != nil && table.Menu.GetSelectedOption() == 5 && !table.denyIfReadOnly() {
+			index := models.IndexDefinition{Name: drivers.TableColumnValue(table.GetIndexes(), selectedRowIndex, "Key_name", "index_name", "name")}
+			table.confirmSchemaChange(models.SchemaChange{Type: models.SchemaDropIndex, Index: index})
+		} else if table.Menu.GetSelectedOption() == 1 && !table.denyIfReadOnly() {
 			isAnInsertedRow := false
 			indexOfInsertedRow := -1
 
@@ -439,6 +559,10 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 
 		}
 	} else if command == commands.SetValue {
+		if table.denyIfReadOnly() {
+			return nil
+		}
+
 		table.SetIsEditing(true)
 		table.SetInputCapture(nil)
 
@@ -456,6 +580,39 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 		})
 
 		list.Show(x, y, 30)
+	} else if command == commands.FilterByValue || command == commands.ExcludeValue || command == commands.FilterNull || command == commands.FilterNotNull {
+		// Quick filters only apply to the records
+		if table.Menu != nil && table.Menu.GetSelectedOption() == 1 {
+			operator := map[commands.Command]string{
+				commands.FilterByValue: models.FilterEqual,
+				commands.ExcludeValue:  models.FilterNotEqual,
+				commands.FilterNull:    models.FilterIsNull,
+				commands.FilterNotNull: models.FilterIsNotNull,
+			}[command]
+
+			table.addQuickFilter(operator, selectedRowIndex, selectedColumnIndex)
+		}
+	} else if helpers.ContainsCommand([]commands.Command{commands.HideColumn, commands.MoveColumnLeft, commands.MoveColumnRight, commands.PinColumns, commands.WidenColumn, commands.NarrowColumn, commands.AutoFitColumn}, command) {
+		if !table.isShowingTableRecords() {
+			return nil
+		}
+
+		switch command {
+		case commands.HideColumn:
+			table.hideColumn(selectedRowIndex, selectedColumnIndex)
+		case commands.MoveColumnLeft:
+			table.moveColumn(selectedRowIndex, selectedColumnIndex, -1)
+		case commands.MoveColumnRight:
+			table.moveColumn(selectedRowIndex, selectedColumnIndex, 1)
+		case commands.PinColumns:
+			table.pinColumns(selectedColumnIndex)
+		case commands.WidenColumn:
+			table.resizeColumn(selectedColumnIndex, columnWidthStep)
+		case commands.NarrowColumn:
+			table.resizeColumn(selectedColumnIndex, -columnWidthStep)
+		case commands.AutoFitColumn:
+			table.autoFitColumn(selectedColumnIndex)
+		}
 	} else if command == commands.ToggleSidebar {
 		table.ShowSidebar(!table.GetShowSidebar())
 	} else if command == commands.FocusSidebar {
@@ -466,14 +623,24 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 
 	if len(table.GetRecords()) > 0 {
 		switch command {
-		case commands.SortDesc:
-			currentColumnName := table.GetColumnNameByIndex(selectedColumnIndex)
-			table.Pagination.SetOffset(0)
-			table.SetSortedBy(currentColumnName, "DESC")
-		case commands.SortAsc:
+		case commands.SortDesc, commands.SortAsc, commands.AddSortDesc, commands.AddSortAsc:
 			currentColumnName := table.GetColumnNameByIndex(selectedColumnIndex)
+			direction := "ASC"
+			if command == commands.SortDesc || command == commands.AddSortDesc {
+				direction = "DESC"
+			}
+
 			table.Pagination.SetOffset(0)
-			table.SetSortedBy(currentColumnName, "ASC")
+
+			if command == commands.AddSortDesc || command == commands.AddSortAsc {
+				table.AddSortedBy(currentColumnName, direction)
+			} else {
+				table.SetSortedBy(currentColumnName, direction)
+			}
+
+			if table.GetRowCount() > 1 {
+				table.Select(1, selectedColumnIndex)
+			}
 		case commands.Copy:
 			selectedCell := table.GetCell(selectedRowIndex, selectedColumnIndex)
 
@@ -492,7 +659,77 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 	return event
 }
 
+func (table *ResultsTable) ddlInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	command := app.Keymaps.Group(app.TableGroup).Resolve(event)
+
+	switch command {
+	case commands.RecordsMenu, commands.ColumnsMenu, commands.ConstraintsMenu, commands.ForeignKeysMenu, commands.IndexesMenu, commands.StatsMenu, commands.Refresh:
+		return table.tableInputCapture(event)
+	case commands.Copy:
+		err := lib.NewClipboard().Write(table.state.ddl)
+		if err != nil {
+			table.SetError(err.Error(), nil)
+		}
+
+		return nil
+	}
+
+	return event
+}
+
+// ShowDDL shows the DDL of the table in place of the table, loading it the first time.
+func (table *ResultsTable) ShowDDL() {
+	if table.state.ddl == "" {
+		ddl, err := table.DBDriver.GetTableDDL(table.GetDatabaseName(), table.GetTableName())
+		if err != nil {
+			table.SetError(err.Error(), nil)
+			return
+		}
+
+		table.state.ddl = ddl
+	}
+
+	table.DDL.SetText(helpers.HighlightSQL(table.state.ddl, helpers.SQLHighlightColors{
+		Keyword: app.Styles.SecondaryTextColor.String(),
+		String:  app.Styles.TertiaryTextColor.String(),
+		Number:  tcell.ColorLightSkyBlue.String(),
+		Comment: tcell.ColorGray.String(),
+	}))
+	table.DDL.ScrollToBeginning()
+
+	table.Menu.SetSelectedOption(6)
+	table.MenuPages.SwitchToPage(pageNameTableDDL)
+	App.SetFocus(table.DDL)
+}
+
+// ShowStats shows the statistics of the table, which are loaded every time because they change
+// with the rows.
+func (table *ResultsTable) ShowStats() {
+	stats, err := table.DBDriver.GetTableStats(table.GetDatabaseName(), table.GetTableName())
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	table.Menu.SetSelectedOption(7)
+	table.UpdateRows(stats)
+}
+
+// HideDDL shows the table again after ShowDDL.
+func (table *ResultsTable) HideDDL() {
+	if table.MenuPages == nil || !table.GetShowDDL() {
+		return
+	}
+
+	table.MenuPages.SwitchToPage(pageNameTableRecords)
+	App.SetFocus(table)
+}
+
 func (table *ResultsTable) UpdateRows(rows [][]string) {
+	// The layout is applied to the records only, the other tabs show every column
+	table.state.content.visibleColumns = 0
+	table.SetFixed(1, 0)
+
 	table.Clear()
 	table.AddRows(rows)
 	App.ForceDraw()
@@ -523,6 +760,10 @@ func (table *ResultsTable) RemoveHighlightTable() {
 	table.SetBordersColor(app.Styles.InverseTextColor)
 	table.SetTitleColor(app.Styles.InverseTextColor)
 	table.UpdateRowsColor(app.Styles.InverseTextColor, tview.Styles.InverseTextColor)
+
+	if table.DDL != nil {
+		table.DDL.SetBorderColor(app.Styles.InverseTextColor)
+	}
 }
 
 func (table *ResultsTable) RemoveHighlightAll() {
@@ -540,6 +781,10 @@ func (table *ResultsTable) HighlightTable() {
 	table.SetBordersColor(app.Styles.PrimaryTextColor)
 	table.SetTitleColor(app.Styles.PrimaryTextColor)
 	table.UpdateRowsColor(app.Styles.PrimaryTextColor, tview.Styles.PrimaryTextColor)
+
+	if table.DDL != nil {
+		table.DDL.SetBorderColor(app.Styles.PrimaryTextColor)
+	}
 }
 
 func (table *ResultsTable) HighlightAll() {
@@ -597,71 +842,101 @@ func (table *ResultsTable) subscribeToEditorChanges() {
 	for stateChange := range ch {
 		switch stateChange.Key {
 		case eventSQLEditorQuery:
-			query := stateChange.Value.(string)
-			if query != "" {
-				queryLower := strings.ToLower(query)
+			table.executeEditorQuery(stateChange.Value.(string))
+		case eventSQLEditorBegin:
+			table.BeginTransaction()
+		case eventSQLEditorCommit:
+			table.CommitTransaction()
+		case eventSQLEditorRollback:
+			table.RollbackTransaction()
+		case eventSQLEditorEscape:
+			table.SetIsFiltering(false)
+			App.SetFocus(table)
+			table.HighlightTable()
+			table.Editor.SetBlur()
+			table.SetInputCapture(table.tableInputCapture)
+			App.Draw()
+		}
+	}
+}
 
-				if strings.Contains(queryLower, "select") {
-					table.SetLoading(true)
-					App.Draw()
+// executeEditorQuery runs the query of the SQL editor, in the open transaction if there is one.
+func (table *ResultsTable) executeEditorQuery(query string) {
+	if query != "" {
+		queryLower := strings.ToLower(query)
 
-					rows, err := table.DBDriver.ExecuteQuery(query)
-					table.Pagination.SetTotalRecords(len(rows))
-					table.Pagination.SetLimit(len(rows))
+		// Statements are run in the open transaction, if any
+		executeQuery := table.DBDriver.ExecuteQuery
+		executeDMLStatement := table.DBDriver.ExecuteDMLStatement
 
-					if err != nil {
-						table.SetLoading(false)
-						App.Draw()
-						table.SetError(err.Error(), nil)
-					} else {
-						table.UpdateRows(rows)
-						table.SetIsFiltering(false)
-
-						if len(rows) > 1 {
-							App.SetFocus(table)
-							table.HighlightTable()
-							table.Editor.SetBlur()
-							table.SetInputCapture(table.tableInputCapture)
-							App.Draw()
-						} else if len(rows) == 1 {
-							table.SetInputCapture(nil)
-							App.SetFocus(table.Editor)
-							table.Editor.Highlight()
-							table.RemoveHighlightTable()
-							table.SetIsFiltering(true)
-							App.Draw()
-						}
-						table.SetLoading(false)
-					}
-					table.EditorPages.SwitchToPage(pageNameTableEditorTable)
-					App.Draw()
-				} else {
-					table.SetRecords([][]string{})
-					table.SetLoading(true)
-					App.Draw()
+		if transaction := table.state.transaction; transaction != nil {
+			executeQuery = transaction.ExecuteQuery
+			executeDMLStatement = transaction.ExecuteDMLStatement
+		}
 
-					result, err := table.DBDriver.ExecuteDMLStatement(query)
+		switch helpers.TransactionCommand(query) {
+		case commands.BeginTransaction:
+			table.BeginTransaction()
+		case commands.CommitTransaction:
+			table.CommitTransaction()
+		case commands.RollbackTransaction:
+			table.RollbackTransaction()
+		default:
+			if table.GetIsReadOnly() && !helpers.IsReadOnlyQuery(query) {
+				table.SetError("This connection is read-only, only SELECT statements are allowed", nil)
+			} else if strings.Contains(queryLower, "select") {
+				table.SetLoading(true)
+				App.Draw()
+
+				rows, err := executeQuery(query)
+				table.Pagination.SetTotalRecords(len(rows))
+				table.Pagination.SetLimit(len(rows))
 
-					if err != nil {
-						table.SetLoading(false)
+				if err != nil {
+					table.SetLoading(false)
+					App.Draw()
+					table.SetError(err.Error(), nil)
+				} else {
+					table.UpdateRows(rows)
+					table.SetIsFiltering(false)
+
+					if len(rows) > 1 {
+						App.SetFocus(table)
+						table.HighlightTable()
+						table.Editor.SetBlur()
+						table.SetInputCapture(table.tableInputCapture)
 						App.Draw()
-						table.SetError(err.Error(), nil)
-					} else {
-						table.SetResultsInfo(result)
-						table.SetLoading(false)
-						table.EditorPages.SwitchToPage(pageNameTableEditorResultsInfo)
+					} else if len(rows) == 1 {
+						table.SetInputCapture(nil)
 						App.SetFocus(table.Editor)
+						table.Editor.Highlight()
+						table.RemoveHighlightTable()
+						table.SetIsFiltering(true)
 						App.Draw()
 					}
+					table.SetLoading(false)
+				}
+				table.EditorPages.SwitchToPage(pageNameTableEditorTable)
+				App.Draw()
+			} else {
+				table.SetRecords([][]string{})
+				table.SetLoading(true)
+				App.Draw()
+
+				result, err := executeDMLStatement(query)
+
+				if err != nil {
+					table.SetLoading(false)
+					App.Draw()
+					table.SetError(err.Error(), nil)
+				} else {
+					table.SetResultsInfo(result)
+					table.SetLoading(false)
+					table.EditorPages.SwitchToPage(pageNameTableEditorResultsInfo)
+					App.SetFocus(table.Editor)
+					App.Draw()
 				}
 			}
-		case eventSQLEditorEscape:
-			table.SetIsFiltering(false)
-			App.SetFocus(table)
-			table.HighlightTable()
-			table.Editor.SetBlur()
-			table.SetInputCapture(table.tableInputCapture)
-			App.Draw()
 		}
 	}
 }
@@ -688,6 +963,10 @@ func (table *ResultsTable) GetForeignKeys() [][]string {
 	return table.state.foreignKeys
 }
 
+func (table *ResultsTable) GetReferencedForeignKeys() []models.ForeignKey {
+	return table.state.referencedForeignKeys
+}
+
 func (table *ResultsTable) GetTableName() string {
 	return table.state.tableName
 }
@@ -704,30 +983,33 @@ func (table *ResultsTable) GetIsEditing() bool {
 	return table.state.isEditing
 }
 
-func (table *ResultsTable) GetCurrentSort() string {
+func (table *ResultsTable) GetCurrentSort() []models.OrderBy {
 	return table.state.currentSort
 }
 
+// GetColumnNameByIndex returns the name of a column of the records, which are in the order of
+// the layout.
 func (table *ResultsTable) GetColumnNameByIndex(index int) string {
-	columns := table.GetColumns()
+	records := table.GetRecords()
 
-	for i, col := range columns {
-		if i > 0 && i == index+1 {
-			return col[0]
-		}
+	if len(records) == 0 || index < 0 || index >= len(records[0]) {
+		return ""
 	}
 
-	return ""
+	return records[0][index]
 }
 
 func (table *ResultsTable) GetColumnIndexByName(columnName string) int {
-	cols := table.GetColumns()
-	logger.Info("GetColumnIndexByName", map[string]any{"cols": cols})
+	records := table.GetRecords()
 	index := -1
 
-	for i, col := range cols {
-		if i > 0 && col[0] == columnName {
-			index = i - 1 // Because the first column is the column names
+	if len(records) == 0 {
+		return index
+	}
+
+	for i, column := range records[0] {
+		if column == columnName {
+			index = i
 			break
 		}
 	}
@@ -747,6 +1029,34 @@ func (table *ResultsTable) GetShowSidebar() bool {
 	return table.state.showSidebar
 }
 
+func (table *ResultsTable) GetIsInTransaction() bool {
+	return table.state.transaction != nil
+}
+
+// GetIsFullRowMatch reports whether rows are identified by the original value of every column
+// because the table has no primary or unique key and the driver no row identity.
+func (table *ResultsTable) GetIsFullRowMatch() bool {
+	return table.state.isFullRowMatch
+}
+
+func (table *ResultsTable) GetIsReadOnly() bool {
+	return table.state.isReadOnly
+}
+
+func (table *ResultsTable) GetShowDDL() bool {
+	if table.MenuPages == nil {
+		return false
+	}
+
+	name, _ := table.MenuPages.GetFrontPage()
+
+	return name == pageNameTableDDL
+}
+
+func (table *ResultsTable) GetIsView() bool {
+	return table.state.isView
+}
+
 func (table *ResultsTable) GetPrimaryKeyColumnNames() []string {
 	return table.state.primaryKeyColumnNames
 }
@@ -754,8 +1064,8 @@ func (table *ResultsTable) GetPrimaryKeyColumnNames() []string {
 // Setters
 
 func (table *ResultsTable) SetRecords(rows [][]string) {
-	table.state.records = rows
-	table.UpdateRows(rows)
+	table.state.records = table.arrangeColumns(rows)
+	table.showRecords()
 }
 
 func (table *ResultsTable) SetColumns(columns [][]string) {
@@ -770,6 +1080,10 @@ func (table *ResultsTable) SetForeignKeys(foreignKeys [][]string) {
 	table.state.foreignKeys = foreignKeys
 }
 
+func (table *ResultsTable) SetReferencedForeignKeys(foreignKeys []models.ForeignKey) {
+	table.state.referencedForeignKeys = foreignKeys
+}
+
 func (table *ResultsTable) SetIndexes(indexes [][]string) {
 	table.state.indexes = indexes
 }
@@ -848,51 +1162,114 @@ func (table *ResultsTable) SetIsFiltering(filtering bool) {
 	table.state.isFiltering = filtering
 }
 
-func (table *ResultsTable) SetCurrentSort(sort string) {
+func (table *ResultsTable) SetIsReadOnly(readOnly bool) {
+	table.state.isReadOnly = readOnly
+}
+
+// SetIsView marks the records as the ones of a view, which can be browsed but not changed.
+func (table *ResultsTable) SetIsView(isView bool) {
+	table.state.isView = isView
+}
+
+func (table *ResultsTable) SetCurrentSort(sort []models.OrderBy) {
 	table.state.currentSort = sort
 }
 
+// SetSortedBy sorts the records by a single column, replacing the current sort.
 func (table *ResultsTable) SetSortedBy(column string, direction string) {
-	sort := fmt.Sprintf("%s %s", column, direction)
+	sort := []models.OrderBy{{Column: column, Descending: direction == "DESC"}}
+
+	if !reflect.DeepEqual(table.GetCurrentSort(), sort) {
+		table.applySort(sort)
+	}
+}
 
-	if table.GetCurrentSort() != sort {
-		where := ""
-		if table.Filter != nil {
-			where = table.Filter.GetCurrentFilter()
+// AddSortedBy adds a column to the sort after the current ones, or changes its direction when the
+// records are already sorted by it.
+func (table *ResultsTable) AddSortedBy(column string, direction string) {
+	current := table.GetCurrentSort()
+	sort := make([]models.OrderBy, 0, len(current)+1)
+	found := false
+
+	for _, orderBy := range current {
+		if orderBy.Column == column {
+			orderBy.Descending = direction == "DESC"
+			found = true
 		}
-		table.SetLoading(true)
-		records, _, err := table.DBDriver.GetRecords(table.GetDatabaseName(), table.GetTableName(), where, sort, table.Pagination.GetOffset(), table.Pagination.GetLimit())
-		table.SetLoading(false)
 
-		if err != nil {
-			table.SetError(err.Error(), nil)
-		} else {
-			table.SetRecords(records)
-			App.ForceDraw()
+		sort = append(sort, orderBy)
+	}
+
+	if !found {
+		if len(sort) >= maxSortColumns {
+			table.SetError(fmt.Sprintf("The records can be sorted by up to %d columns", maxSortColumns), nil)
+			return
 		}
 
-		table.SetCurrentSort(sort)
+		sort = append(sort, models.OrderBy{Column: column, Descending: direction == "DESC"})
+	}
 
-		columns := table.GetColumns()
-		iconDirection := "▲"
+	if !reflect.DeepEqual(current, sort) {
+		table.applySort(sort)
+	}
+}
+
+// applySort fetches the records again sorted by the given columns. The sort is kept only once
+// they are fetched, so a failed fetch leaves the current one.
+func (table *ResultsTable) applySort(sort []models.OrderBy) {
+	query := table.recordsQuery()
+	query.OrderBy = sort
+
+	table.SetLoading(true)
+	records, _, err := table.DBDriver.GetRecords(table.GetDatabaseName(), table.GetTableName(), query)
+	table.SetLoading(false)
+
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	if query.RowID {
+		records = table.takeRowIDs(records)
+	}
 
-		if direction == "DESC" {
-			iconDirection = "▼"
+	table.SetCurrentSort(sort)
+	table.SetRecords(records)
+	App.ForceDraw()
+}
+
+// setSortIndicators marks the header of the sorted columns with their direction, and with their
+// priority when the records are sorted by more than one column.
+func (table *ResultsTable) setSortIndicators() {
+	sort := table.GetCurrentSort()
+	records := table.GetRecords()
+
+	if len(records) == 0 {
+		return
+	}
+
+	for i, column := range records[0] {
+		cell := table.GetCell(0, i)
+		if cell == nil {
+			continue
 		}
 
-		for i, col := range columns {
-			if i > 0 {
-				tableCell := tview.NewTableCell(col[0])
-				tableCell.SetSelectable(false)
-				tableCell.SetExpansion(1)
-				tableCell.SetTextColor(app.Styles.PrimaryTextColor)
+		cell.SetText(column)
 
-				if col[0] == column {
-					tableCell.SetText(fmt.Sprintf("%s %s", col[0], iconDirection))
-					table.SetCell(0, i-1, tableCell)
-				} else {
-					table.SetCell(0, i-1, tableCell)
-				}
+		for priority, orderBy := range sort {
+			if orderBy.Column != column {
+				continue
+			}
+
+			iconDirection := "▲"
+			if orderBy.Descending {
+				iconDirection = "▼"
+			}
+
+			if len(sort) > 1 {
+				cell.SetText(fmt.Sprintf("%s %s%d", column, iconDirection, priority+1))
+			} else {
+				cell.SetText(fmt.Sprintf("%s %s", column, iconDirection))
 			}
 		}
 	}
@@ -902,19 +1279,93 @@ func (table *ResultsTable) SetPrimaryKeyColumnNames(primaryKeyColumnNames []stri
 	table.state.primaryKeyColumnNames = primaryKeyColumnNames
 }
 
+func (table *ResultsTable) SetIsFullRowMatch(isFullRowMatch bool) {
+	table.state.isFullRowMatch = isFullRowMatch
+}
+
+// recordsQuery returns the query of the current page of the records, with the typed filter, the
+// quick filters and the sort.
+func (table *ResultsTable) recordsQuery() models.RecordsQuery {
+	query := models.RecordsQuery{
+		OrderBy: table.GetCurrentSort(),
+		Offset:  table.Pagination.GetOffset(),
+		Limit:   table.Pagination.GetLimit(),
+		RowID:   table.state.rowIDs != nil,
+	}
+
+	if table.Filter != nil {
+		query.Where = strings.TrimPrefix(table.Filter.GetCurrentFilter(), "WHERE ")
+		query.Conditions = table.Filter.GetConditions()
+	}
+
+	return query
+}
+
+// takeRowIDs keeps the identities of the rows, read as the first column of the records, and
+// returns the records without them.
+func (table *ResultsTable) takeRowIDs(records [][]string) [][]string {
+	table.state.rowIDs = make([]string, len(records))
+
+	for i, record := range records {
+		if len(record) > 0 {
+			table.state.rowIDs[i] = record[0]
+			records[i] = record[1:]
+		}
+	}
+
+	return records
+}
+
+// addQuickFilter filters the records by the value of the selected cell, and fetches them again
+// from the first page.
+func (table *ResultsTable) addQuickFilter(operator string, row, column int) {
+	records := table.GetRecords()
+	if table.Filter == nil || row < 1 || row >= len(records) || column >= len(records[row]) {
+		return
+	}
+
+	condition := models.FilterCondition{Column: table.GetColumnNameByIndex(column), Operator: operator, Value: records[row][column]}
+
+	// NULL cells can't be compared with =
+	if condition.Value == "NULL&" {
+		switch operator {
+		case models.FilterEqual:
+			condition.Operator = models.FilterIsNull
+		case models.FilterNotEqual:
+			condition.Operator = models.FilterIsNotNull
+		}
+	}
+
+	table.Filter.AddCondition(condition)
+	table.Pagination.SetOffset(0)
+	table.FetchRecords(nil)
+
+	if table.GetRowCount() > 1 {
+		table.Select(1, column)
+	}
+}
+
 func (table *ResultsTable) FetchRecords(onError func()) [][]string {
 	tableName := table.GetTableName()
 	databaseName := table.GetDatabaseName()
 
 	table.SetLoading(true)
 
-	where := ""
-	if table.Filter != nil {
-		where = table.Filter.GetCurrentFilter()
+	primaryKeyColumnNames, _ := table.DBDriver.GetPrimaryKeyColumnNames(databaseName, tableName)
+
+	// Without a primary key, a unique key with no nullable columns identifies the rows just as
+	// well. Otherwise the rows are read with their ctid or rowid, views have none, and on MySQL
+	// they are matched by all of their original values.
+	if len(primaryKeyColumnNames) == 0 {
+		primaryKeyColumnNames, _ = table.DBDriver.GetUniqueKeyColumnNames(databaseName, tableName)
 	}
-	sort := table.GetCurrentSort()
 
-	records, totalRecords, err := table.DBDriver.GetRecords(databaseName, tableName, where, sort, table.Pagination.GetOffset(), table.Pagination.GetLimit())
+	logger.Info("FetchRecords", map[string]any{"primaryKeyColumnNames": primaryKeyColumnNames})
+
+	query := table.recordsQuery()
+	query.RowID = len(primaryKeyColumnNames) == 0 && !table.GetIsView() && drivers.RowIDColumn(table.DBDriver.GetProvider()) != ""
+
+	records, totalRecords, err := table.DBDriver.GetRecords(databaseName, tableName, query)
 
 	if err != nil {
 		table.SetError(err.Error(), onError)
@@ -927,10 +1378,13 @@ func (table *ResultsTable) FetchRecords(onError func()) [][]string {
 		columns, _ := table.DBDriver.GetTableColumns(databaseName, tableName)
 		constraints, _ := table.DBDriver.GetConstraints(databaseName, tableName)
 		foreignKeys, _ := table.DBDriver.GetForeignKeys(databaseName, tableName)
+		referencedForeignKeys, _ := table.DBDriver.GetReferencedForeignKeys(databaseName, tableName)
 		indexes, _ := table.DBDriver.GetIndexes(databaseName, tableName)
-		primaryKeyColumnNames, _ := table.DBDriver.GetPrimaryKeyColumnNames(databaseName, tableName)
 
-		logger.Info("FetchRecords", map[string]any{"primaryKeyColumnNames": primaryKeyColumnNames})
+		table.state.rowIDs = nil
+		if query.RowID {
+			records = table.takeRowIDs(records)
+		}
 
 		if len(records) > 0 {
 			table.SetRecords(records)
@@ -939,8 +1393,11 @@ func (table *ResultsTable) FetchRecords(onError func()) [][]string {
 		table.SetColumns(columns)
 		table.SetConstraints(constraints)
 		table.SetForeignKeys(foreignKeys)
+		table.SetReferencedForeignKeys(referencedForeignKeys)
 		table.SetIndexes(indexes)
 		table.SetPrimaryKeyColumnNames(primaryKeyColumnNames)
+		table.SetIsFullRowMatch(len(primaryKeyColumnNames) == 0 && !query.RowID)
+		table.state.ddl = ""
 		table.Select(1, 0)
 
 		table.Pagination.SetTotalRecords(totalRecords)
@@ -965,16 +1422,10 @@ func (table *ResultsTable) StartEditingCell(row int, col int, callback func(newV
 
 	inputField.SetDoneFunc(func(key tcell.Key) {
 		table.SetIsEditing(false)
-		currentValue := cell.Text
 		newValue := inputField.GetText()
-		columnName := table.GetCell(0, col).Text
 
 		if key != tcell.KeyEscape {
-			cell.SetText(newValue)
-
-			if currentValue != newValue {
-				table.AppendNewChange(models.DmlUpdateType, row, col, models.CellValue{Type: models.String, Value: newValue, Column: columnName, TableColumnIndex: col, TableRowIndex: row})
-			}
+			table.setCellValue(row, col, newValue)
 
 			switch key {
 			case tcell.KeyTab:
@@ -1016,6 +1467,21 @@ func (table *ResultsTable) StartEditingCell(row int, col int, callback func(newV
 	App.SetFocus(inputField)
 }
 
+// setCellValue sets the text of the cell and queues the update of its value when it changed.
+func (table *ResultsTable) setCellValue(row, col int, newValue string) {
+	cell := table.GetCell(row, col)
+	if cell.Text == newValue {
+		return
+	}
+
+	cell.SetText(newValue)
+
+	// The header shows the sort indicators, the records have the plain name
+	columnName := table.GetColumnNameByIndex(col)
+
+	table.AppendNewChange(models.DmlUpdateType, row, col, models.CellValue{Type: models.String, Value: newValue, Column: columnName, TableColumnIndex: col, TableRowIndex: row})
+}
+
 func (table *ResultsTable) CheckIfRowIsInserted(rowID string) bool {
 	for _, dmlChange := range *table.state.listOfDbChanges {
 		if dmlChange.Type == models.DmlInsertType && dmlChange.PrimaryKeyInfo[0].Value == rowID {
@@ -1140,6 +1606,7 @@ func (table *ResultsTable) AppendNewChange(changeType models.DmlType, rowIndex i
 			Table:          tableName,
 			Values:         []models.CellValue{value},
 			PrimaryKeyInfo: rowPrimaryKeyInfo,
+			FullRowMatch:   table.GetIsFullRowMatch(),
 		}
 
 		*table.state.listOfDbChanges = append(*table.state.listOfDbChanges, newDmlChange)
@@ -1154,6 +1621,39 @@ func (table *ResultsTable) GetPrimaryKeyValue(rowIndex int) []models.PrimaryKeyI
 
 	info := []models.PrimaryKeyInfo{}
 
+	if rowIDs := table.state.rowIDs; rowIDs != nil {
+		// The inserted rows come after the records and have no identity yet
+		if rowIndex > 0 && rowIndex < len(rowIDs) {
+			info = append(info, models.PrimaryKeyInfo{Name: drivers.RowIDColumn(table.DBDriver.GetProvider()), Value: rowIDs[rowIndex]})
+		}
+
+		return info
+	}
+
+	if table.GetIsFullRowMatch() {
+		records := table.GetRecords()
+
+		if rowIndex <= 0 || rowIndex >= len(records) {
+			return info
+		}
+
+		// The records keep the original values, the cells may already show pending changes
+		for i, columnName := range records[0] {
+			value := records[rowIndex][i]
+
+			switch value {
+			case "NULL&":
+				info = append(info, models.PrimaryKeyInfo{Name: columnName, IsNull: true})
+			case "EMPTY&":
+				info = append(info, models.PrimaryKeyInfo{Name: columnName, Value: ""})
+			default:
+				info = append(info, models.PrimaryKeyInfo{Name: columnName, Value: value})
+			}
+		}
+
+		return info
+	}
+
 	for _, primaryKeyColumnName := range primaryKeyColumnNames {
 		primaryKeyValue := table.GetCell(rowIndex, table.GetColumnIndexByName(primaryKeyColumnName)).Text
 		logger.Info("GetPrimaryKeyValue", map[string]any{"primaryKeyValue": primaryKeyValue})
@@ -1174,17 +1674,42 @@ func (table *ResultsTable) SetCellColor(rowIndex int, colIndex int, color tcell.
 }
 
 func (table *ResultsTable) appendNewRow() {
+	table.StartEditingCell(table.insertRow(nil), 0, nil)
+}
+
+// insertRow queues the insert of a row with the given values by column, the columns without a
+// value take their default. It returns the index of the new row.
+func (table *ResultsTable) insertRow(values map[string]models.CellValue) int {
 	dbColumns := table.GetColumns()
 	newRowTableIndex := table.GetRowCount()
 	newRowUUID := uuid.New().String()
-	newRow := make([]models.CellValue, len(dbColumns)-1)
+	columnNames := make([]string, 0, len(dbColumns))
 
 	for i, column := range dbColumns {
 		if i != 0 { // Skip the first row because they are the column names (e.x "Field", "Type", "Null", "Key", "Default", "Extra")
-			newRow[i-1] = models.CellValue{Type: models.Default, Column: column[0], Value: "DEFAULT", TableRowIndex: newRowTableIndex, TableColumnIndex: i}
+			columnNames = append(columnNames, column[0])
 		}
 	}
 
+	// The cells of the new row follow the columns of the records, which are in the order of the layout
+	if records := table.GetRecords(); len(records) > 0 && len(records[0]) == len(columnNames) {
+		columnNames = records[0]
+	}
+
+	newRow := make([]models.CellValue, len(columnNames))
+
+	for i, name := range columnNames {
+		value, ok := values[name]
+		if !ok {
+			value = models.CellValue{Type: models.Default, Value: "DEFAULT"}
+		}
+
+		value.Column = name
+		value.TableRowIndex = newRowTableIndex
+		value.TableColumnIndex = i + 1
+		newRow[i] = value
+	}
+
 	newInsert := models.DbDmlChange{
 		Type:           models.DmlInsertType,
 		Database:       table.GetDatabaseName(),
@@ -1197,7 +1722,7 @@ func (table *ResultsTable) appendNewRow() {
 
 	table.AppendNewRow(newRow, newRowTableIndex, newRowUUID)
 
-	table.StartEditingCell(newRowTableIndex, 0, nil)
+	return newRowTableIndex
 }
 
 func (table *ResultsTable) search() {
@@ -1302,6 +1827,99 @@ func (table *ResultsTable) search() {
 	table.SetInputCapture(nil)
 }
 
+// showColumnForm opens the form to add a column, or to change the given one.
+func (table *ResultsTable) showColumnForm(changeType models.SchemaChangeType, column models.ColumnDefinition) {
+	title := " Add column "
+	if changeType == models.SchemaAlterColumn {
+		title = fmt.Sprintf(" Alter column %s ", column.Name)
+	}
+
+	form := NewColumnForm(title, "Preview", column, func(newColumn models.ColumnDefinition) {
+		table.confirmSchemaChange(models.SchemaChange{Type: changeType, Column: newColumn, OldColumn: column})
+	})
+
+	MainPages.AddPage(pageNameSchemaForm, form, true, true)
+}
+
+// showIndexForm opens the form to create an index.
+func (table *ResultsTable) showIndexForm() {
+	columns := table.GetColumns()
+
+	columnNames := []string{}
+	for row := 1; row < len(columns); row++ {
+		columnNames = append(columnNames, drivers.TableColumnValue(columns, row, "Field", "column_name", "name"))
+	}
+
+	form := NewIndexForm(columnNames, func(index models.IndexDefinition) {
+		table.confirmSchemaChange(models.SchemaChange{Type: models.SchemaCreateIndex, Index: index})
+	})
+
+	MainPages.AddPage(pageNameSchemaForm, form, true, true)
+}
+
+// confirmSchemaChange shows the statements of a schema change and runs them once confirmed,
+// then reloads the table to show its new structure.
+func (table *ResultsTable) confirmSchemaChange(change models.SchemaChange) {
+	change.Database = table.GetDatabaseName()
+	change.Table = table.GetTableName()
+
+	statements, err := table.DBDriver.GetSchemaChangeStatements(change)
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	confirmationText := fmt.Sprintf("Run the following statements?\n\n%s;", strings.Join(statements, ";\n"))
+	if table.DBDriver.GetProvider() == drivers.DriverMySQL {
+		confirmationText += "\n\nMySQL commits schema changes right away, they can't be rolled back."
+	}
+
+	confirmationModal := NewConfirmationModal(confirmationText)
+
+	confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+		MainPages.RemovePage(pageNameConfirmation)
+		confirmationModal = nil
+
+		if buttonLabel != "Yes" {
+			return
+		}
+
+		if err := table.DBDriver.ExecuteSchemaStatements(change.Database, statements); err != nil {
+			table.SetError(err.Error(), nil)
+			return
+		}
+
+		option := table.Menu.GetSelectedOption()
+
+		table.FetchRecords(nil)
+
+		// FetchRecords shows the records, go back to the tab the change was made from
+		switch option {
+		case 2:
+			table.UpdateRows(table.GetColumns())
+		case 5:
+			table.UpdateRows(table.GetIndexes())
+		}
+	})
+
+	MainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+}
+
+// denyIfReadOnly tells the user that changes are not allowed and returns true
+// when the table belongs to a read-only connection.
+func (table *ResultsTable) denyIfReadOnly() bool {
+	switch {
+	case table.GetIsReadOnly():
+		table.SetError("This connection is read-only, changes are not allowed", nil)
+	case table.GetIsView():
+		table.SetError("Views are read-only, changes are not allowed", nil)
+	default:
+		return false
+	}
+
+	return true
+}
+
 func (table *ResultsTable) FinishSettingValue() {
 	table.SetIsEditing(false)
 	table.SetInputCapture(table.tableInputCapture)
@@ -1346,7 +1964,12 @@ func (table *ResultsTable) UpdateSidebar() {
 			name := columns[i][0]
 			colType := columns[i][1]
 
-			text := table.GetCell(selectedRow, i-1).Text
+			columnIndex := table.GetColumnIndexByName(name)
+			if columnIndex < 0 {
+				continue
+			}
+
+			text := table.GetCell(selectedRow, columnIndex).Text
 			title := name
 
 			repeatCount := sidebarWidth - len(name) - len(colType) - 4 // idk why 4 is needed, but it works.
@@ -1363,7 +1986,7 @@ func (table *ResultsTable) UpdateSidebar() {
 			for _, dmlChange := range *table.state.listOfDbChanges {
 				if dmlChange.Type == models.DmlUpdateType {
 					for _, v := range dmlChange.Values {
-						if v.Column == name && v.TableRowIndex == selectedRow && v.TableColumnIndex == i-1 {
+						if v.Column == name && v.TableRowIndex == selectedRow && v.TableColumnIndex == columnIndex {
 							pendingEditExist = true
 							break
 						}
@@ -1376,3 +1999,89 @@ func (table *ResultsTable) UpdateSidebar() {
 
 	}
 }
+
+// BeginTransaction opens a transaction that the editor statements run in until it is
+// committed or rolled back.
+func (table *ResultsTable) BeginTransaction() {
+	if table.GetIsInTransaction() {
+		table.SetError("A transaction is already open, commit or roll it back first", nil)
+		return
+	}
+
+	transaction, err := table.DBDriver.BeginTransaction()
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	table.setTransaction(transaction)
+	table.showTransactionResult("Transaction started")
+}
+
+func (table *ResultsTable) CommitTransaction() {
+	if !table.GetIsInTransaction() {
+		table.SetError("There is no open transaction", nil)
+		return
+	}
+
+	err := table.state.transaction.Commit()
+	table.setTransaction(nil)
+
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	table.showTransactionResult("Transaction committed")
+}
+
+func (table *ResultsTable) RollbackTransaction() {
+	if !table.GetIsInTransaction() {
+		table.SetError("There is no open transaction", nil)
+		return
+	}
+
+	err := table.state.transaction.Rollback()
+	table.setTransaction(nil)
+
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	table.showTransactionResult("Transaction rolled back")
+}
+
+// discardTransaction rolls back the open transaction without reporting the result, it is
+// used when the tab is closed or the app quits.
+func (table *ResultsTable) discardTransaction() {
+	if !table.GetIsInTransaction() {
+		return
+	}
+
+	err := table.state.transaction.Rollback()
+	if err != nil {
+		logger.Error(err.Error(), nil)
+	}
+
+	table.setTransaction(nil)
+}
+
+func (table *ResultsTable) setTransaction(transaction drivers.Transaction) {
+	table.state.transaction = transaction
+
+	if table.Editor != nil {
+		table.Editor.SetTransactionIndicator(transaction != nil)
+	}
+}
+
+func (table *ResultsTable) showTransactionResult(result string) {
+	if table.Editor == nil {
+		return
+	}
+
+	table.SetResultsInfo(result)
+	table.EditorPages.SwitchToPage(pageNameTableEditorResultsInfo)
+	App.SetFocus(table.Editor)
+	App.Draw()
+}
@@ -24,6 +24,8 @@ var menuItems = []string{
 	menuConstraints,
 	menuForeignKeys,
 	menuIndexes,
+	menuDDL,
+	menuStats,
 }
 
 func NewResultsTableMenu() *ResultsTableMenu {
@@ -60,6 +62,8 @@ func NewResultsTableMenu() *ResultsTableMenu {
 			size = 20
 		case menuIndexes:
 			size = 16
+		case menuDDL, menuStats:
+			size = 11
 		}
 
 		menu.MenuItems = append(menu.MenuItems, textview)
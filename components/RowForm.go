@@ -0,0 +1,501 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// Options of the dropdowns of the enum and nullable boolean columns that are not values
+const (
+	rowFormOptionDefault = "(default)"
+	rowFormOptionNull    = "(null)"
+)
+
+// rowFormBooleans are the values of the dropdowns of the nullable boolean columns
+var rowFormBooleans = []string{"true", "false"}
+
+// rowField is an input of the row form for a column of the table.
+type rowField struct {
+	column models.ColumnDefinition
+	kind   string
+	// enum are the values of an enum column, or true and false for a nullable boolean
+	enum []string
+	// defaultValue is what the column takes by default, it is empty when there is no default
+	defaultValue string
+	foreignKey   *models.ForeignKey
+	item         tview.FormItem
+	// initial is the value the input had when the form was opened
+	initial string
+}
+
+// RowForm edits a row of the records, or a new one, with an input suited to each column: a
+// checkbox for the booleans, a dropdown for the enums and validated text for the rest.
+type RowForm struct {
+	tview.Primitive
+	table  *ResultsTable
+	form   *tview.Form
+	errors *tview.TextView
+	layout *tview.Flex
+	fields []*rowField
+	// row is the row being edited, it is -1 for a new row
+	row int
+}
+
+// showRowForm opens the row form for a row of the records, or for a new row when row is -1.
+func (table *ResultsTable) showRowForm(row int) {
+	if table.denyIfReadOnly() || !table.isShowingTableRecords() {
+		return
+	}
+
+	rowForm, err := newRowForm(table, row)
+	if err != nil {
+		table.SetError(err.Error(), nil)
+		return
+	}
+
+	MainPages.AddPage(pageNameRowForm, rowForm, true, true)
+	App.SetFocus(rowForm.form)
+}
+
+func newRowForm(table *ResultsTable, row int) (*RowForm, error) {
+	columns := table.GetColumns()
+	if len(columns) < 2 {
+		return nil, errors.New("the columns of the table are not loaded")
+	}
+
+	foreignKeys := table.GetReferencedForeignKeys()
+
+	enumValues, err := table.DBDriver.GetEnumValues(table.GetDatabaseName(), table.GetTableName())
+	if err != nil {
+		logger.Error("newRowForm", map[string]any{"error": err.Error()})
+	}
+
+	title := " New row (Ctrl+S to save, Esc to cancel) "
+	if row >= 0 {
+		title = " Edit row (Ctrl+S to save, Esc to cancel) "
+	}
+
+	rowForm := &RowForm{
+		table:  table,
+		form:   newSchemaForm(title),
+		errors: tview.NewTextView(),
+		row:    row,
+	}
+
+	rowForm.form.SetItemPadding(0)
+	rowForm.form.SetCancelFunc(rowForm.close)
+	// The border goes around the errors too
+	rowForm.form.SetBorder(false)
+	rowForm.form.SetBorderPadding(0, 0, 1, 1)
+	rowForm.errors.SetTextColor(tcell.ColorRed)
+	rowForm.errors.SetBorderPadding(0, 0, 1, 1)
+
+	for i := 1; i < len(columns); i++ {
+		field := newRowField(table.DBDriver.GetProvider(), columns, i, enumValues)
+
+		for j, foreignKey := range foreignKeys {
+			for _, column := range foreignKey.Columns {
+				if column == field.column.Name {
+					field.foreignKey = &foreignKeys[j]
+				}
+			}
+		}
+
+		rowForm.addField(field)
+	}
+
+	rowForm.form.AddButton("Save", rowForm.submit)
+	rowForm.form.AddButton("Cancel", rowForm.close)
+
+	rowForm.form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlS {
+			rowForm.submit()
+			return nil
+		}
+
+		return event
+	})
+
+	rowForm.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(rowForm.form, 0, 1, true).
+		AddItem(rowForm.errors, 0, 0, false)
+	rowForm.layout.SetBorder(true)
+	rowForm.layout.SetTitle(title)
+
+	// The fields, the buttons and the border, with room for a couple of errors
+	height := len(rowForm.fields) + 7
+	if height > 30 {
+		height = 30
+	}
+
+	rowForm.Primitive = centeredModal(rowForm.layout, 80, height)
+
+	return rowForm, nil
+}
+
+// newRowField reads the column of a row of the columns of the table and chooses its input. The
+// enum values are the ones of the columns whose type doesn't list them.
+func newRowField(provider string, columns [][]string, row int, enumValues map[string][]string) *rowField {
+	column := drivers.ColumnDefinitionFromRow(provider, columns, row)
+
+	field := &rowField{
+		column:       column,
+		kind:         helpers.FieldKind(column.Type),
+		defaultValue: column.Default,
+	}
+
+	switch {
+	case field.kind == helpers.FieldEnum:
+		field.enum = helpers.EnumValues(column.Type)
+	case len(enumValues[column.Name]) > 0:
+		field.kind = helpers.FieldEnum
+		field.enum = enumValues[column.Name]
+	case field.kind == helpers.FieldBoolean && column.Nullable:
+		// A checkbox can't be set back to NULL
+		field.enum = rowFormBooleans
+	}
+
+	if field.kind == helpers.FieldEnum && len(field.enum) == 0 {
+		field.kind = helpers.FieldText
+	}
+
+	switch {
+	case field.defaultValue != "":
+	case strings.Contains(column.Extra, "AUTO_INCREMENT"):
+		field.defaultValue = "AUTO_INCREMENT"
+	case provider == drivers.DriverSqlite && isSqliteRowID(columns, row):
+		field.defaultValue = "ROWID"
+	}
+
+	return field
+}
+
+// isSqliteRowID tells whether the column is the only column of the primary key and an
+// INTEGER, which makes it the rowid of the table, so it gets a value when it is left out.
+func isSqliteRowID(columns [][]string, row int) bool {
+	if !strings.EqualFold(drivers.TableColumnValue(columns, row, "type"), "integer") || drivers.TableColumnValue(columns, row, "pk") != "1" {
+		return false
+	}
+
+	for i := 1; i < len(columns); i++ {
+		if i != row && drivers.TableColumnValue(columns, i, "pk") != "0" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// addField adds the input of a column, with the value of the row being edited.
+func (rowForm *RowForm) addField(field *rowField) {
+	value := models.CellValue{Type: models.Default, Value: "DEFAULT"}
+	if rowForm.row >= 0 {
+		value = rowForm.table.cellValue(rowForm.row, rowForm.table.GetColumnIndexByName(field.column.Name))
+	}
+
+	label := field.column.Name
+	if !field.column.Nullable && field.defaultValue == "" {
+		label += " *"
+	}
+	if field.foreignKey != nil {
+		label += " →"
+	}
+
+	switch {
+	case field.kind == helpers.FieldBoolean && !field.column.Nullable:
+		checked := value.Type == models.String && helpers.IsTrue(value.Value.(string))
+		if value.Type == models.Default {
+			checked = helpers.IsTrue(strings.Trim(field.defaultValue, "'"))
+		}
+
+		if rowForm.row < 0 && field.defaultValue != "" {
+			label += " (default)"
+		}
+
+		checkbox := tview.NewCheckbox().SetLabel(label).SetChecked(checked)
+		field.item = checkbox
+		field.initial = fmt.Sprint(checked)
+	case field.kind == helpers.FieldEnum || field.kind == helpers.FieldBoolean:
+		options := []string{}
+		if rowForm.row < 0 && field.defaultValue != "" {
+			options = append(options, rowFormOptionDefault)
+		}
+		if field.column.Nullable {
+			options = append(options, rowFormOptionNull)
+		}
+		options = append(options, field.enum...)
+
+		current := value.Value
+		if field.kind == helpers.FieldBoolean && value.Type == models.String {
+			current = fmt.Sprint(helpers.IsTrue(value.Value.(string)))
+		}
+
+		selected := 0
+		for i, option := range options {
+			if (value.Type == models.Null && option == rowFormOptionNull) || (value.Type == models.String && option == current) {
+				selected = i
+			}
+		}
+
+		dropdown := tview.NewDropDown().SetLabel(label).SetOptions(options, nil).SetCurrentOption(selected)
+		dropdown.SetListStyles(tcell.StyleDefault.Background(app.Styles.InverseTextColor).Foreground(app.Styles.PrimaryTextColor), tcell.StyleDefault.Background(app.Styles.PrimaryTextColor).Foreground(app.Styles.InverseTextColor))
+		field.item = dropdown
+		field.initial = options[selected]
+	default:
+		text := ""
+		if value.Type == models.String {
+			text = value.Value.(string)
+		}
+
+		input := tview.NewInputField().SetLabel(label).SetText(text)
+		input.SetPlaceholder(field.placeholder(rowForm.row < 0, value))
+		input.SetPlaceholderTextColor(app.Styles.InverseTextColor)
+
+		if field.foreignKey != nil {
+			input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() != tcell.KeyCtrlP {
+					return event
+				}
+
+				rowForm.table.showForeignKeyPicker(*field.foreignKey, field.column.Name, rowForm.form, rowForm.setValues)
+
+				return nil
+			})
+		}
+
+		field.item = input
+		field.initial = text
+	}
+
+	rowForm.fields = append(rowForm.fields, field)
+	rowForm.form.AddFormItem(field.item)
+}
+
+// placeholder tells what an empty input stands for, and the format of the dates and times.
+func (field *rowField) placeholder(isNew bool, value models.CellValue) string {
+	hints := []string{}
+
+	switch {
+	case !isNew && value.Type != models.String:
+		hints = append(hints, value.Value.(string))
+	case isNew && field.defaultValue != "":
+		hints = append(hints, "DEFAULT "+field.defaultValue)
+	case field.column.Nullable:
+		hints = append(hints, "NULL")
+	}
+
+	switch field.kind {
+	case helpers.FieldDate:
+		hints = append(hints, "2006-01-02")
+	case helpers.FieldDateTime:
+		hints = append(hints, "2006-01-02 15:04:05")
+	case helpers.FieldTime:
+		hints = append(hints, "15:04:05")
+	case helpers.FieldText:
+	default:
+		hints = append(hints, field.kind)
+	}
+
+	if field.foreignKey != nil {
+		hints = append(hints, "Ctrl+P to pick")
+	}
+
+	return strings.Join(hints, " · ")
+}
+
+// current returns what the input of the field shows.
+func (field *rowField) current() string {
+	switch item := field.item.(type) {
+	case *tview.Checkbox:
+		return fmt.Sprint(item.IsChecked())
+	case *tview.DropDown:
+		_, option := item.GetCurrentOption()
+		return option
+	case *tview.InputField:
+		return item.GetText()
+	}
+
+	return ""
+}
+
+// value returns the value of the field and whether it has to be saved. An empty input stands
+// for the default of the column in a new row, and otherwise for NULL when the column is
+// nullable.
+func (field *rowField) value(provider string, isNew bool) (models.CellValue, bool, error) {
+	current := field.current()
+
+	if current == field.initial && (!isNew || field.defaultValue != "") {
+		return models.CellValue{}, false, nil
+	}
+
+	switch field.item.(type) {
+	case *tview.Checkbox:
+		return models.CellValue{Type: models.String, Value: booleanValue(provider, current)}, true, nil
+	case *tview.DropDown:
+		switch {
+		case current == rowFormOptionDefault:
+			return models.CellValue{}, false, nil
+		case current == rowFormOptionNull:
+			return models.CellValue{Type: models.Null, Value: "NULL"}, true, nil
+		case field.kind == helpers.FieldBoolean:
+			return models.CellValue{Type: models.String, Value: booleanValue(provider, current)}, true, nil
+		}
+
+		return models.CellValue{Type: models.String, Value: current}, true, nil
+	}
+
+	switch {
+	case current != "":
+		if err := helpers.ValidateField(field.kind, current); err != nil {
+			return models.CellValue{}, false, err
+		}
+
+		return models.CellValue{Type: models.String, Value: current}, true, nil
+	case isNew && field.defaultValue != "":
+		return models.CellValue{}, false, nil
+	case field.column.Nullable:
+		return models.CellValue{Type: models.Null, Value: "NULL"}, true, nil
+	case field.kind == helpers.FieldText:
+		return models.CellValue{Type: models.Empty, Value: "EMPTY"}, true, nil
+	}
+
+	return models.CellValue{}, false, errors.New("a value is required")
+}
+
+// booleanValue writes true or false as the database takes them, MySQL stores booleans as 1 and 0.
+func booleanValue(provider, value string) string {
+	if provider == drivers.DriverPostgres {
+		return value
+	}
+
+	if value == "true" {
+		return "1"
+	}
+
+	return "0"
+}
+
+// setValues puts the values chosen in the foreign key picker in the inputs of their columns.
+func (rowForm *RowForm) setValues(values map[string]string) {
+	for _, field := range rowForm.fields {
+		input, ok := field.item.(*tview.InputField)
+		if value, found := values[field.column.Name]; ok && found {
+			input.SetText(value)
+		}
+	}
+}
+
+// submit validates the inputs and queues the insert or the update of the row. Nothing is
+// queued while an input is not valid.
+func (rowForm *RowForm) submit() {
+	provider := rowForm.table.DBDriver.GetProvider()
+	isNew := rowForm.row < 0
+
+	columns := []string{}
+	values := map[string]models.CellValue{}
+	problems := []string{}
+	firstInvalid := -1
+
+	for i, field := range rowForm.fields {
+		value, ok, err := field.value(provider, isNew)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", field.column.Name, err.Error()))
+			if firstInvalid < 0 {
+				firstInvalid = i
+			}
+			continue
+		}
+
+		if ok {
+			columns = append(columns, field.column.Name)
+			values[field.column.Name] = value
+		}
+	}
+
+	if len(problems) > 0 {
+		rowForm.errors.SetText(strings.Join(problems, "\n"))
+		rowForm.layout.ResizeItem(rowForm.errors, len(problems)+1, 0)
+		rowForm.form.SetFocus(firstInvalid)
+		App.SetFocus(rowForm.form)
+		return
+	}
+
+	rowForm.close()
+
+	if isNew {
+		rowForm.table.insertRow(values)
+	} else {
+		for _, column := range columns {
+			rowForm.table.setCell(rowForm.row, rowForm.table.GetColumnIndexByName(column), values[column])
+		}
+	}
+
+	if rowForm.table.GetShowSidebar() {
+		rowForm.table.UpdateSidebar()
+	}
+}
+
+func (rowForm *RowForm) close() {
+	MainPages.RemovePage(pageNameRowForm)
+	App.SetFocus(rowForm.table)
+}
+
+// cellValue returns the value of a cell, with its pending change if it has one.
+func (table *ResultsTable) cellValue(row, col int) models.CellValue {
+	cell := table.GetCell(row, col)
+	reference, _ := cell.GetReference().(string)
+
+	switch reference {
+	case "":
+		return models.CellValue{Type: models.String, Value: cell.Text}
+	case "NULL&":
+		return models.CellValue{Type: models.Null, Value: "NULL"}
+	case "EMPTY&":
+		return models.CellValue{Type: models.Empty, Value: "EMPTY"}
+	case "DEFAULT&":
+		return models.CellValue{Type: models.Default, Value: "DEFAULT"}
+	}
+
+	// The cells of the inserted rows reference the insert, which has the type of their values
+	column := table.GetColumnNameByIndex(col)
+	for _, change := range *table.state.listOfDbChanges {
+		if change.Type != models.DmlInsertType || change.PrimaryKeyInfo[0].Value != reference {
+			continue
+		}
+
+		for _, value := range change.Values {
+			if value.Column == column {
+				return value
+			}
+		}
+	}
+
+	return models.CellValue{Type: models.String, Value: cell.Text}
+}
+
+// setCell queues the update of a cell to a value of any type.
+func (table *ResultsTable) setCell(row, col int, value models.CellValue) {
+	if value.Type == models.String {
+		table.setCellValue(row, col, value.Value.(string))
+		return
+	}
+
+	value.Column = table.GetColumnNameByIndex(col)
+	value.TableRowIndex = row
+	value.TableColumnIndex = col
+
+	table.AppendNewChange(models.DmlUpdateType, row, col, value)
+
+	// The cells of the inserted rows keep their text otherwise
+	table.GetCell(row, col).SetText(value.Value.(string))
+}
@@ -42,10 +42,19 @@ func NewSQLEditor() *SQLEditor {
 			return nil
 		} else if command == commands.UnfocusEditor {
 			sqlEditor.Publish(eventSQLEditorEscape, "")
+		} else if command == commands.BeginTransaction {
+			sqlEditor.Publish(eventSQLEditorBegin, "")
+			return nil
+		} else if command == commands.CommitTransaction {
+			sqlEditor.Publish(eventSQLEditorCommit, "")
+			return nil
+		} else if command == commands.RollbackTransaction {
+			sqlEditor.Publish(eventSQLEditorRollback, "")
+			return nil
 		} else if command == commands.OpenInExternalEditor && runtime.GOOS == "linux" {
 			// ----- THIS IS A LINUX-ONLY FEATURE, for now
 
-			text := openExternalEditor(sqlEditor)
+			text := openExternalEditor("./lazysql.sql", sqlEditor.GetText())
 
 			// Set the text from file
 			sqlEditor.SetText(text, true)
@@ -83,6 +92,16 @@ func (s *SQLEditor) Highlight() {
 	s.SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.PrimaryTextColor))
 }
 
+// SetTransactionIndicator shows in the title of the editor whether a transaction is open.
+func (s *SQLEditor) SetTransactionIndicator(open bool) {
+	if open {
+		s.SetTitle(" Transaction open ")
+		s.SetTitleColor(colorTableChange)
+	} else {
+		s.SetTitle("")
+	}
+}
+
 func (s *SQLEditor) SetBlur() {
 	s.SetBorderColor(app.Styles.InverseTextColor)
 	s.SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.InverseTextColor))
@@ -95,15 +114,12 @@ func (s *SQLEditor) SetBlur() {
 	A: OPENING EDITORS LIKE VIM/NEOVIM REALLY MESSED UP INITIAL TERMINAL'S OUTPUT.
 */
 
-func openExternalEditor(s *SQLEditor) string {
-	// Current folder as path of temporary file
-	path := "./lazysql.sql"
-
+func openExternalEditor(path, text string) string {
 	editor := getEditor()
 	terminal := getTerminal()
 
-	// Create a temporary file with the current SQL query content
-	content := []byte(s.GetText())
+	// Create a temporary file with the current content
+	content := []byte(text)
 
 	/*
 		0644 Permission
@@ -114,7 +130,7 @@ func openExternalEditor(s *SQLEditor) string {
 
 	err := os.WriteFile(path, content, 0644)
 	if err != nil {
-		return s.GetText()
+		return text
 	}
 
 	// Remove the temporary file with the end of function
@@ -131,13 +147,13 @@ func openExternalEditor(s *SQLEditor) string {
 
 	err = cmd.Run()
 	if err != nil {
-		return s.GetText()
+		return text
 	}
 
 	// Read the updated content from the temporary file
 	updatedContent, err := os.ReadFile(path)
 	if err != nil {
-		return s.GetText()
+		return text
 	}
 
 	// Convert to string before returning
@@ -0,0 +1,220 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// comparisonDrivers holds the drivers opened to compare schemas or rows with connections that
+// have no home page, so they are connected only once.
+var comparisonDrivers = map[string]drivers.Driver{}
+
+// NewSchemaDiffForm returns a modal form to choose the connection, database and schema that a
+// database is compared with. The connection is nil when the current one is chosen.
+func NewSchemaDiffForm(connections []models.Connection, database, schema string, onSubmit func(connection *models.Connection, database, schema string)) tview.Primitive {
+	form := newSchemaForm(fmt.Sprintf(" Compare %s with ", database))
+
+	options := []string{"This connection"}
+	for _, connection := range connections {
+		options = append(options, connection.Name)
+	}
+
+	form.AddDropDown("Connection", options, 0, nil)
+	form.AddInputField("Database", database, 0, nil, nil)
+	form.AddInputField("Schema", schema, 0, nil, nil)
+	form.GetFormItem(2).(*tview.InputField).SetPlaceholder("Only for PostgreSQL")
+
+	form.AddButton("Compare", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+
+		var connection *models.Connection
+		if index, _ := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption(); index > 0 {
+			connection = &connections[index-1]
+		}
+
+		onSubmit(connection, strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText()), strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText()))
+	})
+	form.AddButton("Cancel", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+	})
+
+	return centeredModal(form, 60, 11)
+}
+
+// NewSchemaDiff returns a modal that lists the differences between two schemas, followed by the
+// script that migrates the target.
+func NewSchemaDiff(source, target string, differences []models.SchemaDifference, script string) tview.Primitive {
+	textView := tview.NewTextView()
+	textView.SetDynamicColors(true)
+	textView.SetWrap(false)
+	textView.SetBorder(true)
+	textView.SetBorderPadding(0, 0, 1, 1)
+	textView.SetBorderColor(app.Styles.PrimaryTextColor)
+	textView.SetTextColor(app.Styles.PrimaryTextColor)
+	textView.SetTitle(fmt.Sprintf(" %s compared with %s (y to copy the script, Esc to close) ", source, target))
+
+	var text strings.Builder
+
+	if len(differences) == 0 {
+		text.WriteString("The schemas are the same\n")
+	}
+
+	for _, difference := range differences {
+		color := "yellow"
+		switch difference.Type {
+		case models.DiffAdded:
+			color = "green"
+		case models.DiffRemoved:
+			color = "red"
+		}
+
+		text.WriteString(fmt.Sprintf("[%s]%s %s[-]\n", color, difference.Type, tview.Escape(difference.Detail)))
+	}
+
+	if script != "" {
+		text.WriteString(fmt.Sprintf("\n[::b]Migration script of %s[::-]\n\n", tview.Escape(target)))
+		text.WriteString(tview.Escape(script))
+	}
+
+	textView.SetText(text.String())
+
+	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+
+		switch {
+		case command == commands.Quit || event.Key() == tcell.KeyEsc:
+			MainPages.RemovePage(pageNameSchemaDiff)
+		case event.Rune() == 'y':
+			if err := lib.NewClipboard().Write(script); err != nil {
+				textView.SetTitle(fmt.Sprintf(" %s ", err.Error()))
+			} else {
+				textView.SetTitle(" Copied the migration script to the clipboard (Esc to close) ")
+			}
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(textView, 0, 12, true).
+			AddItem(nil, 0, 1, false), 0, 12, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// showSchemaDiffForm asks for the database that a database or schema of the tree is compared with.
+func (home *Home) showSchemaDiffForm(database, schema string) {
+	if schema == "" && home.DBDriver.GetProvider() == drivers.DriverPostgres {
+		schema = "public"
+	}
+
+	connections, err := helpers.LoadConnections()
+	if err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	form := NewSchemaDiffForm(connections, database, schema, func(connection *models.Connection, targetDatabase, targetSchema string) {
+		home.compareSchemas(database, schema, connection, targetDatabase, targetSchema)
+	})
+
+	MainPages.AddPage(pageNameSchemaForm, form, true, true)
+}
+
+// compareSchemas shows the differences between a database of this connection, the source, and a
+// database of another connection or of this one, the target.
+func (home *Home) compareSchemas(database, schema string, connection *models.Connection, targetDatabase, targetSchema string) {
+	target := home.DBDriver
+	targetName := home.Connection.Name
+
+	if connection != nil {
+		var err error
+
+		target, err = comparisonDriver(*connection)
+		if err != nil {
+			home.showError(err.Error())
+			return
+		}
+
+		targetName = connection.Name
+	}
+
+	// The column types of a dialect can't be written in another one
+	if target.GetProvider() != home.DBDriver.GetProvider() {
+		home.showError(fmt.Sprintf("%s can only be compared with a database of the same kind, not %s", home.DBDriver.GetProvider(), target.GetProvider()))
+		return
+	}
+
+	if target.GetProvider() != drivers.DriverPostgres {
+		targetSchema = ""
+	} else if targetSchema == "" {
+		targetSchema = "public"
+	}
+
+	sourceTables, err := drivers.GetTableSchemas(home.DBDriver, database, schema)
+	if err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	targetTables, err := drivers.GetTableSchemas(target, targetDatabase, targetSchema)
+	if err != nil {
+		home.showError(err.Error())
+		return
+	}
+
+	differences := drivers.DiffSchemas(sourceTables, targetTables)
+	script := drivers.MigrationScript(target, targetDatabase, targetSchema, differences)
+
+	MainPages.AddPage(pageNameSchemaDiff, NewSchemaDiff(schemaDiffName(home.Connection.Name, database, schema), schemaDiffName(targetName, targetDatabase, targetSchema), differences, script), true, true)
+}
+
+// comparisonDriver returns the driver of a connection, reusing the one of its home page when it
+// is open.
+func comparisonDriver(connection models.Connection) (drivers.Driver, error) {
+	for _, home := range homes {
+		if home.Connection.URL == connection.URL {
+			return home.DBDriver, nil
+		}
+	}
+
+	if db, ok := comparisonDrivers[connection.URL]; ok {
+		return db, nil
+	}
+
+	db, err := drivers.Open(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	comparisonDrivers[connection.URL] = db
+
+	return db, nil
+}
+
+func schemaDiffName(connection, database, schema string) string {
+	name := fmt.Sprintf("%s/%s", connection, database)
+	if schema != "" {
+		name += "." + schema
+	}
+
+	return name
+}
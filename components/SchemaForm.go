@@ -0,0 +1,199 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// NewColumnForm returns a modal form to define a new column or change an existing one.
+func NewColumnForm(title, submitLabel string, column models.ColumnDefinition, onSubmit func(models.ColumnDefinition)) tview.Primitive {
+	form := newSchemaForm(title)
+
+	form.AddInputField("Name", column.Name, 0, nil, nil)
+	form.AddInputField("Type", column.Type, 0, nil, nil)
+	form.AddCheckbox("Nullable", column.Nullable, nil)
+	form.AddInputField("Default (SQL)", column.Default, 0, nil, nil)
+
+	form.AddButton(submitLabel, func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+
+		// Extra is not editable, it is kept as it was
+		onSubmit(models.ColumnDefinition{
+			Name:     strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()),
+			Type:     strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText()),
+			Nullable: form.GetFormItem(2).(*tview.Checkbox).IsChecked(),
+			Default:  strings.TrimSpace(form.GetFormItem(3).(*tview.InputField).GetText()),
+			Extra:    column.Extra,
+		})
+	})
+	form.AddButton("Cancel", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+	})
+
+	return centeredModal(form, 60, 13)
+}
+
+// NewIndexForm returns a modal form to create an index on some of the columns of a table.
+func NewIndexForm(columns []string, onSubmit func(models.IndexDefinition)) tview.Primitive {
+	form := newSchemaForm(" Create index ")
+
+	form.AddInputField("Name", "", 0, nil, nil)
+	form.AddInputField("Columns", "", 0, nil, nil)
+	form.GetFormItem(1).(*tview.InputField).SetPlaceholder(strings.Join(columns, ", "))
+	form.AddCheckbox("Unique", false, nil)
+
+	form.AddButton("Preview", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+
+		index := models.IndexDefinition{
+			Name:   strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()),
+			Unique: form.GetFormItem(2).(*tview.Checkbox).IsChecked(),
+		}
+
+		for _, column := range strings.Split(form.GetFormItem(1).(*tview.InputField).GetText(), ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				index.Columns = append(index.Columns, column)
+			}
+		}
+
+		onSubmit(index)
+	})
+	form.AddButton("Cancel", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+	})
+
+	return centeredModal(form, 60, 11)
+}
+
+// NewCreateTableForm returns a wizard to define a new table. Its columns are added one at a time
+// with the column form and listed below the form.
+func NewCreateTableForm(onSubmit func(table string, columns []models.ColumnDefinition, primaryKey []string)) tview.Primitive {
+	columns := []models.ColumnDefinition{}
+
+	columnList := tview.NewTextView()
+	columnList.SetBorder(true)
+	columnList.SetTitle(" Columns ")
+	columnList.SetText("No columns yet")
+
+	form := newSchemaForm(" Create table ")
+	form.SetCancelFunc(func() {
+		MainPages.RemovePage(pageNameCreateTable)
+	})
+
+	form.AddInputField("Name", "", 0, nil, nil)
+	form.AddInputField("Primary key", "", 0, nil, nil)
+
+	primaryKeyInput := form.GetFormItem(1).(*tview.InputField)
+
+	updateColumnList := func() {
+		lines := []string{}
+		for _, column := range columns {
+			line := column.Name + " " + column.Type
+			if !column.Nullable {
+				line += " NOT NULL"
+			}
+
+			if column.Default != "" {
+				line += " DEFAULT " + column.Default
+			}
+
+			lines = append(lines, tview.Escape(line))
+		}
+
+		if len(lines) == 0 {
+			lines = append(lines, "No columns yet")
+		}
+
+		columnList.SetText(strings.Join(lines, "\n"))
+	}
+
+	form.AddButton("Add column", func() {
+		columnForm := NewColumnForm(" Add column ", "Add", models.ColumnDefinition{Nullable: true}, func(column models.ColumnDefinition) {
+			columns = append(columns, column)
+
+			// The first column is usually the primary key
+			if len(columns) == 1 && primaryKeyInput.GetText() == "" {
+				primaryKeyInput.SetText(column.Name)
+			}
+
+			updateColumnList()
+		})
+
+		MainPages.AddPage(pageNameSchemaForm, columnForm, true, true)
+	})
+	form.AddButton("Remove last", func() {
+		if len(columns) > 0 {
+			columns = columns[:len(columns)-1]
+			updateColumnList()
+		}
+	})
+	form.AddButton("Preview", func() {
+		MainPages.RemovePage(pageNameCreateTable)
+
+		primaryKey := []string{}
+		for _, column := range strings.Split(primaryKeyInput.GetText(), ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				primaryKey = append(primaryKey, column)
+			}
+		}
+
+		onSubmit(strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()), columns, primaryKey)
+	})
+	form.AddButton("Cancel", func() {
+		MainPages.RemovePage(pageNameCreateTable)
+	})
+
+	wrapper := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 9, 0, true).
+		AddItem(columnList, 0, 1, false)
+
+	return centeredModal(wrapper, 80, 22)
+}
+
+// NewTableNameForm returns a modal form to choose the name of a renamed or duplicated table, with
+// the option to copy its rows when duplicating.
+func NewTableNameForm(title, name string, withDataOption bool, onSubmit func(name string, withData bool)) tview.Primitive {
+	form := newSchemaForm(title)
+
+	form.AddInputField("New name", name, 0, nil, nil)
+	if withDataOption {
+		form.AddCheckbox("Copy data", false, nil)
+	}
+
+	form.AddButton("Preview", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+
+		withData := withDataOption && form.GetFormItem(1).(*tview.Checkbox).IsChecked()
+		onSubmit(strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()), withData)
+	})
+	form.AddButton("Cancel", func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+	})
+
+	return centeredModal(form, 60, 9)
+}
+
+func newSchemaForm(title string) *tview.Form {
+	form := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
+	form.SetBorder(true)
+	form.SetTitle(title)
+	form.SetCancelFunc(func() {
+		MainPages.RemovePage(pageNameSchemaForm)
+	})
+
+	return form
+}
+
+func centeredModal(primitive tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(primitive, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
 	"github.com/jorgerojas26/lazysql/app"
@@ -30,6 +31,7 @@ type TabbedPane struct {
 	*tview.Pages
 	HeaderContainer *tview.Flex
 	state           *TabbedPaneState
+	headerColor     tcell.Color
 }
 
 func NewTabbedPane() *TabbedPane {
@@ -40,6 +42,7 @@ func NewTabbedPane() *TabbedPane {
 		Pages:           tview.NewPages(),
 		HeaderContainer: container,
 		state:           &TabbedPaneState{},
+		headerColor:     app.Styles.PrimaryTextColor,
 	}
 }
 
@@ -243,7 +246,7 @@ func (t *TabbedPane) HighlightTabHeader(tab *Tab) {
 		if tabToHighlight.Header == tab.Header {
 			tabToHighlight.Header.SetTextColor(app.Styles.SecondaryTextColor)
 		} else {
-			tabToHighlight.Header.SetTextColor(app.Styles.PrimaryTextColor)
+			tabToHighlight.Header.SetTextColor(t.headerColor)
 		}
 		tabToHighlight = tabToHighlight.NextTab
 	}
@@ -256,12 +259,18 @@ func (t *TabbedPane) Highlight() {
 		if tab == t.state.CurrentTab {
 			tab.Header.SetTextColor(app.Styles.SecondaryTextColor)
 		} else {
-			tab.Header.SetTextColor(app.Styles.PrimaryTextColor)
+			tab.Header.SetTextColor(t.headerColor)
 		}
 		tab = tab.NextTab
 	}
 }
 
+// SetHeaderColor sets the color of the headers of the tabs that are not selected.
+func (t *TabbedPane) SetHeaderColor(color tcell.Color) {
+	t.headerColor = color
+	t.Highlight()
+}
+
 func (t *TabbedPane) SetBlur() {
 	tab := t.state.FirstTab
 
@@ -22,6 +22,55 @@ type TreeState struct {
 	isFiltering           bool
 }
 
+// objectGroup is the reference of the node that groups the objects of a type
+type objectGroup struct {
+	database   string
+	schema     string
+	objectType string
+}
+
+// accessGroup is the reference of the node that lists the users and roles of the server, its
+// children reference a *models.Role.
+type accessGroup struct{}
+
+var objectGroupNames = map[string]string{
+	models.ObjectTypeView:             "Views",
+	models.ObjectTypeMaterializedView: "Materialized views",
+	models.ObjectTypeFunction:         "Functions",
+	models.ObjectTypeProcedure:        "Procedures",
+	models.ObjectTypeTrigger:          "Triggers",
+	models.ObjectTypeSequence:         "Sequences",
+	models.ObjectTypeType:             "Types",
+}
+
+var objectIcons = map[string]string{
+	models.ObjectTypeView:             "◇",
+	models.ObjectTypeMaterializedView: "◆",
+	models.ObjectTypeFunction:         "ƒ",
+	models.ObjectTypeProcedure:        "λ",
+	models.ObjectTypeTrigger:          "↯",
+	models.ObjectTypeSequence:         "#",
+	models.ObjectTypeType:             "τ",
+}
+
+// treeTableAction is published when an action on a table is chosen in the tree. Table is empty
+// when creating a table, and Schema is only set for the drivers that group the tables by schema.
+type treeTableAction struct {
+	database string
+	schema   string
+	table    string
+	command  commands.Command
+}
+
+// tableName returns the name of the table in the format of the driver.
+func (action treeTableAction) tableName(table string) string {
+	if action.schema != "" {
+		return fmt.Sprintf("%s.%s", action.schema, table)
+	}
+
+	return table
+}
+
 type Tree struct {
 	DBDriver drivers.Driver
 	*tview.TreeView
@@ -81,16 +130,16 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 				rootNode.AddChild(childNode)
 
 				go func(database string, node *tview.TreeNode) {
-					tables, err := tree.DBDriver.GetTables(database)
-					if err != nil {
+					if err := tree.loadDatabase(database, node); err != nil {
 						logger.Error(err.Error(), nil)
 						return
 					}
 
-					tree.databasesToNodes(tables, node, true)
 					App.Draw()
 				}(database, childNode)
 			}
+
+			tree.addAccessNode(rootNode)
 		}
 		tree.SetFocusFunc(nil)
 	})
@@ -116,6 +165,25 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 	})
 
 	tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		switch reference := node.GetReference().(type) {
+		case objectGroup, accessGroup:
+			node.SetExpanded(!node.IsExpanded())
+			return
+		case *models.Role:
+			tree.Publish(models.StateChange{
+				Key:   eventTreeSelectedRole,
+				Value: *reference,
+			})
+			return
+		case models.DatabaseObject:
+			tree.SetSelectedDatabase(reference.Database)
+			tree.Publish(models.StateChange{
+				Key:   eventTreeSelectedObject,
+				Value: reference,
+			})
+			return
+		}
+
 		if node.GetLevel() == 1 {
 			if node.IsExpanded() {
 				node.SetExpanded(false)
@@ -173,10 +241,8 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 			childrens := tree.GetRoot().GetChildren()
 			lastNode := childrens[len(childrens)-1]
 
-			if lastNode.IsExpanded() {
-				childNodes := lastNode.GetChildren()
-				lastChildren := childNodes[len(childNodes)-1]
-				tree.SetCurrentNode(lastChildren)
+			if childNodes := lastNode.GetChildren(); lastNode.IsExpanded() && len(childNodes) > 0 {
+				tree.SetCurrentNode(childNodes[len(childNodes)-1])
 			} else {
 				tree.SetCurrentNode(lastNode)
 			}
@@ -202,6 +268,66 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 			tree.CollapseAll()
 		case commands.ExpandAll:
 			tree.ExpandAll()
+		case commands.ShowDefinition:
+			if object, ok := tree.GetCurrentNode().GetReference().(models.DatabaseObject); ok {
+				tree.Publish(models.StateChange{
+					Key:   eventTreeShowDefinition,
+					Value: object,
+				})
+			}
+		case commands.ShowDiagram:
+			// The diagram of a PostgreSQL database node has the tables of every schema
+			database, schema, _ := tree.nodeTable(tree.GetCurrentNode())
+			if database != "" {
+				tree.Publish(models.StateChange{
+					Key:   eventTreeShowDiagram,
+					Value: treeTableAction{database: database, schema: schema, command: command},
+				})
+			}
+		case commands.CompareSchema:
+			database, schema, _ := tree.nodeTable(tree.GetCurrentNode())
+			if database != "" {
+				tree.Publish(models.StateChange{
+					Key:   eventTreeCompareSchema,
+					Value: treeTableAction{database: database, schema: schema, command: command},
+				})
+			}
+		case commands.ShowDatabaseStats:
+			database, _, _ := tree.nodeTable(tree.GetCurrentNode())
+			if database != "" {
+				tree.Publish(models.StateChange{
+					Key:   eventTreeShowStats,
+					Value: treeTableAction{database: database, command: command},
+				})
+			}
+		case commands.Refresh:
+			if accessNode := tree.accessNode(tree.GetCurrentNode()); accessNode != nil {
+				tree.loadRoles(accessNode)
+				break
+			}
+
+			database, _, _ := tree.nodeTable(tree.GetCurrentNode())
+			if database != "" {
+				if err := tree.RefreshDatabase(database); err != nil {
+					logger.Error(err.Error(), nil)
+				}
+			}
+		case commands.CreateTable, commands.RenameTable, commands.TruncateTable, commands.DropTable, commands.DuplicateTable:
+			database, schema, table := tree.nodeTable(tree.GetCurrentNode())
+
+			// Only creating a table works on the database and schema nodes
+			if database == "" || (table == "" && command != commands.CreateTable) {
+				break
+			}
+
+			if schema == "" && tree.DBDriver.GetProvider() == drivers.DriverPostgres {
+				schema = "public"
+			}
+
+			tree.Publish(models.StateChange{
+				Key:   eventTreeTableAction,
+				Value: treeTableAction{database: database, schema: schema, table: table, command: command},
+			})
 		}
 		return nil
 	})
@@ -288,6 +414,169 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 	return tree
 }
 
+// loadDatabase adds the tables and the objects of a database to its node.
+func (tree *Tree) loadDatabase(database string, node *tview.TreeNode) error {
+	tables, err := tree.DBDriver.GetTables(database)
+	if err != nil {
+		return err
+	}
+
+	tree.databasesToNodes(tables, node, true)
+
+	objects, err := tree.DBDriver.GetObjects(database)
+	if err != nil {
+		logger.Error(err.Error(), nil)
+	} else {
+		tree.objectsToNodes(objects, node)
+	}
+
+	return nil
+}
+
+// addAccessNode adds the section of the users and roles after the databases. SQLite has no users,
+// so the section only says it is not supported.
+func (tree *Tree) addAccessNode(rootNode *tview.TreeNode) {
+	text := "Users and roles"
+	if tree.DBDriver.GetProvider() == drivers.DriverSqlite {
+		text += " (unsupported)"
+	}
+
+	node := tview.NewTreeNode(text)
+	node.SetExpanded(false)
+	node.SetReference(accessGroup{})
+	node.SetColor(app.Styles.PrimaryTextColor)
+	rootNode.AddChild(node)
+
+	go func() {
+		tree.loadRoles(node)
+		App.Draw()
+	}()
+}
+
+// loadRoles adds the users and roles of the server to the access section, or the reason they
+// can't be listed.
+func (tree *Tree) loadRoles(node *tview.TreeNode) {
+	roles, err := tree.DBDriver.GetRoles()
+
+	node.ClearChildren()
+
+	if err != nil {
+		childNode := tview.NewTreeNode(err.Error())
+		childNode.SetReference(accessGroup{})
+		childNode.SetColor(app.Styles.InverseTextColor)
+		node.AddChild(childNode)
+		return
+	}
+
+	for i := range roles {
+		role := &roles[i]
+
+		text := role.String()
+		if len(role.Attributes) > 0 {
+			text += fmt.Sprintf(" (%s)", strings.Join(role.Attributes, ", "))
+		}
+
+		childNode := tview.NewTreeNode(text)
+		childNode.SetReference(role)
+		childNode.SetColor(app.Styles.PrimaryTextColor)
+		node.AddChild(childNode)
+	}
+}
+
+// accessNode returns the node of the access section when a node is in it.
+func (tree *Tree) accessNode(node *tview.TreeNode) *tview.TreeNode {
+	path := tree.GetPath(node)
+	if len(path) < 2 {
+		return nil
+	}
+
+	if _, ok := path[1].GetReference().(accessGroup); ok {
+		return path[1]
+	}
+
+	return nil
+}
+
+// RefreshDatabase reloads the tables and the objects of a database without reconnecting. The
+// nodes that were expanded stay expanded and the cursor stays on the same node when it still exists.
+func (tree *Tree) RefreshDatabase(database string) error {
+	var databaseNode *tview.TreeNode
+
+	for _, node := range tree.GetRoot().GetChildren() {
+		if reference, ok := node.GetReference().(string); ok && reference == database {
+			databaseNode = node
+			break
+		}
+	}
+
+	if databaseNode == nil {
+		return fmt.Errorf("database %s is not in the tree", database)
+	}
+
+	currentNode := tree.GetCurrentNode()
+	currentReference := currentNode.GetReference()
+	isCurrentNodeInDatabase := false
+
+	expanded := map[interface{}]bool{}
+	databaseNode.Walk(func(node, _ *tview.TreeNode) bool {
+		if node.IsExpanded() {
+			expanded[node.GetReference()] = true
+		}
+
+		isCurrentNodeInDatabase = isCurrentNodeInDatabase || node == currentNode
+		return true
+	})
+
+	if err := tree.loadDatabase(database, databaseNode); err != nil {
+		return err
+	}
+
+	if isCurrentNodeInDatabase {
+		tree.SetCurrentNode(databaseNode)
+	}
+
+	databaseNode.Walk(func(node, _ *tview.TreeNode) bool {
+		if expanded[node.GetReference()] {
+			node.SetExpanded(true)
+		}
+
+		if isCurrentNodeInDatabase && node.GetReference() == currentReference {
+			tree.SetCurrentNode(node)
+		}
+		return true
+	})
+
+	return nil
+}
+
+// nodeTable returns the database of a node, with the schema for the drivers that group the tables
+// by schema. The table is only returned for table nodes.
+func (tree *Tree) nodeTable(node *tview.TreeNode) (database, schema, table string) {
+	path := tree.GetPath(node)
+	if len(path) < 2 {
+		return "", "", ""
+	}
+
+	database, _ = path[1].GetReference().(string)
+
+	// Tables are the children of the database node, or of the schema nodes in PostgreSQL
+	tableLevel := 3
+	if tree.DBDriver.GetProvider() == drivers.DriverPostgres {
+		tableLevel = 4
+
+		if len(path) > 2 {
+			schema, _ = path[2].GetReference().(string)
+		}
+	}
+
+	if reference, ok := node.GetReference().(string); ok && len(path) == tableLevel {
+		split := strings.Split(reference, ".")
+		table = split[len(split)-1]
+	}
+
+	return database, schema, table
+}
+
 func (tree *Tree) databasesToNodes(children map[string][]string, node *tview.TreeNode, defaultExpanded bool) {
 	node.ClearChildren()
 
@@ -324,6 +613,67 @@ func (tree *Tree) databasesToNodes(children map[string][]string, node *tview.Tre
 	}
 }
 
+// objectsToNodes adds a node per object type to the database node, or to the schema node for the
+// drivers that group the tables by schema, with the objects of that type.
+func (tree *Tree) objectsToNodes(objects []models.DatabaseObject, node *tview.TreeNode) {
+	groups := map[objectGroup]*tview.TreeNode{}
+
+	for _, objectType := range models.ObjectTypes {
+		for _, object := range objects {
+			if object.Type != objectType {
+				continue
+			}
+
+			reference := objectGroup{database: object.Database, schema: object.Schema, objectType: objectType}
+
+			groupNode, ok := groups[reference]
+			if !ok {
+				parentNode := node
+
+				if object.Schema != "" {
+					parentNode = schemaNode(node, object.Schema)
+				}
+
+				groupNode = tview.NewTreeNode(objectGroupNames[objectType])
+				groupNode.SetExpanded(false)
+				groupNode.SetReference(reference)
+				groupNode.SetColor(app.Styles.PrimaryTextColor)
+				parentNode.AddChild(groupNode)
+
+				groups[reference] = groupNode
+			}
+
+			text := fmt.Sprintf("%s %s", objectIcons[objectType], object.Name)
+			if object.Table != "" {
+				text += fmt.Sprintf(" (%s)", object.Table)
+			}
+
+			childNode := tview.NewTreeNode(text)
+			childNode.SetReference(object)
+			childNode.SetColor(app.Styles.PrimaryTextColor)
+			groupNode.AddChild(childNode)
+		}
+	}
+}
+
+// schemaNode returns the node of a schema under the database node, adding it for schemas
+// without tables.
+func schemaNode(databaseNode *tview.TreeNode, schema string) *tview.TreeNode {
+	for _, child := range databaseNode.GetChildren() {
+		if reference, ok := child.GetReference().(string); ok && reference == schema {
+			return child
+		}
+	}
+
+	node := tview.NewTreeNode(schema)
+	node.SetExpanded(false)
+	node.SetReference(schema)
+	node.SetColor(app.Styles.PrimaryTextColor)
+	databaseNode.AddChild(node)
+
+	return node
+}
+
 func (tree *Tree) search(searchText string) {
 	rootNode := tree.GetRoot()
 	lowerSearchText := strings.ToLower(searchText)
@@ -393,6 +743,19 @@ func (tree *Tree) SetSelectedDatabase(database string) {
 	})
 }
 
+// SelectDatabase moves the cursor to the database node, expands it and selects the database.
+func (tree *Tree) SelectDatabase(database string) {
+	for _, node := range tree.GetRoot().GetChildren() {
+		if reference, ok := node.GetReference().(string); ok && reference == database {
+			node.SetExpanded(true)
+			tree.SetCurrentNode(node)
+			break
+		}
+	}
+
+	tree.SetSelectedDatabase(database)
+}
+
 func (tree *Tree) SetSelectedTable(table string) {
 	tree.state.selectedTable = table
 	tree.Publish(models.StateChange{
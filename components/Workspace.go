@@ -0,0 +1,194 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// maxListedPendingChanges is the number of recovered changes listed before they are queued.
+const maxListedPendingChanges = 10
+
+// workspace returns the tabs, the editor and the pending changes of the home.
+func (home *Home) workspace() models.Workspace {
+	workspace := models.Workspace{
+		PendingChanges: home.ListOfDbChanges,
+	}
+
+	if current := home.TabbedPane.GetCurrentTab(); current != nil {
+		workspace.CurrentTab = current.Reference
+	}
+
+	tab := home.TabbedPane.state.FirstTab
+
+	for i := 0; tab != nil && i < home.TabbedPane.state.Length; i++ {
+		table := tab.Content
+
+		switch {
+		case tab.Name == tabNameEditor && table.Editor != nil:
+			workspace.EditorOpen = true
+			workspace.Editor = table.Editor.GetText()
+		case table.Menu != nil:
+			workspace.Tabs = append(workspace.Tabs, models.WorkspaceTab{
+				Database:   table.GetDatabaseName(),
+				Table:      table.GetTableName(),
+				Name:       tab.Name,
+				Reference:  tab.Reference,
+				IsView:     table.GetIsView(),
+				Where:      strings.TrimPrefix(table.Filter.GetCurrentFilter(), "WHERE "),
+				Conditions: table.Filter.GetConditions(),
+				Sort:       table.GetCurrentSort(),
+				Offset:     table.Pagination.GetOffset(),
+			})
+		}
+
+		tab = tab.NextTab
+	}
+
+	return workspace
+}
+
+// SaveWorkspaces saves the workspace of every open connection, unless the config turns it off.
+func SaveWorkspaces() error {
+	config, _ := helpers.LoadConfig()
+	if !config.SavesWorkspace() || len(homes) == 0 {
+		return nil
+	}
+
+	workspaces := map[string]models.Workspace{}
+	for _, home := range homes {
+		workspaces[home.Connection.Name] = home.workspace()
+	}
+
+	return helpers.SaveWorkspaces(workspaces)
+}
+
+// closeHome closes the home page of the connection with the URL, when it is open, and the
+// connection of its driver, so its workspace is no longer saved on exit. The workspace is saved
+// right away for the connection with the given name, which is empty to discard it.
+func closeHome(url string, workspaceName string) {
+	for i, home := range homes {
+		if home.Connection.URL != url {
+			continue
+		}
+
+		if config, _ := helpers.LoadConfig(); workspaceName != "" && config.SavesWorkspace() {
+			if err := helpers.SaveWorkspaces(map[string]models.Workspace{workspaceName: home.workspace()}); err != nil {
+				logger.Error("closeHome", map[string]any{"error": err.Error()})
+			}
+		}
+
+		homes = append(homes[:i], homes[i+1:]...)
+		MainPages.RemovePage(url)
+
+		if err := home.DBDriver.Close(); err != nil {
+			logger.Error("closeHome", map[string]any{"error": err.Error()})
+		}
+
+		return
+	}
+}
+
+// restoreWorkspace reopens the tabs and the editor saved for the connection, and offers to
+// recover the changes that were not saved.
+func (home *Home) restoreWorkspace() {
+	config, _ := helpers.LoadConfig()
+	if !config.SavesWorkspace() {
+		return
+	}
+
+	workspace, err := helpers.LoadWorkspace(home.Connection.Name)
+	if err != nil {
+		logger.Error("restoreWorkspace", map[string]any{"error": err.Error()})
+		return
+	}
+
+	for _, tab := range workspace.Tabs {
+		home.openTableTab(tableLocation{
+			database:   tab.Database,
+			table:      tab.Table,
+			where:      tab.Where,
+			conditions: tab.Conditions,
+			sort:       tab.Sort,
+			offset:     tab.Offset,
+			name:       tab.Name,
+			reference:  tab.Reference,
+		}, tab.IsView)
+	}
+
+	if workspace.EditorOpen {
+		tableWithEditor := home.openEditor()
+		tableWithEditor.Editor.SetText(workspace.Editor, true)
+	}
+
+	if workspace.CurrentTab != "" && home.TabbedPane.GetTabByReference(workspace.CurrentTab) != nil {
+		home.focusTab(home.TabbedPane.SwitchToTabByReference(workspace.CurrentTab))
+	}
+
+	if len(workspace.PendingChanges) > 0 {
+		home.offerPendingChanges(workspace.PendingChanges)
+	}
+}
+
+// offerPendingChanges lists the changes that were not saved when lazysql quit and asks whether to
+// queue them again. The rows of the updates and deletes may be elsewhere by now, so they are not
+// tied to the cells of the tabs anymore and can only be seen in this list before they are queued.
+func (home *Home) offerPendingChanges(changes []models.DbDmlChange) {
+	var confirmationText strings.Builder
+
+	if len(changes) == 1 {
+		confirmationText.WriteString("There is 1 change that was not saved in the last session:\n\n")
+	} else {
+		confirmationText.WriteString(fmt.Sprintf("There are %d changes that were not saved in the last session:\n\n", len(changes)))
+	}
+
+	for i, change := range changes {
+		if i == maxListedPendingChanges {
+			confirmationText.WriteString(fmt.Sprintf("and %d more\n", len(changes)-i))
+			break
+		}
+
+		confirmationText.WriteString(helpers.DescribeChange(change) + "\n")
+	}
+
+	if len(changes) == 1 {
+		confirmationText.WriteString("\nRecover it? It runs with the next save.")
+	} else {
+		confirmationText.WriteString("\nRecover them? They run with the next save.")
+	}
+
+	confirmationModal := NewConfirmationModal(confirmationText.String())
+
+	confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+		MainPages.RemovePage(pageNameConfirmation)
+
+		if buttonLabel != "Yes" {
+			return
+		}
+
+		for _, change := range changes {
+			for i, value := range change.Values {
+				// The values are read back from the state file as whatever type it gave them
+				if _, ok := value.Value.(string); !ok {
+					change.Values[i].Value = fmt.Sprint(value.Value)
+				}
+
+				if change.Type != models.DmlInsertType {
+					change.Values[i].TableRowIndex = -1
+					change.Values[i].TableColumnIndex = -1
+				}
+			}
+
+			home.ListOfDbChanges = append(home.ListOfDbChanges, change)
+		}
+
+		if tab := home.TabbedPane.GetCurrentTab(); tab != nil && tab.Content.isShowingTableRecords() {
+			tab.Content.AddInsertedRows()
+		}
+	})
+
+	MainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+}
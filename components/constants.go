@@ -11,9 +11,26 @@ var App = app.App
 // Pages
 const (
 	// General
-	pageNameHelp         string = "Help"
-	pageNameConfirmation string = "Confirmation"
-	pageNameConnections  string = "Connections"
+	pageNameHelp             string = "Help"
+	pageNameConfirmation     string = "Confirmation"
+	pageNameConnections      string = "Connections"
+	pageNameObjectDefinition string = "ObjectDefinition"
+	pageNameSchemaForm       string = "SchemaForm"
+	pageNameCreateTable      string = "CreateTable"
+	pageNameError            string = "Error"
+	pageNameReferences       string = "References"
+	pageNameERDiagram        string = "ERDiagram"
+	pageNameDiagramFile      string = "DiagramFile"
+	pageNameSchemaDiff       string = "SchemaDiff"
+	pageNameDataDiff         string = "DataDiff"
+	pageNameDatabaseStats    string = "DatabaseStats"
+	pageNameActivityMonitor  string = "ActivityMonitor"
+	pageNameSessionQuery     string = "SessionQuery"
+	pageNameGrants           string = "Grants"
+	pageNameColumnLayout     string = "ColumnLayout"
+	pageNameCellViewer       string = "CellViewer"
+	pageNameRowForm          string = "RowForm"
+	pageNameForeignKeyPicker string = "ForeignKeyPicker"
 
 	// Results table
 	pageNameTable                  string = "Table"
@@ -22,6 +39,8 @@ const (
 	pageNameTableEditorTable       string = "TableEditorTable"
 	pageNameTableEditorResultsInfo string = "TableEditorResultsInfo"
 	pageNameTableEditCell          string = "TableEditCell"
+	pageNameTableRecords           string = "TableRecords"
+	pageNameTableDDL               string = "TableDDL"
 
 	// Sidebar
 	pageNameSidebar string = "Sidebar"
@@ -46,14 +65,24 @@ const (
 	eventSidebarToggling      string = "TogglingSidebar"
 	eventSidebarCommitEditing string = "CommitEditingSidebar"
 
-	eventSQLEditorQuery  string = "Query"
-	eventSQLEditorEscape string = "Escape"
+	eventSQLEditorQuery    string = "Query"
+	eventSQLEditorEscape   string = "Escape"
+	eventSQLEditorBegin    string = "BeginTransaction"
+	eventSQLEditorCommit   string = "CommitTransaction"
+	eventSQLEditorRollback string = "RollbackTransaction"
 
 	eventResultsTableFiltering string = "FilteringResultsTable"
 
 	eventTreeSelectedDatabase string = "SelectedDatabase"
 	eventTreeSelectedTable    string = "SelectedTable"
 	eventTreeIsFiltering      string = "IsFiltering"
+	eventTreeSelectedObject   string = "SelectedObject"
+	eventTreeShowDefinition   string = "ShowDefinition"
+	eventTreeTableAction      string = "TableAction"
+	eventTreeShowDiagram      string = "ShowDiagram"
+	eventTreeCompareSchema    string = "CompareSchema"
+	eventTreeShowStats        string = "ShowStats"
+	eventTreeSelectedRole     string = "SelectedRole"
 )
 
 // Results table menu items
@@ -63,6 +92,8 @@ const (
 	menuConstraints string = "Constraints"
 	menuForeignKeys string = "Foreign Keys"
 	menuIndexes     string = "Indexes"
+	menuDDL         string = "DDL"
+	menuStats       string = "Stats"
 )
 
 // Actions
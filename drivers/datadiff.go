@@ -0,0 +1,413 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// DataDiffChunkSize is the number of rows read from each side of a data diff at a time.
+const DataDiffChunkSize = 1000
+
+// TableSource is a table of a database, read through the driver of its connection.
+type TableSource struct {
+	Driver   Driver
+	Database string
+	Table    string
+}
+
+// tableReader reads the rows of a table in chunks ordered by its primary key, each chunk starting
+// after the key of the last row of the previous one.
+type tableReader struct {
+	source     TableSource
+	sort       []models.OrderBy
+	columns    []string
+	keyIndexes []int
+	after      []string
+	rows       [][]string
+	done       bool
+}
+
+func newTableReader(source TableSource, primaryKey []string) *tableReader {
+	sort := make([]models.OrderBy, 0, len(primaryKey))
+	for _, column := range primaryKey {
+		sort = append(sort, models.OrderBy{Column: column})
+	}
+
+	return &tableReader{source: source, sort: sort}
+}
+
+// next reads the next chunk of rows, which is empty once every row was read.
+func (reader *tableReader) next(chunkSize int) error {
+	reader.rows = nil
+	if reader.done {
+		return nil
+	}
+
+	records, _, err := reader.source.Driver.GetRecords(reader.source.Database, reader.source.Table, models.RecordsQuery{
+		OrderBy: reader.sort,
+		After:   reader.after,
+		Limit:   chunkSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		reader.done = true
+		return nil
+	}
+
+	if reader.columns == nil {
+		reader.columns = records[0]
+
+		for _, column := range reader.sort {
+			index := indexOf(reader.columns, column.Column)
+			if index < 0 {
+				return fmt.Errorf("%s has no column %s of the primary key", reader.source.Table, column.Column)
+			}
+
+			reader.keyIndexes = append(reader.keyIndexes, index)
+		}
+	}
+
+	reader.rows = records[1:]
+	reader.done = len(reader.rows) < chunkSize
+
+	if len(reader.rows) > 0 {
+		reader.after = projectRow(reader.rows[len(reader.rows)-1], reader.keyIndexes)
+	}
+
+	return nil
+}
+
+// DiffTableData compares the rows of a table in two databases by the primary key of the left
+// one. Both sides are read in chunks ordered by the key and merged as they are read, so only a
+// chunk of each side is kept in memory along with the differences. The keys are compared as
+// numbers when their column is a number in the left table and as text otherwise, and both
+// databases have to sort them that way. Only the columns of the left table that the right table
+// has are compared.
+// progress is called after each chunk with the number of rows read, returning false stops the
+// comparison.
+func DiffTableData(left, right TableSource, chunkSize int, progress func(rows int) bool) (models.TableDataDiff, error) {
+	primaryKey, err := left.Driver.GetPrimaryKeyColumnNames(left.Database, left.Table)
+	if err != nil {
+		return models.TableDataDiff{}, err
+	}
+
+	if len(primaryKey) == 0 {
+		return models.TableDataDiff{}, fmt.Errorf("%s has no primary key to match the rows with", left.Table)
+	}
+
+	numeric, err := numericKeyColumns(left, primaryKey)
+	if err != nil {
+		return models.TableDataDiff{}, err
+	}
+
+	diff := models.TableDataDiff{PrimaryKey: primaryKey}
+
+	leftStream := &keyStream{reader: newTableReader(left, primaryKey), numeric: numeric}
+	rightStream := &keyStream{reader: newTableReader(right, primaryKey), numeric: numeric}
+
+	read := func(stream *keyStream) error {
+		rows, err := stream.fill(chunkSize)
+		if err != nil || rows == 0 {
+			return err
+		}
+
+		diff.Rows += rows
+
+		if progress != nil && !progress(diff.Rows) {
+			return errors.New("the comparison was stopped")
+		}
+
+		return nil
+	}
+
+	for _, stream := range []*keyStream{leftStream, rightStream} {
+		if err := read(stream); err != nil {
+			return models.TableDataDiff{}, err
+		}
+	}
+
+	columns, leftIndexes, rightIndexes, err := commonColumns(leftStream.reader.columns, rightStream.reader.columns)
+	if err != nil {
+		return models.TableDataDiff{}, err
+	}
+
+	diff.Columns = columns
+
+	for {
+		leftRecord, rightRecord := leftStream.peek(), rightStream.peek()
+		if leftRecord == nil && rightRecord == nil {
+			break
+		}
+
+		order := 0
+		switch {
+		case leftRecord == nil:
+			order = 1
+		case rightRecord == nil:
+			order = -1
+		default:
+			order = compareKeys(leftStream.key(), rightStream.key(), numeric)
+		}
+
+		switch {
+		case order < 0:
+			diff.OnlyLeft = append(diff.OnlyLeft, projectRow(leftRecord, leftIndexes))
+		case order > 0:
+			diff.OnlyRight = append(diff.OnlyRight, projectRow(rightRecord, rightIndexes))
+		default:
+			leftRow, rightRow := projectRow(leftRecord, leftIndexes), projectRow(rightRecord, rightIndexes)
+			if !equalRows(leftRow, rightRow) {
+				diff.Changed = append(diff.Changed, models.ChangedRow{Left: leftRow, Right: rightRow})
+			}
+		}
+
+		if order <= 0 {
+			leftStream.position++
+			if err := read(leftStream); err != nil {
+				return models.TableDataDiff{}, err
+			}
+		}
+
+		if order >= 0 {
+			rightStream.position++
+			if err := read(rightStream); err != nil {
+				return models.TableDataDiff{}, err
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// keyStream walks through the rows of a table reader one at a time, reading the next chunk when
+// the current one runs out and checking that the keys come in the order they are merged in.
+type keyStream struct {
+	reader   *tableReader
+	numeric  []bool
+	position int
+	// previous is the key of the last row of the previous chunk
+	previous []string
+}
+
+// fill reads the next chunk once every row of the current one was used, and returns the number
+// of rows it read.
+func (stream *keyStream) fill(chunkSize int) (int, error) {
+	if stream.position < len(stream.reader.rows) || stream.reader.done {
+		return 0, nil
+	}
+
+	if err := stream.reader.next(chunkSize); err != nil {
+		return 0, err
+	}
+
+	stream.position = 0
+
+	previous := stream.previous
+	for _, record := range stream.reader.rows {
+		key := projectRow(record, stream.reader.keyIndexes)
+		if previous != nil && compareKeys(previous, key, stream.numeric) >= 0 {
+			return 0, stream.orderError()
+		}
+
+		previous = key
+	}
+
+	stream.previous = previous
+
+	return len(stream.reader.rows), nil
+}
+
+func (stream *keyStream) orderError() error {
+	return fmt.Errorf("the keys of %s are not sorted as numbers or as text, the rows can't be matched", stream.reader.source.Table)
+}
+
+// peek returns the current row, or nil once every row was used.
+func (stream *keyStream) peek() []string {
+	if stream.position >= len(stream.reader.rows) {
+		return nil
+	}
+
+	return stream.reader.rows[stream.position]
+}
+
+// key returns the values of the primary key of the current row.
+func (stream *keyStream) key() []string {
+	return projectRow(stream.peek(), stream.reader.keyIndexes)
+}
+
+// numericKeyColumns returns whether each column of the primary key is a number in the left table.
+func numericKeyColumns(source TableSource, primaryKey []string) ([]bool, error) {
+	columns, err := source.Driver.GetTableColumns(source.Database, source.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	types := map[string]string{}
+	for row := 1; row < len(columns); row++ {
+		column := ColumnDefinitionFromRow(source.Driver.GetProvider(), columns, row)
+		types[column.Name] = column.Type
+	}
+
+	numeric := make([]bool, len(primaryKey))
+	for i, column := range primaryKey {
+		numeric[i] = numericTypePattern.MatchString(types[column])
+	}
+
+	return numeric, nil
+}
+
+var numericTypePattern = regexp.MustCompile(`(?i)^((tiny|small|medium|big)?(int|integer|serial)\d*|decimal|numeric|float\d*|double|real|number)\b`)
+
+// compareKeys compares two primary keys column by column, as numbers in the numeric columns, so
+// 1.5 and 1.50 are the same key, and as bytes in the others. Values that are not numbers sort
+// after the numbers.
+func compareKeys(a, b []string, numeric []bool) int {
+	for i := range a {
+		if order := compareKeyValues(a[i], b[i], numeric[i]); order != 0 {
+			return order
+		}
+	}
+
+	return 0
+}
+
+func compareKeyValues(a, b string, numeric bool) int {
+	if numeric {
+		aNumber, aOK := new(big.Rat).SetString(a)
+		bNumber, bOK := new(big.Rat).SetString(b)
+
+		switch {
+		case aOK && bOK:
+			return aNumber.Cmp(bNumber)
+		case aOK:
+			return -1
+		case bOK:
+			return 1
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+// commonColumns returns the left columns that the right side has too, with their positions on
+// each side.
+func commonColumns(left, right []string) (columns []string, leftIndexes, rightIndexes []int, err error) {
+	for i, column := range left {
+		if j := indexOf(right, column); j >= 0 {
+			columns = append(columns, column)
+			leftIndexes = append(leftIndexes, i)
+			rightIndexes = append(rightIndexes, j)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, nil, nil, fmt.Errorf("the tables have no columns in common")
+	}
+
+	return columns, leftIndexes, rightIndexes, nil
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func projectRow(record []string, indexes []int) []string {
+	row := make([]string, len(indexes))
+	for i, index := range indexes {
+		if index < len(record) {
+			row[i] = record[index]
+		}
+	}
+
+	return row
+}
+
+func equalRows(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DataSyncScript returns the INSERT, UPDATE and DELETE statements that make the rows of the
+// right table match the left one, in the dialect of the right provider. Postgres tables include
+// their schema.
+func DataSyncScript(provider, table string, diff models.TableDataDiff) string {
+	formattedTable := QuoteIdentifier(provider, table)
+	if provider == DriverPostgres {
+		if schema, name, ok := strings.Cut(table, "."); ok {
+			formattedTable = QuoteIdentifier(provider, schema) + "." + QuoteIdentifier(provider, name)
+		}
+	}
+
+	columns := make([]string, len(diff.Columns))
+	for i, column := range diff.Columns {
+		columns[i] = QuoteIdentifier(provider, column)
+	}
+
+	where := func(row []string) string {
+		conditions := make([]string, 0, len(diff.PrimaryKey))
+		for _, column := range diff.PrimaryKey {
+			conditions = append(conditions, fmt.Sprintf("%s = %s", QuoteIdentifier(provider, column), recordLiteral(provider, row[indexOf(diff.Columns, column)])))
+		}
+
+		return strings.Join(conditions, " AND ")
+	}
+
+	var script strings.Builder
+
+	for _, row := range diff.OnlyLeft {
+		values := make([]string, len(row))
+		for i, value := range row {
+			values[i] = recordLiteral(provider, value)
+		}
+
+		script.WriteString(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);\n", formattedTable, strings.Join(columns, ", "), strings.Join(values, ", ")))
+	}
+
+	for _, changed := range diff.Changed {
+		assignments := []string{}
+		for i, value := range changed.Left {
+			if value != changed.Right[i] {
+				assignments = append(assignments, fmt.Sprintf("%s = %s", columns[i], recordLiteral(provider, value)))
+			}
+		}
+
+		script.WriteString(fmt.Sprintf("UPDATE %s SET %s WHERE %s;\n", formattedTable, strings.Join(assignments, ", "), where(changed.Right)))
+	}
+
+	for _, row := range diff.OnlyRight {
+		script.WriteString(fmt.Sprintf("DELETE FROM %s WHERE %s;\n", formattedTable, where(row)))
+	}
+
+	return script.String()
+}
+
+// recordLiteral returns the SQL literal of a value of the records.
+func recordLiteral(provider, value string) string {
+	switch value {
+	case "NULL&":
+		return "NULL"
+	case "EMPTY&":
+		return "''"
+	}
+
+	return QuoteLiteral(provider, value)
+}
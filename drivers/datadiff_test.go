@@ -0,0 +1,205 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// recordsDriver serves the records of a table from memory, in the order they are listed, the
+// rest of the driver is not used.
+type recordsDriver struct {
+	Driver
+	primaryKey []string
+	columns    [][]string
+	records    [][]string
+}
+
+func (db *recordsDriver) GetProvider() string {
+	return DriverMySQL
+}
+
+func (db *recordsDriver) GetPrimaryKeyColumnNames(_, _ string) ([]string, error) {
+	return db.primaryKey, nil
+}
+
+func (db *recordsDriver) GetTableColumns(_, _ string) ([][]string, error) {
+	return db.columns, nil
+}
+
+func (db *recordsDriver) GetRecords(_, _ string, query models.RecordsQuery) ([][]string, int, error) {
+	rows := db.records[1:]
+
+	// The rows after a key start after the row with that key
+	offset := 0
+	if len(query.After) > 0 {
+		keyIndexes := make([]int, len(query.After))
+		for i := range query.After {
+			keyIndexes[i] = indexOf(db.records[0], query.OrderBy[i].Column)
+		}
+
+		for i, row := range rows {
+			if reflect.DeepEqual(projectRow(row, keyIndexes), query.After) {
+				offset = i + 1
+			}
+		}
+	}
+
+	end := offset + query.Limit
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	return append([][]string{db.records[0]}, rows[offset:end]...), len(rows), nil
+}
+
+func TestDiffTableData(t *testing.T) {
+	left := &recordsDriver{
+		primaryKey: []string{"id"},
+		columns: [][]string{
+			{"Field", "Type"},
+			{"id", "int(11)"},
+			{"name", "varchar(50)"},
+			{"email", "varchar(100)"},
+		},
+		records: [][]string{
+			{"id", "name", "email"},
+			{"1", "ann", "ann@example.com"},
+			{"2", "bob", "NULL&"},
+			{"3", "cy", "cy@example.com"},
+			{"10", "ten", "EMPTY&"},
+		},
+	}
+
+	// The right side has no email column
+	right := &recordsDriver{
+		primaryKey: []string{"id"},
+		records: [][]string{
+			{"name", "id"},
+			{"ann", "1"},
+			{"bobby", "2"},
+			{"dee", "4"},
+			{"ten", "10"},
+		},
+	}
+
+	var progress []int
+
+	got, err := DiffTableData(TableSource{Driver: left, Table: "customers"}, TableSource{Driver: right, Table: "customers"}, 2, func(rows int) bool {
+		progress = append(progress, rows)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := models.TableDataDiff{
+		Columns:    []string{"id", "name"},
+		PrimaryKey: []string{"id"},
+		OnlyLeft:   [][]string{{"3", "cy"}},
+		OnlyRight:  [][]string{{"4", "dee"}},
+		Changed:    []models.ChangedRow{{Left: []string{"2", "bob"}, Right: []string{"2", "bobby"}}},
+		Rows:       8,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffTableData() = %+v, want %+v", got, want)
+	}
+
+	if !reflect.DeepEqual(progress, []int{2, 4, 6, 8}) {
+		t.Errorf("progress = %v, want [2 4 6 8]", progress)
+	}
+
+	_, err = DiffTableData(TableSource{Driver: left, Table: "customers"}, TableSource{Driver: right, Table: "customers"}, 2, func(int) bool {
+		return false
+	})
+	if err == nil {
+		t.Error("DiffTableData() did not stop")
+	}
+
+	// The keys of a side sorted as text can't be merged with the ones sorted as numbers
+	right.records = [][]string{{"name", "id"}, {"ann", "1"}, {"ten", "10"}, {"bobby", "2"}}
+
+	_, err = DiffTableData(TableSource{Driver: left, Table: "customers"}, TableSource{Driver: right, Table: "customers"}, 2, nil)
+	if err == nil {
+		t.Error("DiffTableData() matched keys sorted in another order")
+	}
+}
+
+func Test_compareKeys(t *testing.T) {
+	tests := []struct {
+		a, b    []string
+		numeric []bool
+		want    int
+	}{
+		{a: []string{"9"}, b: []string{"10"}, numeric: []bool{true}, want: -1},
+		{a: []string{"9"}, b: []string{"10"}, numeric: []bool{false}, want: 1},
+		{a: []string{"1.50"}, b: []string{"1.5"}, numeric: []bool{true}, want: 0},
+		{a: []string{"2", "b"}, b: []string{"2", "a"}, numeric: []bool{true, false}, want: 1},
+		{a: []string{"-1"}, b: []string{"1"}, numeric: []bool{true}, want: -1},
+		{a: []string{"ann"}, b: []string{"ann"}, numeric: []bool{false}, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := compareKeys(tt.a, tt.b, tt.numeric); got != tt.want {
+			t.Errorf("compareKeys(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func Test_numericTypePattern(t *testing.T) {
+	for _, columnType := range []string{"int(11) unsigned", "bigint", "INTEGER", "numeric(10,2)", "double precision", "smallserial", "int8"} {
+		if !numericTypePattern.MatchString(columnType) {
+			t.Errorf("%q is not a number", columnType)
+		}
+	}
+
+	for _, columnType := range []string{"interval", "varchar(20)", "text", "uuid", "point"} {
+		if numericTypePattern.MatchString(columnType) {
+			t.Errorf("%q is a number", columnType)
+		}
+	}
+}
+
+func TestDataSyncScript(t *testing.T) {
+	diff := models.TableDataDiff{
+		Columns:    []string{"id", "name", "note"},
+		PrimaryKey: []string{"id"},
+		OnlyLeft:   [][]string{{"3", "O'Brien", "NULL&"}},
+		OnlyRight:  [][]string{{"4", "dee", "EMPTY&"}},
+		Changed:    []models.ChangedRow{{Left: []string{"2", "bob", "vip"}, Right: []string{"2", "bobby", "vip"}}},
+	}
+
+	tests := []struct {
+		name     string
+		provider string
+		table    string
+		want     string
+	}{
+		{
+			name:     "MySQL",
+			provider: DriverMySQL,
+			table:    "customers",
+			want: "INSERT INTO `customers` (`id`, `name`, `note`) VALUES ('3', 'O''Brien', NULL);\n" +
+				"UPDATE `customers` SET `name` = 'bob' WHERE `id` = '2';\n" +
+				"DELETE FROM `customers` WHERE `id` = '4';\n",
+		},
+		{
+			name:     "PostgreSQL",
+			provider: DriverPostgres,
+			table:    "public.customers",
+			want: `INSERT INTO "public"."customers" ("id", "name", "note") VALUES ('3', 'O''Brien', NULL);` + "\n" +
+				`UPDATE "public"."customers" SET "name" = 'bob' WHERE "id" = '2';` + "\n" +
+				`DELETE FROM "public"."customers" WHERE "id" = '4';` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DataSyncScript(test.provider, test.table, diff); got != test.want {
+				t.Errorf("DataSyncScript() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package drivers
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// GetDiagram returns the tables of a database with their columns and the foreign keys between
+// them. On PostgreSQL the tables can be limited to a schema, the tables of other schemas that
+// they reference are included without columns.
+func GetDiagram(db Driver, database, schema string) (models.Diagram, error) {
+	tables, err := db.GetTables(database)
+	if err != nil {
+		return models.Diagram{}, err
+	}
+
+	names := []string{}
+
+	for key, keyTables := range tables {
+		for _, table := range keyTables {
+			switch {
+			case db.GetProvider() == DriverPostgres:
+				if schema == "" || key == schema {
+					names = append(names, key+"."+table)
+				}
+			case db.GetProvider() == DriverSqlite && strings.HasPrefix(table, "sqlite_"):
+				// Internal tables, like sqlite_sequence
+			default:
+				names = append(names, table)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	diagram := models.Diagram{}
+	included := map[string]bool{}
+
+	for _, name := range names {
+		columns, err := db.GetTableColumns(database, name)
+		if err != nil {
+			return models.Diagram{}, err
+		}
+
+		primaryKey, err := db.GetPrimaryKeyColumnNames(database, name)
+		if err != nil {
+			return models.Diagram{}, err
+		}
+
+		foreignKeys, err := db.GetReferencedForeignKeys(database, name)
+		if err != nil {
+			return models.Diagram{}, err
+		}
+
+		table := models.DiagramTable{Name: name}
+
+		for row := 1; row < len(columns); row++ {
+			column := models.DiagramColumn{
+				Name: TableColumnValue(columns, row, "Field", "column_name", "name"),
+				Type: TableColumnValue(columns, row, "Type", "data_type", "type"),
+			}
+
+			for _, primaryKeyColumn := range primaryKey {
+				column.PrimaryKey = column.PrimaryKey || primaryKeyColumn == column.Name
+			}
+
+			table.Columns = append(table.Columns, column)
+		}
+
+		diagram.Tables = append(diagram.Tables, table)
+		diagram.ForeignKeys = append(diagram.ForeignKeys, foreignKeys...)
+		included[name] = true
+	}
+
+	for _, foreignKey := range diagram.ForeignKeys {
+		if !included[foreignKey.ReferencedTable] {
+			diagram.Tables = append(diagram.Tables, models.DiagramTable{Name: foreignKey.ReferencedTable})
+			included[foreignKey.ReferencedTable] = true
+		}
+	}
+
+	return diagram, nil
+}
@@ -1,6 +1,8 @@
 package drivers
 
 import (
+	"fmt"
+
 	"github.com/jorgerojas26/lazysql/models"
 )
 
@@ -9,17 +11,95 @@ type Driver interface {
 	TestConnection(urlstr string) error
 	GetDatabases() ([]string, error)
 	GetTables(database string) (map[string][]string, error)
+	GetObjects(database string) ([]models.DatabaseObject, error)
+	GetObjectDefinition(object models.DatabaseObject) (string, error)
 	GetTableColumns(database, table string) ([][]string, error)
 	GetConstraints(database, table string) ([][]string, error)
 	GetForeignKeys(database, table string) ([][]string, error)
+	// GetReferencedForeignKeys returns the foreign keys of a table, which reference other tables
+	GetReferencedForeignKeys(database, table string) ([]models.ForeignKey, error)
+	// GetReferencingForeignKeys returns the foreign keys of other tables that reference a table
+	GetReferencingForeignKeys(database, table string) ([]models.ForeignKey, error)
 	GetIndexes(database, table string) ([][]string, error)
-	GetRecords(database, table, where, sort string, offset, limit int) ([][]string, int, error)
+	// GetIndexDefinitions returns the indexes of a table other than its primary key
+	GetIndexDefinitions(database, table string) ([]models.IndexDefinition, error)
+	// GetEnumValues returns the labels of the enum columns of a table by column, for the
+	// databases whose column types don't list them
+	GetEnumValues(database, table string) (map[string][]string, error)
+	GetTableDDL(database, table string) (string, error)
+	// GetTableStats returns statistics of a table, like its approximate row count and size, as rows
+	// of a name and a value after a header
+	GetTableStats(database, table string) ([][]string, error)
+	// GetDatabaseStats returns statistics of a database, like its size and number of tables, in the
+	// format of GetTableStats
+	GetDatabaseStats(database string) ([][]string, error)
+	// GetSessions returns the sessions of the server other than the one of the driver
+	GetSessions() ([]models.Session, error)
+	// CancelSession cancels the query that a session is running
+	CancelSession(id string) error
+	// TerminateSession closes a session
+	TerminateSession(id string) error
+	// GetRoles returns the users and roles of the server
+	GetRoles() ([]models.Role, error)
+	// GetGrants returns the privileges of a role on databases, schemas and tables and the roles
+	// granted to it
+	GetGrants(role models.Role) ([]models.Grant, error)
+	// GetGrantStatement returns the statement that grants the privileges of a grant, or revokes them
+	GetGrantStatement(grant models.Grant, revoke bool) (string, error)
+	GetSchemaChangeStatements(change models.SchemaChange) ([]string, error)
+	ExecuteSchemaStatements(database string, statements []string) error
+	// GetRecords returns a page of the records of a table and the number of records that match
+	// the filter of the query
+	GetRecords(database, table string, query models.RecordsQuery) ([][]string, int, error)
 	UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error
 	DeleteRecord(database, table string, primaryKeyColumnName, primaryKeyValue string) error
 	ExecuteDMLStatement(query string) (string, error)
 	ExecuteQuery(query string) ([][]string, error)
 	ExecutePendingChanges(changes []models.DbDmlChange) error
+	BeginTransaction() (Transaction, error)
 	SetProvider(provider string) // NOTE: This is used to get the primary key from the database table until i find a better way to do it. See ResultsTable.go GetPrimaryKeyValue function
 	GetProvider() string
+	// Close closes the connection pool of the driver
+	Close() error
 	GetPrimaryKeyColumnNames(database, table string) ([]string, error)
+	GetUniqueKeyColumnNames(database, table string) ([]string, error)
+}
+
+// NewDriver returns the driver for the provider of a connection.
+func NewDriver(provider string) (Driver, error) {
+	switch provider {
+	case DriverMySQL:
+		return &MySQL{}, nil
+	case DriverPostgres:
+		return &Postgres{}, nil
+	case DriverSqlite:
+		return &SQLite{}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported database provider: %s", provider)
+}
+
+// Open returns the driver of a connection connected to its database, read-only when the
+// connection is.
+func Open(connection models.Connection) (Driver, error) {
+	db, err := NewDriver(connection.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	urlstr := connection.URL
+
+	if connection.ReadOnly {
+		urlstr, err = ReadOnlyURL(connection.Provider, urlstr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = db.Connect(urlstr)
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
 }
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/xo/dburl"
 
 	"github.com/jorgerojas26/lazysql/helpers/logger"
@@ -15,6 +17,8 @@ import (
 type MySQL struct {
 	Connection *sql.DB
 	Provider   string
+	// sessions keeps the ids of the sessions of the connections of the pool
+	sessions *sessionConnector
 }
 
 func (db *MySQL) TestConnection(urlstr string) (err error) {
@@ -24,11 +28,24 @@ func (db *MySQL) TestConnection(urlstr string) (err error) {
 func (db *MySQL) Connect(urlstr string) (err error) {
 	db.SetProvider(DriverMySQL)
 
-	db.Connection, err = dburl.Open(urlstr)
+	parsedURL, err := dburl.Parse(urlstr)
 	if err != nil {
 		return err
 	}
 
+	config, err := mysql.ParseDSN(parsedURL.DSN)
+	if err != nil {
+		return err
+	}
+
+	connector, err := mysql.NewConnector(config)
+	if err != nil {
+		return err
+	}
+
+	db.sessions = newSessionConnector(connector, "SELECT CONNECTION_ID()")
+	db.Connection = sql.OpenDB(db.sessions)
+
 	err = db.Connection.Ping()
 	if err != nil {
 		return err
@@ -68,7 +85,8 @@ func (db *MySQL) GetTables(database string) (map[string][]string, error) {
 		return nil, errors.New("database name is required")
 	}
 
-	rows, err := db.Connection.Query(fmt.Sprintf("SHOW TABLES FROM `%s`", database))
+	// Views are listed with the other database objects
+	rows, err := db.Connection.Query(fmt.Sprintf("SHOW FULL TABLES FROM `%s` WHERE Table_type <> 'VIEW'", database))
 	if err != nil {
 		return nil, err
 	}
@@ -76,8 +94,8 @@ func (db *MySQL) GetTables(database string) (map[string][]string, error) {
 
 	tables := make(map[string][]string)
 	for rows.Next() {
-		var table string
-		err = rows.Scan(&table)
+		var table, tableType string
+		err = rows.Scan(&table, &tableType)
 		if err != nil {
 			return nil, err
 		}
@@ -91,6 +109,428 @@ func (db *MySQL) GetTables(database string) (map[string][]string, error) {
 	return tables, nil
 }
 
+func (db *MySQL) GetObjects(database string) ([]models.DatabaseObject, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	rows, err := db.Connection.Query(`
+	SELECT '', table_name, '', 'view' FROM information_schema.views WHERE table_schema = ?
+	UNION ALL
+	SELECT '', routine_name, '', LOWER(routine_type) FROM information_schema.routines WHERE routine_schema = ?
+	UNION ALL
+	SELECT '', trigger_name, event_object_table, 'trigger' FROM information_schema.triggers WHERE trigger_schema = ?
+	ORDER BY 2
+	`, database, database, database)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanObjects(rows, database)
+}
+
+func (db *MySQL) GetObjectDefinition(object models.DatabaseObject) (string, error) {
+	var query, column string
+
+	name := db.formatTableName(object.Database, object.Name)
+
+	switch object.Type {
+	case models.ObjectTypeView:
+		query, column = "SHOW CREATE VIEW "+name, "Create View"
+	case models.ObjectTypeFunction:
+		query, column = "SHOW CREATE FUNCTION "+name, "Create Function"
+	case models.ObjectTypeProcedure:
+		query, column = "SHOW CREATE PROCEDURE "+name, "Create Procedure"
+	case models.ObjectTypeTrigger:
+		query, column = "SHOW CREATE TRIGGER "+name, "SQL Original Statement"
+	default:
+		return "", fmt.Errorf("unsupported object type: %s", object.Type)
+	}
+
+	results, err := db.ExecuteQuery(query)
+	if err != nil {
+		return "", err
+	}
+
+	definition, err := resultColumnValue(results, column)
+	if err != nil {
+		return "", err
+	}
+
+	// The body of routines is NULL for users without privileges on them
+	if definition == "" {
+		return "", fmt.Errorf("the definition of %s is not available, check the privileges of the user", object.Name)
+	}
+
+	return definition, nil
+}
+
+// GetTableDDL returns the CREATE statement of a table or view.
+func (db *MySQL) GetTableDDL(database, table string) (string, error) {
+	if database == "" {
+		return "", errors.New("database name is required")
+	}
+
+	if table == "" {
+		return "", errors.New("table name is required")
+	}
+
+	results, err := db.ExecuteQuery("SHOW CREATE TABLE " + db.formatTableName(database, table))
+	if err != nil {
+		return "", err
+	}
+
+	// SHOW CREATE TABLE also works for views, but names the column after them
+	ddl, err := resultColumnValue(results, "Create Table")
+	if err != nil {
+		ddl, err = resultColumnValue(results, "Create View")
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return ddl + ";", nil
+}
+
+// GetTableStats reads the statistics of a table from information_schema.TABLES, where the
+// row count is an estimate on InnoDB.
+func (db *MySQL) GetTableStats(database, table string) ([][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	var engine, rowFormat, collation, autoIncrement, createTime, updateTime, checkTime sql.NullString
+	var rows, dataLength, indexLength, dataFree sql.NullInt64
+
+	// TABLE_ROWS is exact for MyISAM but an estimate for InnoDB
+	err := db.Connection.QueryRow(`
+	SELECT ENGINE, ROW_FORMAT, TABLE_COLLATION, TABLE_ROWS, DATA_LENGTH, INDEX_LENGTH, DATA_FREE,
+		CAST(AUTO_INCREMENT AS CHAR), CAST(CREATE_TIME AS CHAR), CAST(UPDATE_TIME AS CHAR), CAST(CHECK_TIME AS CHAR)
+	FROM information_schema.TABLES
+	WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`, database, table).
+		Scan(&engine, &rowFormat, &collation, &rows, &dataLength, &indexLength, &dataFree, &autoIncrement, &createTime, &updateTime, &checkTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]string{
+		statsHeader,
+		{"Approximate rows", fmt.Sprint(rows.Int64)},
+		{"Data size", formatSize(dataLength.Int64)},
+		{"Index size", formatSize(indexLength.Int64)},
+		{"Total size", formatSize(dataLength.Int64 + indexLength.Int64)},
+		{"Free space", formatSize(dataFree.Int64)},
+		{"Auto increment", autoIncrement.String},
+		{"Created", createTime.String},
+		{"Last update", updateTime.String},
+		{"Last check", checkTime.String},
+		{"Engine", engine.String},
+		{"Row format", rowFormat.String},
+		{"Collation", collation.String},
+	}, nil
+}
+
+// GetDatabaseStats sums the statistics of the tables of a database.
+func (db *MySQL) GetDatabaseStats(database string) ([][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	var tables, views, rows, dataLength, indexLength int64
+	var characterSet, collation string
+
+	err := db.Connection.QueryRow(`
+	SELECT COALESCE(SUM(t.TABLE_TYPE = 'BASE TABLE'), 0), COALESCE(SUM(t.TABLE_TYPE = 'VIEW'), 0),
+		COALESCE(SUM(t.TABLE_ROWS), 0), COALESCE(SUM(t.DATA_LENGTH), 0), COALESCE(SUM(t.INDEX_LENGTH), 0),
+		s.DEFAULT_CHARACTER_SET_NAME, s.DEFAULT_COLLATION_NAME
+	FROM information_schema.SCHEMATA s
+	LEFT JOIN information_schema.TABLES t ON t.TABLE_SCHEMA = s.SCHEMA_NAME
+	WHERE s.SCHEMA_NAME = ?
+	GROUP BY s.DEFAULT_CHARACTER_SET_NAME, s.DEFAULT_COLLATION_NAME`, database).
+		Scan(&tables, &views, &rows, &dataLength, &indexLength, &characterSet, &collation)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]string{
+		statsHeader,
+		{"Tables", fmt.Sprint(tables)},
+		{"Views", fmt.Sprint(views)},
+		{"Approximate rows", fmt.Sprint(rows)},
+		{"Data size", formatSize(dataLength)},
+		{"Index size", formatSize(indexLength)},
+		{"Total size", formatSize(dataLength + indexLength)},
+		{"Character set", characterSet},
+		{"Collation", collation},
+	}, nil
+}
+
+func (db *MySQL) GetSessions() ([]models.Session, error) {
+	listedAt := time.Now()
+
+	rows, err := db.Connection.Query(`
+	SELECT ID, COALESCE(USER, ''), COALESCE(DB, ''), COMMAND, COALESCE(STATE, ''), COALESCE(TIME, 0), COALESCE(INFO, '')
+	FROM information_schema.PROCESSLIST
+	WHERE ID <> CONNECTION_ID()
+	ORDER BY TIME DESC, ID`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	indexes := map[string]int{}
+
+	for rows.Next() {
+		var session models.Session
+		var command, state string
+		var seconds int64
+
+		err := rows.Scan(&session.ID, &session.User, &session.Database, &command, &state, &seconds, &session.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		session.State = command
+		if state != "" {
+			session.State += ": " + state
+		}
+
+		session.Duration = time.Duration(seconds) * time.Second
+
+		indexes[session.ID] = len(sessions)
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// The sys schema lists the InnoDB lock waits, it may be missing or not readable, so the
+	// sessions are listed without their locks then
+	lockRows, err := db.Connection.Query("SELECT waiting_pid, blocking_pid FROM sys.innodb_lock_waits")
+	if err != nil {
+		logger.Error("Failed to read the lock waits", map[string]any{"error": err.Error()})
+		return db.sessions.leaveOut(sessions, listedAt), nil
+	}
+	defer lockRows.Close()
+
+	for lockRows.Next() {
+		var waiting, blocking string
+
+		if err := lockRows.Scan(&waiting, &blocking); err != nil {
+			return nil, err
+		}
+
+		if i, ok := indexes[waiting]; ok {
+			sessions[i].BlockedBy = append(sessions[i].BlockedBy, blocking)
+		}
+	}
+
+	if err := lockRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return db.sessions.leaveOut(sessions, listedAt), nil
+}
+
+func (db *MySQL) CancelSession(id string) error {
+	number, err := parseSessionID(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Connection.Exec(fmt.Sprintf("KILL QUERY %d", number))
+
+	return err
+}
+
+func (db *MySQL) TerminateSession(id string) error {
+	number, err := parseSessionID(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Connection.Exec(fmt.Sprintf("KILL CONNECTION %d", number))
+
+	return err
+}
+
+func (db *MySQL) GetRoles() ([]models.Role, error) {
+	rows, err := db.Connection.Query("SELECT User, Host, Super_priv = 'Y' FROM mysql.user ORDER BY User, Host")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+
+	for rows.Next() {
+		var role models.Role
+		var superuser bool
+
+		if err := rows.Scan(&role.Name, &role.Host, &superuser); err != nil {
+			return nil, err
+		}
+
+		if superuser {
+			role.Attributes = append(role.Attributes, "super")
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+func (db *MySQL) GetGrants(role models.Role) ([]models.Grant, error) {
+	rows, err := db.Connection.Query(fmt.Sprintf("SHOW GRANTS FOR %s@%s", QuoteLiteral(DriverMySQL, role.Name), QuoteLiteral(DriverMySQL, role.Host)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []models.Grant{}
+
+	for rows.Next() {
+		var statement string
+
+		if err := rows.Scan(&statement); err != nil {
+			return nil, err
+		}
+
+		if grant, ok := parseMySQLGrant(statement); ok {
+			grant.Role = role
+			grants = append(grants, grant)
+		}
+	}
+
+	return grants, rows.Err()
+}
+
+// parseMySQLGrant reads a line of SHOW GRANTS, which grants privileges on an object or roles.
+func parseMySQLGrant(statement string) (models.Grant, bool) {
+	statement, ok := strings.CutPrefix(statement, "GRANT ")
+	if !ok {
+		return models.Grant{}, false
+	}
+
+	grant := models.Grant{
+		Grantable: strings.Contains(statement, " WITH GRANT OPTION") || strings.Contains(statement, " WITH ADMIN OPTION"),
+	}
+
+	privileges, rest, ok := strings.Cut(statement, " ON ")
+	if !ok {
+		// Roles are granted without an object: GRANT `role`@`%` TO `user`@`%`
+		roles, _, _ := strings.Cut(statement, " TO ")
+
+		grant.ObjectType = models.GrantObjectRole
+		for _, role := range SplitPrivileges(roles) {
+			grant.Privileges = append(grant.Privileges, unquoteMySQLName(role))
+		}
+
+		return grant, true
+	}
+
+	object, _, _ := strings.Cut(rest, " TO ")
+	grant.Privileges = SplitPrivileges(privileges)
+
+	switch {
+	case object == "*.*":
+		grant.ObjectType = models.GrantObjectGlobal
+	case strings.HasPrefix(object, "PROCEDURE "):
+		grant.ObjectType = models.GrantObjectProcedure
+		grant.Object = unquoteMySQLName(strings.TrimPrefix(object, "PROCEDURE "))
+	case strings.HasPrefix(object, "FUNCTION "):
+		grant.ObjectType = models.GrantObjectFunction
+		grant.Object = unquoteMySQLName(strings.TrimPrefix(object, "FUNCTION "))
+	case strings.HasSuffix(object, ".*"):
+		grant.ObjectType = models.GrantObjectDatabase
+		grant.Object = unquoteMySQLName(strings.TrimSuffix(object, ".*"))
+	default:
+		grant.ObjectType = models.GrantObjectTable
+		grant.Object = unquoteMySQLName(object)
+	}
+
+	return grant, true
+}
+
+// unquoteMySQLName removes the quotes of the parts of a name like `shop`.`orders` or 'app'@'%'.
+func unquoteMySQLName(name string) string {
+	return strings.NewReplacer("``", "`", "`", "", "'", "").Replace(name)
+}
+
+func (db *MySQL) GetGrantStatement(grant models.Grant, revoke bool) (string, error) {
+	if err := validateGrant(grant); err != nil {
+		return "", err
+	}
+
+	account := QuoteIdentifier(DriverMySQL, grant.Role.Name) + "@" + QuoteIdentifier(DriverMySQL, grant.Role.Host)
+
+	if grant.ObjectType == models.GrantObjectRole {
+		roles := make([]string, len(grant.Privileges))
+		for i, role := range grant.Privileges {
+			name, host := role, "%"
+			if at := strings.LastIndex(role, "@"); at >= 0 {
+				name, host = role[:at], role[at+1:]
+			}
+
+			roles[i] = QuoteIdentifier(DriverMySQL, name) + "@" + QuoteIdentifier(DriverMySQL, host)
+		}
+
+		if revoke {
+			return fmt.Sprintf("REVOKE %s FROM %s", strings.Join(roles, ", "), account), nil
+		}
+
+		statement := fmt.Sprintf("GRANT %s TO %s", strings.Join(roles, ", "), account)
+		if grant.Grantable {
+			statement += " WITH ADMIN OPTION"
+		}
+
+		return statement, nil
+	}
+
+	var object string
+
+	switch grant.ObjectType {
+	case models.GrantObjectGlobal:
+		object = "*.*"
+	case models.GrantObjectDatabase:
+		object = QuoteIdentifier(DriverMySQL, grant.Object) + ".*"
+	case models.GrantObjectTable, models.GrantObjectProcedure, models.GrantObjectFunction:
+		database, name, ok := strings.Cut(grant.Object, ".")
+		if !ok {
+			return "", fmt.Errorf("the %s has to be written as database.%s", grant.ObjectType, grant.ObjectType)
+		}
+
+		object = QuoteIdentifier(DriverMySQL, database) + "." + QuoteIdentifier(DriverMySQL, name)
+		if grant.ObjectType != models.GrantObjectTable {
+			object = strings.ToUpper(grant.ObjectType) + " " + object
+		}
+	case models.GrantObjectSchema:
+		return "", errors.New("MySQL has no schemas, grant the privileges on the database")
+	default:
+		return "", fmt.Errorf("MySQL has no %s privileges", grant.ObjectType)
+	}
+
+	privileges := strings.Join(grant.Privileges, ", ")
+
+	if revoke {
+		return fmt.Sprintf("REVOKE %s ON %s FROM %s", privileges, object, account), nil
+	}
+
+	statement := fmt.Sprintf("GRANT %s ON %s TO %s", privileges, object, account)
+	if grant.Grantable {
+		statement += " WITH GRANT OPTION"
+	}
+
+	return statement, nil
+}
+
 func (db *MySQL) GetTableColumns(database, table string) (results [][]string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
@@ -240,6 +680,76 @@ func (db *MySQL) GetForeignKeys(database, table string) (results [][]string, err
 	return
 }
 
+func (db *MySQL) GetReferencedForeignKeys(database, table string) ([]models.ForeignKey, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	// Foreign keys to tables of other databases can't be opened from the tabs of this one
+	rows, err := db.Connection.Query(`
+	SELECT CONSTRAINT_NAME, TABLE_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+	FROM information_schema.KEY_COLUMN_USAGE
+	WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND REFERENCED_TABLE_SCHEMA = TABLE_SCHEMA
+	ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION`, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanForeignKeys(rows)
+}
+
+func (db *MySQL) GetReferencingForeignKeys(database, table string) ([]models.ForeignKey, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	rows, err := db.Connection.Query(`
+	SELECT CONSTRAINT_NAME, TABLE_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+	FROM information_schema.KEY_COLUMN_USAGE
+	WHERE REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME = ? AND TABLE_SCHEMA = REFERENCED_TABLE_SCHEMA
+	ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION`, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanForeignKeys(rows)
+}
+
+// GetEnumValues returns no values, the enum columns of MySQL list them in their type.
+func (db *MySQL) GetEnumValues(_, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+func (db *MySQL) GetIndexDefinitions(database, table string) ([]models.IndexDefinition, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	// Functional indexes have no column name
+	rows, err := db.Connection.Query(`
+	SELECT INDEX_NAME, NON_UNIQUE = 0, COALESCE(COLUMN_NAME, '')
+	FROM information_schema.STATISTICS
+	WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME <> 'PRIMARY'
+	ORDER BY INDEX_NAME, SEQ_IN_INDEX`, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanIndexDefinitions(rows)
+}
+
 func (db *MySQL) GetIndexes(database, table string) (results [][]string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
@@ -290,7 +800,7 @@ func (db *MySQL) GetIndexes(database, table string) (results [][]string, err err
 	return
 }
 
-func (db *MySQL) GetRecords(database, table, where, sort string, offset, limit int) (paginatedResults [][]string, totalRecords int, err error) {
+func (db *MySQL) GetRecords(database, table string, recordsQuery models.RecordsQuery) (paginatedResults [][]string, totalRecords int, err error) {
 	if table == "" {
 		return nil, 0, errors.New("table name is required")
 	}
@@ -299,24 +809,21 @@ func (db *MySQL) GetRecords(database, table, where, sort string, offset, limit i
 		return nil, 0, errors.New("database name is required")
 	}
 
+	limit := recordsQuery.Limit
 	if limit == 0 {
 		limit = DefaultRowLimit
 	}
 
-	query := "SELECT * FROM "
-	query += db.formatTableName(database, table)
-
-	if where != "" {
-		query += fmt.Sprintf(" %s", where)
+	where, orderBy, args, err := recordsClauses(DriverMySQL, recordsQuery)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if sort != "" {
-		query += fmt.Sprintf(" ORDER BY %s", sort)
-	}
+	formattedTableName := db.formatTableName(database, table)
 
-	query += " LIMIT ?, ?"
+	query := "SELECT * FROM " + formattedTableName + where + orderBy + " LIMIT ?, ?"
 
-	paginatedRows, err := db.Connection.Query(query, offset, limit)
+	paginatedRows, err := db.Connection.Query(query, append(args, recordsQuery.Offset, limit)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -365,10 +872,8 @@ func (db *MySQL) GetRecords(database, table, where, sort string, offset, limit i
 		return nil, 0, err
 	}
 
-	countQuery := "SELECT COUNT(*) FROM "
-	countQuery += fmt.Sprintf("`%s`.", database)
-	countQuery += fmt.Sprintf("`%s`", table)
-	row := db.Connection.QueryRow(countQuery)
+	countQuery := "SELECT COUNT(*) FROM " + formattedTableName + where
+	row := db.Connection.QueryRow(countQuery, args...)
 	if err := row.Scan(&totalRecords); err != nil {
 		return nil, 0, err
 	}
@@ -383,36 +888,7 @@ func (db *MySQL) ExecuteQuery(query string) (results [][]string, err error) {
 	}
 	defer rows.Close()
 
-	columns, err := rows.Columns()
-	if err != nil {
-		return nil, err
-	}
-
-	results = append(results, columns)
-
-	for rows.Next() {
-		rowValues := make([]interface{}, len(columns))
-		for i := range columns {
-			rowValues[i] = new(sql.RawBytes)
-		}
-
-		err = rows.Scan(rowValues...)
-		if err != nil {
-			return nil, err
-		}
-
-		var row []string
-		for _, col := range rowValues {
-			row = append(row, string(*col.(*sql.RawBytes)))
-		}
-
-		results = append(results, row)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return
+	return scanQueryResults(rows)
 }
 
 func (db *MySQL) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
@@ -448,6 +924,10 @@ func (db *MySQL) ExecuteDMLStatement(query string) (result string, err error) {
 	return fmt.Sprintf("%d rows affected", rowsAffected), nil
 }
 
+func (db *MySQL) BeginTransaction() (Transaction, error) {
+	return beginTransaction(db.Connection, nil)
+}
+
 func (db *MySQL) ExecutePendingChanges(changes []models.DbDmlChange) (err error) {
 	var queries []models.Query
 
@@ -506,14 +986,9 @@ func (db *MySQL) ExecutePendingChanges(changes []models.DbDmlChange) (err error)
 
 			copy(args, values)
 
-			for i, pki := range change.PrimaryKeyInfo {
-				if i == 0 {
-					queryStr += fmt.Sprintf(" WHERE `%s` = ?", pki.Name)
-				} else {
-					queryStr += fmt.Sprintf(" AND `%s` = ?", pki.Name)
-				}
-				args = append(args, pki.Value)
-			}
+			condition, conditionArgs := db.rowCondition(change)
+			queryStr += condition
+			args = append(args, conditionArgs...)
 
 			newQuery := models.Query{
 				Query: queryStr,
@@ -525,16 +1000,8 @@ func (db *MySQL) ExecutePendingChanges(changes []models.DbDmlChange) (err error)
 			queryStr := "DELETE FROM "
 			queryStr += db.formatTableName(change.Database, change.Table)
 
-			deleteArgs := make([]interface{}, len(change.PrimaryKeyInfo))
-
-			for i, pki := range change.PrimaryKeyInfo {
-				if i == 0 {
-					queryStr += fmt.Sprintf(" WHERE `%s` = ?", pki.Name)
-				} else {
-					queryStr += fmt.Sprintf(" AND `%s` = ?", pki.Name)
-				}
-				deleteArgs[i] = pki.Value
-			}
+			condition, deleteArgs := db.rowCondition(change)
+			queryStr += condition
 
 			logger.Info("deleteArgs", map[string]any{"deleteArgs": deleteArgs})
 
@@ -598,6 +1065,146 @@ func (db *MySQL) GetProvider() string {
 	return db.Provider
 }
 
+func (db *MySQL) Close() error {
+	return db.Connection.Close()
+}
+
+// GetUniqueKeyColumnNames returns the columns of a unique index that can identify the rows of a
+// table without a primary key.
+func (db *MySQL) GetUniqueKeyColumnNames(database, table string) ([]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	rows, err := db.Connection.Query(`
+	SELECT s.index_name, s.column_name, c.is_nullable = 'YES'
+	FROM information_schema.statistics s
+	JOIN information_schema.columns c
+		ON c.table_schema = s.table_schema AND c.table_name = s.table_name AND c.column_name = s.column_name
+	WHERE s.table_schema = ? AND s.table_name = ? AND s.non_unique = 0
+	ORDER BY s.index_name, s.seq_in_index
+	`, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns := []indexColumn{}
+
+	for rows.Next() {
+		var column indexColumn
+
+		err = rows.Scan(&column.index, &column.column, &column.nullable)
+		if err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, column)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return notNullableIndexColumns(columns), nil
+}
+
+// GetSchemaChangeStatements returns the statements that apply a change to the structure of a table.
+func (db *MySQL) GetSchemaChangeStatements(change models.SchemaChange) ([]string, error) {
+	if err := validateSchemaChange(change); err != nil {
+		return nil, err
+	}
+
+	formattedTableName := db.formatTableName(change.Database, change.Table)
+
+	switch change.Type {
+	case models.SchemaAddColumn:
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", formattedTableName, columnDefinition(change.Column, "`"))}, nil
+	case models.SchemaAlterColumn:
+		// CHANGE COLUMN renames and redefines the column at once, MODIFY COLUMN only redefines it
+		if change.Column.Name != change.OldColumn.Name {
+			return []string{fmt.Sprintf("ALTER TABLE %s CHANGE COLUMN %s %s", formattedTableName, quoteIdentifier(change.OldColumn.Name, "`"), columnDefinition(change.Column, "`"))}, nil
+		}
+
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", formattedTableName, columnDefinition(change.Column, "`"))}, nil
+	case models.SchemaDropColumn:
+		return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", formattedTableName, quoteIdentifier(change.Column.Name, "`"))}, nil
+	case models.SchemaCreateIndex:
+		return []string{createIndexStatement(change.Index, formattedTableName, "`")}, nil
+	case models.SchemaDropIndex:
+		return []string{fmt.Sprintf("DROP INDEX %s ON %s", quoteIdentifier(change.Index.Name, "`"), formattedTableName)}, nil
+	case models.SchemaCreateTable:
+		return []string{createTableStatement(formattedTableName, change.Columns, change.PrimaryKey, "`")}, nil
+	case models.SchemaRenameTable:
+		return []string{fmt.Sprintf("RENAME TABLE %s TO %s", formattedTableName, db.formatTableName(change.Database, change.NewTable))}, nil
+	case models.SchemaTruncateTable:
+		return []string{fmt.Sprintf("TRUNCATE TABLE %s", formattedTableName)}, nil
+	case models.SchemaDropTable:
+		return []string{fmt.Sprintf("DROP TABLE %s", formattedTableName)}, nil
+	case models.SchemaDuplicateTable:
+		newTableName := db.formatTableName(change.Database, change.NewTable)
+
+		// LIKE copies the columns and the indexes, but not the foreign keys
+		statements := []string{fmt.Sprintf("CREATE TABLE %s LIKE %s", newTableName, formattedTableName)}
+		if change.WithData {
+			statements = append(statements, fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", newTableName, formattedTableName))
+		}
+
+		return statements, nil
+	}
+
+	return nil, fmt.Errorf("unsupported schema change type: %d", change.Type)
+}
+
+// ExecuteSchemaStatements runs the statements of a schema change. MySQL commits every DDL
+// statement implicitly, so they can't be rolled back and run one by one until the first error.
+func (db *MySQL) ExecuteSchemaStatements(_ string, statements []string) error {
+	for _, statement := range statements {
+		logger.Info(statement, nil)
+
+		if _, err := db.Connection.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rowCondition returns the WHERE clause that matches the row of an update or delete and its
+// arguments. Without a primary or unique key every column is compared and only the first
+// matching row is changed.
+func (db *MySQL) rowCondition(change models.DbDmlChange) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	for _, pki := range change.PrimaryKeyInfo {
+		if change.FullRowMatch {
+			conditions = append(conditions, fmt.Sprintf("`%s` <=> ?", pki.Name))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("`%s` = ?", pki.Name))
+		}
+
+		if pki.IsNull {
+			args = append(args, nil)
+		} else {
+			args = append(args, pki.Value)
+		}
+	}
+
+	condition := " WHERE " + strings.Join(conditions, " AND ")
+
+	if change.FullRowMatch {
+		condition += " LIMIT 1"
+	}
+
+	return condition, args
+}
+
 func (db *MySQL) formatTableName(database, table string) string {
 	return fmt.Sprintf("`%s`.`%s`", database, table)
 }
@@ -0,0 +1,110 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func Test_parseMySQLGrant(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      models.Grant
+	}{
+		{
+			name:      "global",
+			statement: "GRANT USAGE ON *.* TO `app`@`%`",
+			want:      models.Grant{ObjectType: models.GrantObjectGlobal, Privileges: []string{"USAGE"}},
+		},
+		{
+			name:      "database",
+			statement: "GRANT SELECT, INSERT ON `shop`.* TO `app`@`%` WITH GRANT OPTION",
+			want:      models.Grant{ObjectType: models.GrantObjectDatabase, Object: "shop", Privileges: []string{"SELECT", "INSERT"}, Grantable: true},
+		},
+		{
+			name:      "table columns",
+			statement: "GRANT SELECT (`id`, `name`), DELETE ON `shop`.`orders` TO `app`@`%`",
+			want:      models.Grant{ObjectType: models.GrantObjectTable, Object: "shop.orders", Privileges: []string{"SELECT (`id`, `name`)", "DELETE"}},
+		},
+		{
+			name:      "procedure",
+			statement: "GRANT EXECUTE, ALTER ROUTINE ON PROCEDURE `shop`.`refund` TO `app`@`%`",
+			want:      models.Grant{ObjectType: models.GrantObjectProcedure, Object: "shop.refund", Privileges: []string{"EXECUTE", "ALTER ROUTINE"}},
+		},
+		{
+			name:      "roles",
+			statement: "GRANT `reader`@`%`,`writer`@`localhost` TO `app`@`%`",
+			want:      models.Grant{ObjectType: models.GrantObjectRole, Privileges: []string{"reader@%", "writer@localhost"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := parseMySQLGrant(test.statement)
+			if !ok {
+				t.Fatalf("parseMySQLGrant(%q) did not parse", test.statement)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseMySQLGrant() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMySQL_GetGrantStatement(t *testing.T) {
+	role := models.Role{Name: "app", Host: "%"}
+
+	tests := []struct {
+		name    string
+		grant   models.Grant
+		revoke  bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "grant on a table",
+			grant: models.Grant{Role: role, ObjectType: models.GrantObjectTable, Object: "shop.orders", Privileges: []string{"SELECT", "UPDATE (status)"}, Grantable: true},
+			want:  "GRANT SELECT, UPDATE (status) ON `shop`.`orders` TO `app`@`%` WITH GRANT OPTION",
+		},
+		{
+			name:   "revoke on a database",
+			grant:  models.Grant{Role: role, ObjectType: models.GrantObjectDatabase, Object: "shop", Privileges: []string{"ALL PRIVILEGES"}},
+			revoke: true,
+			want:   "REVOKE ALL PRIVILEGES ON `shop`.* FROM `app`@`%`",
+		},
+		{
+			name:   "revoke on a function",
+			grant:  models.Grant{Role: role, ObjectType: models.GrantObjectFunction, Object: "shop.total", Privileges: []string{"EXECUTE"}},
+			revoke: true,
+			want:   "REVOKE EXECUTE ON FUNCTION `shop`.`total` FROM `app`@`%`",
+		},
+		{
+			name:  "grant a role",
+			grant: models.Grant{Role: role, ObjectType: models.GrantObjectRole, Privileges: []string{"reader"}},
+			want:  "GRANT `reader`@`%` TO `app`@`%`",
+		},
+		{
+			name:    "invalid privilege",
+			grant:   models.Grant{Role: role, ObjectType: models.GrantObjectGlobal, Privileges: []string{"SELECT; DROP DATABASE shop"}},
+			wantErr: true,
+		},
+	}
+
+	db := &MySQL{}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := db.GetGrantStatement(test.grant, test.revoke)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("GetGrantStatement() error = %v, wantErr %v", err, test.wantErr)
+			}
+
+			if got != test.want {
+				t.Errorf("GetGrantStatement() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
-	// import postgresql driver
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/xo/dburl"
 
 	"github.com/jorgerojas26/lazysql/helpers/logger"
@@ -20,6 +22,10 @@ type Postgres struct {
 	CurrentDatabase  string
 	PreviousDatabase string
 	Urlstr           string
+	// sessions keeps the ids of the sessions of the connections of the pool
+	sessions *sessionConnector
+	// openTransactions counts the transactions that still hold a connection of the pool
+	openTransactions atomic.Int32
 }
 
 const (
@@ -33,11 +39,19 @@ func (db *Postgres) TestConnection(urlstr string) error {
 func (db *Postgres) Connect(urlstr string) (err error) {
 	db.SetProvider(DriverPostgres)
 
-	db.Connection, err = dburl.Open(urlstr)
+	parsedURL, err := dburl.Parse(urlstr)
 	if err != nil {
 		return err
 	}
 
+	connector, err := pq.NewConnector(parsedURL.DSN)
+	if err != nil {
+		return err
+	}
+
+	db.sessions = newSessionConnector(connector, "SELECT pg_backend_pid()")
+	db.Connection = sql.OpenDB(db.sessions)
+
 	err = db.Connection.Ping()
 	if err != nil {
 		return err
@@ -106,7 +120,8 @@ func (db *Postgres) GetTables(database string) (tables map[string][]string, err
 		}
 	}()
 
-	query := "SELECT table_name, table_schema FROM information_schema.tables WHERE table_catalog = $1"
+	// Views are listed with the other database objects
+	query := "SELECT table_name, table_schema FROM information_schema.tables WHERE table_catalog = $1 AND table_type <> 'VIEW'"
 	rows, err := db.Connection.Query(query, database)
 	if err != nil {
 		return nil, err
@@ -131,6 +146,610 @@ func (db *Postgres) GetTables(database string) (tables map[string][]string, err
 	return tables, nil
 }
 
+func (db *Postgres) GetObjects(database string) ([]models.DatabaseObject, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Functions and types that belong to extensions are left out, like the system schemas
+	rows, err := db.Connection.Query(`
+	SELECT schemaname, viewname, '', 'view' FROM pg_views
+	WHERE schemaname NOT IN ('pg_catalog', 'information_schema')
+	UNION ALL
+	SELECT schemaname, matviewname, '', 'materialized view' FROM pg_matviews
+	UNION ALL
+	SELECT n.nspname, p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')', '',
+		CASE p.prokind WHEN 'p' THEN 'procedure' ELSE 'function' END
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	WHERE p.prokind IN ('f', 'p') AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e')
+	UNION ALL
+	SELECT n.nspname, t.tgname, c.relname, 'trigger' FROM pg_trigger t
+	JOIN pg_class c ON c.oid = t.tgrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE NOT t.tgisinternal
+	UNION ALL
+	SELECT schemaname, sequencename, '', 'sequence' FROM pg_sequences
+	UNION ALL
+	SELECT n.nspname, t.typname, '', 'type' FROM pg_type t
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	WHERE (t.typtype IN ('e', 'd', 'r')
+		OR (t.typtype = 'c' AND EXISTS (SELECT 1 FROM pg_class c WHERE c.oid = t.typrelid AND c.relkind = 'c')))
+		AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = t.oid AND d.deptype = 'e')
+	ORDER BY 1, 2
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanObjects(rows, database)
+}
+
+func (db *Postgres) GetObjectDefinition(object models.DatabaseObject) (string, error) {
+	if object.Database != db.CurrentDatabase {
+		err := db.SwitchDatabase(object.Database)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var query string
+
+	args := []interface{}{object.Schema, object.Name}
+
+	switch object.Type {
+	case models.ObjectTypeView:
+		query = `SELECT format('CREATE OR REPLACE VIEW %I.%I AS', $1::text, $2::text) || E'\n' ||
+		pg_get_viewdef(format('%I.%I', $1::text, $2::text)::regclass, true)`
+	case models.ObjectTypeMaterializedView:
+		query = `SELECT format('CREATE MATERIALIZED VIEW %I.%I AS', $1::text, $2::text) || E'\n' ||
+		pg_get_viewdef(format('%I.%I', $1::text, $2::text)::regclass, true)`
+	case models.ObjectTypeFunction, models.ObjectTypeProcedure:
+		query = `SELECT pg_get_functiondef(p.oid) FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = $1 AND p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')' = $2`
+	case models.ObjectTypeTrigger:
+		query = `SELECT pg_get_triggerdef(t.oid, true) || ';' FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND t.tgname = $2 AND c.relname = $3`
+		args = append(args, object.Table)
+	case models.ObjectTypeSequence:
+		query = `SELECT format('CREATE SEQUENCE %I.%I AS %s INCREMENT BY %s MINVALUE %s MAXVALUE %s START WITH %s CACHE %s%s;',
+			schemaname, sequencename, data_type, increment_by, min_value, max_value, start_value, cache_size,
+			CASE WHEN cycle THEN ' CYCLE' ELSE '' END)
+		FROM pg_sequences WHERE schemaname = $1 AND sequencename = $2`
+	case models.ObjectTypeType:
+		query = `SELECT CASE t.typtype
+		WHEN 'e' THEN format('CREATE TYPE %I.%I AS ENUM (%s)', n.nspname, t.typname,
+			(SELECT string_agg(quote_literal(e.enumlabel), ', ' ORDER BY e.enumsortorder) FROM pg_enum e WHERE e.enumtypid = t.oid))
+		WHEN 'd' THEN format('CREATE DOMAIN %I.%I AS %s', n.nspname, t.typname, format_type(t.typbasetype, t.typtypmod))
+			|| CASE WHEN t.typnotnull THEN ' NOT NULL' ELSE '' END
+			|| COALESCE(' DEFAULT ' || t.typdefault, '')
+			|| COALESCE((SELECT string_agg(' CONSTRAINT ' || quote_ident(c.conname) || ' ' || pg_get_constraintdef(c.oid), '')
+				FROM pg_constraint c WHERE c.contypid = t.oid), '')
+		WHEN 'r' THEN format('CREATE TYPE %I.%I AS RANGE (SUBTYPE = %s)', n.nspname, t.typname,
+			(SELECT format_type(r.rngsubtype, NULL) FROM pg_range r WHERE r.rngtypid = t.oid))
+		ELSE format('CREATE TYPE %I.%I AS (%s)', n.nspname, t.typname,
+			(SELECT string_agg(quote_ident(a.attname) || ' ' || format_type(a.atttypid, a.atttypmod), ', ' ORDER BY a.attnum)
+			FROM pg_attribute a WHERE a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped))
+		END || ';'
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = $1 AND t.typname = $2`
+	default:
+		return "", fmt.Errorf("unsupported object type: %s", object.Type)
+	}
+
+	var definition sql.NullString
+
+	err := db.Connection.QueryRow(query, args...).Scan(&definition)
+	if err != nil {
+		return "", err
+	}
+
+	return definition.String, nil
+}
+
+// GetTableStats reads the size of a table from pg_class and its activity from
+// pg_stat_user_tables, where the row count is the estimate of the last analyze.
+func (db *Postgres) GetTableStats(database, table string) ([][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	splitTableString := strings.Split(table, ".")
+
+	if len(splitTableString) == 1 {
+		return nil, errors.New("table must be in the format schema.table")
+	}
+
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var rows, tableSize, indexSize, totalSize int64
+	var liveRows, deadRows, sequentialScans, indexScans, modifiedRows sql.NullInt64
+	var lastVacuum, lastAutovacuum, lastAnalyze, lastAutoanalyze, collation sql.NullString
+
+	// reltuples is -1 until the table is vacuumed or analyzed for the first time
+	err := db.Connection.QueryRow(`
+	SELECT GREATEST(c.reltuples, 0)::bigint, pg_table_size(c.oid), pg_indexes_size(c.oid), pg_total_relation_size(c.oid),
+		s.n_live_tup, s.n_dead_tup, s.n_mod_since_analyze, s.seq_scan, s.idx_scan,
+		s.last_vacuum::text, s.last_autovacuum::text, s.last_analyze::text, s.last_autoanalyze::text,
+		(SELECT datcollate FROM pg_database WHERE datname = current_database())
+	FROM pg_class c
+	LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+	WHERE c.oid = $1::regclass`, db.formatTableName(splitTableString[0], splitTableString[1])).
+		Scan(&rows, &tableSize, &indexSize, &totalSize, &liveRows, &deadRows, &modifiedRows, &sequentialScans, &indexScans,
+			&lastVacuum, &lastAutovacuum, &lastAnalyze, &lastAutoanalyze, &collation)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]string{
+		statsHeader,
+		{"Approximate rows", fmt.Sprint(rows)},
+		{"Live rows", fmt.Sprint(liveRows.Int64)},
+		{"Dead rows", fmt.Sprint(deadRows.Int64)},
+		{"Rows modified since analyze", fmt.Sprint(modifiedRows.Int64)},
+		{"Data size", formatSize(tableSize)},
+		{"Index size", formatSize(indexSize)},
+		{"Total size", formatSize(totalSize)},
+		{"Sequential scans", fmt.Sprint(sequentialScans.Int64)},
+		{"Index scans", fmt.Sprint(indexScans.Int64)},
+		{"Last vacuum", lastVacuum.String},
+		{"Last autovacuum", lastAutovacuum.String},
+		{"Last analyze", lastAnalyze.String},
+		{"Last autoanalyze", lastAutoanalyze.String},
+		{"Collation", collation.String},
+	}, nil
+}
+
+// GetDatabaseStats counts the schemas, tables and views of a database and reads its size.
+func (db *Postgres) GetDatabaseStats(database string) ([][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var size, schemas, tables, views, materializedViews, rows int64
+	var encoding, collation string
+
+	err := db.Connection.QueryRow(`
+	WITH relations AS (
+		SELECT c.relkind, c.reltuples
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname NOT IN ('pg_catalog', 'information_schema') AND n.nspname NOT LIKE 'pg_toast%'
+	)
+	SELECT pg_database_size(d.oid),
+		(SELECT COUNT(*) FROM pg_namespace WHERE nspname NOT IN ('pg_catalog', 'information_schema') AND nspname NOT LIKE 'pg_toast%' AND nspname NOT LIKE 'pg_temp%'),
+		(SELECT COUNT(*) FROM relations WHERE relkind IN ('r', 'p')),
+		(SELECT COUNT(*) FROM relations WHERE relkind = 'v'),
+		(SELECT COUNT(*) FROM relations WHERE relkind = 'm'),
+		(SELECT COALESCE(SUM(GREATEST(reltuples, 0)), 0)::bigint FROM relations WHERE relkind IN ('r', 'p')),
+		pg_encoding_to_char(d.encoding), d.datcollate
+	FROM pg_database d
+	WHERE d.datname = $1`, database).
+		Scan(&size, &schemas, &tables, &views, &materializedViews, &rows, &encoding, &collation)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]string{
+		statsHeader,
+		{"Size", formatSize(size)},
+		{"Schemas", fmt.Sprint(schemas)},
+		{"Tables", fmt.Sprint(tables)},
+		{"Views", fmt.Sprint(views)},
+		{"Materialized views", fmt.Sprint(materializedViews)},
+		{"Approximate rows", fmt.Sprint(rows)},
+		{"Encoding", encoding},
+		{"Collation", collation},
+	}, nil
+}
+
+// GetTableDDL returns a CREATE TABLE statement for the table with its constraints and indexes,
+// rebuilt from pg_catalog since PostgreSQL does not keep the original statement. Views get
+// their definition instead.
+func (db *Postgres) GetTableDDL(database, table string) (string, error) {
+	if database == "" {
+		return "", errors.New("database name is required")
+	}
+
+	if table == "" {
+		return "", errors.New("table name is required")
+	}
+
+	splitTableString := strings.Split(table, ".")
+
+	if len(splitTableString) == 1 {
+		return "", errors.New("table must be in the format schema.table")
+	}
+
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	tableSchema := splitTableString[0]
+	tableName := splitTableString[1]
+	formattedTableName := db.formatTableName(tableSchema, tableName)
+
+	var relkind string
+
+	err := db.Connection.QueryRow("SELECT relkind FROM pg_class WHERE oid = $1::regclass", formattedTableName).Scan(&relkind)
+	if err != nil {
+		return "", err
+	}
+
+	switch relkind {
+	case "v":
+		return db.GetObjectDefinition(models.DatabaseObject{Database: database, Schema: tableSchema, Name: tableName, Type: models.ObjectTypeView})
+	case "m":
+		return db.GetObjectDefinition(models.DatabaseObject{Database: database, Schema: tableSchema, Name: tableName, Type: models.ObjectTypeMaterializedView})
+	}
+
+	definitions := []string{}
+
+	columns, err := db.Connection.Query(`
+	SELECT quote_ident(a.attname), format_type(a.atttypid, a.atttypmod), a.attnotnull,
+		COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), a.attidentity, a.attgenerated
+	FROM pg_attribute a
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY a.attnum
+	`, formattedTableName)
+	if err != nil {
+		return "", err
+	}
+	defer columns.Close()
+
+	for columns.Next() {
+		var name, dataType, defaultValue, identity, generated string
+		var notNull bool
+
+		err = columns.Scan(&name, &dataType, &notNull, &defaultValue, &identity, &generated)
+		if err != nil {
+			return "", err
+		}
+
+		definition := fmt.Sprintf("%s %s", name, dataType)
+
+		switch {
+		case generated == "s":
+			definition += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", defaultValue)
+		case identity == "a":
+			definition += " GENERATED ALWAYS AS IDENTITY"
+		case identity == "d":
+			definition += " GENERATED BY DEFAULT AS IDENTITY"
+		case defaultValue != "":
+			definition += " DEFAULT " + defaultValue
+		}
+
+		if notNull {
+			definition += " NOT NULL"
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	if err := columns.Err(); err != nil {
+		return "", err
+	}
+
+	// NOT NULL constraints are already part of the columns
+	constraints, err := db.Connection.Query(`
+	SELECT quote_ident(conname), pg_get_constraintdef(oid, true)
+	FROM pg_constraint
+	WHERE conrelid = $1::regclass AND contype <> 'n'
+	ORDER BY contype <> 'p', contype, conname
+	`, formattedTableName)
+	if err != nil {
+		return "", err
+	}
+	defer constraints.Close()
+
+	for constraints.Next() {
+		var name, definition string
+
+		err = constraints.Scan(&name, &definition)
+		if err != nil {
+			return "", err
+		}
+
+		definitions = append(definitions, fmt.Sprintf("CONSTRAINT %s %s", name, definition))
+	}
+
+	if err := constraints.Err(); err != nil {
+		return "", err
+	}
+
+	ddl := fmt.Sprintf("CREATE TABLE %s (\n    %s\n);", formattedTableName, strings.Join(definitions, ",\n    "))
+
+	// Indexes of primary keys, unique keys and exclusion constraints come with the constraint
+	indexes, err := db.Connection.Query(`
+	SELECT pg_get_indexdef(i.indexrelid)
+	FROM pg_index i
+	WHERE i.indrelid = $1::regclass
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_constraint c
+			WHERE c.conindid = i.indexrelid AND c.conrelid = i.indrelid AND c.contype IN ('p', 'u', 'x')
+		)
+	ORDER BY i.indexrelid
+	`, formattedTableName)
+	if err != nil {
+		return "", err
+	}
+	defer indexes.Close()
+
+	for indexes.Next() {
+		var definition string
+
+		err = indexes.Scan(&definition)
+		if err != nil {
+			return "", err
+		}
+
+		ddl += fmt.Sprintf("\n\n%s;", definition)
+	}
+
+	if err := indexes.Err(); err != nil {
+		return "", err
+	}
+
+	return ddl, nil
+}
+
+func (db *Postgres) GetSessions() ([]models.Session, error) {
+	listedAt := time.Now()
+
+	// The duration of an active session is the one of its query, idle sessions count from their
+	// last state change
+	rows, err := db.Connection.Query(`
+	SELECT pid::text, COALESCE(usename, ''), COALESCE(datname, ''),
+		COALESCE(NULLIF(state, ''), backend_type, ''), COALESCE(wait_event_type || ': ' || wait_event, ''),
+		COALESCE(EXTRACT(EPOCH FROM now() - CASE WHEN state = 'active' THEN query_start ELSE COALESCE(state_change, backend_start) END), 0)::float8,
+		COALESCE(query, ''), array_to_string(pg_blocking_pids(pid), ',')
+	FROM pg_stat_activity
+	WHERE pid <> pg_backend_pid()
+	ORDER BY state = 'active' DESC, 6 DESC, pid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+
+	for rows.Next() {
+		var session models.Session
+		var wait, blockedBy string
+		var seconds float64
+
+		err := rows.Scan(&session.ID, &session.User, &session.Database, &session.State, &wait, &seconds, &session.Query, &blockedBy)
+		if err != nil {
+			return nil, err
+		}
+
+		if wait != "" {
+			session.State += " (" + wait + ")"
+		}
+
+		if blockedBy != "" {
+			session.BlockedBy = strings.Split(blockedBy, ",")
+		}
+
+		session.Duration = time.Duration(seconds * float64(time.Second))
+
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return db.sessions.leaveOut(sessions, listedAt), nil
+}
+
+func (db *Postgres) CancelSession(id string) error {
+	return db.signalSession("pg_cancel_backend", id)
+}
+
+func (db *Postgres) TerminateSession(id string) error {
+	return db.signalSession("pg_terminate_backend", id)
+}
+
+// signalSession calls pg_cancel_backend or pg_terminate_backend, which return false instead of
+// failing when the session is gone.
+func (db *Postgres) signalSession(function, id string) error {
+	pid, err := parseSessionID(id)
+	if err != nil {
+		return err
+	}
+
+	var signaled bool
+
+	err = db.Connection.QueryRow(fmt.Sprintf("SELECT %s($1)", function), pid).Scan(&signaled)
+	if err != nil {
+		return err
+	}
+
+	if !signaled {
+		return fmt.Errorf("session %s could not be signaled, it may have ended already", id)
+	}
+
+	return nil
+}
+
+func (db *Postgres) GetRoles() ([]models.Role, error) {
+	rows, err := db.Connection.Query(`
+	SELECT rolname, rolcanlogin, rolsuper, rolcreatedb, rolcreaterole
+	FROM pg_roles
+	WHERE rolname NOT LIKE 'pg\_%'
+	ORDER BY rolname`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+
+	for rows.Next() {
+		var role models.Role
+		var login, superuser, createDatabase, createRole bool
+
+		if err := rows.Scan(&role.Name, &login, &superuser, &createDatabase, &createRole); err != nil {
+			return nil, err
+		}
+
+		for attribute, isSet := range map[string]bool{"login": login, "superuser": superuser, "create database": createDatabase, "create role": createRole} {
+			if isSet {
+				role.Attributes = append(role.Attributes, attribute)
+			}
+		}
+
+		sort.Strings(role.Attributes)
+
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// GetGrants returns the privileges of a role on the databases and the schemas, on the tables of the
+// current database and the roles it is a member of.
+func (db *Postgres) GetGrants(role models.Role) ([]models.Grant, error) {
+	rows, err := db.Connection.Query(`
+	WITH role AS (
+		SELECT oid FROM pg_roles WHERE rolname = $1
+	)
+	SELECT 1, 'database', d.datname, a.privilege_type, a.is_grantable
+	FROM pg_database d, aclexplode(d.datacl) a
+	WHERE a.grantee = (SELECT oid FROM role)
+	UNION ALL
+	SELECT 2, 'schema', n.nspname, a.privilege_type, a.is_grantable
+	FROM pg_namespace n, aclexplode(n.nspacl) a
+	WHERE a.grantee = (SELECT oid FROM role)
+	UNION ALL
+	SELECT 3, 'table', table_schema || '.' || table_name, privilege_type, is_grantable = 'YES'
+	FROM information_schema.role_table_grants
+	WHERE grantee = $1
+	UNION ALL
+	SELECT 4, 'role', '', g.rolname, m.admin_option
+	FROM pg_auth_members m
+	JOIN pg_roles g ON g.oid = m.roleid
+	WHERE m.member = (SELECT oid FROM role)
+	ORDER BY 1, 3, 5, 4`, role.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []models.Grant{}
+
+	// The privileges of an object come in consecutive rows
+	for rows.Next() {
+		var order int
+		var objectType, object, privilege string
+		var grantable bool
+
+		if err := rows.Scan(&order, &objectType, &object, &privilege, &grantable); err != nil {
+			return nil, err
+		}
+
+		last := len(grants) - 1
+		if last >= 0 && grants[last].ObjectType == objectType && grants[last].Object == object && grants[last].Grantable == grantable {
+			grants[last].Privileges = append(grants[last].Privileges, privilege)
+			continue
+		}
+
+		grants = append(grants, models.Grant{
+			Role:       role,
+			ObjectType: objectType,
+			Object:     object,
+			Privileges: []string{privilege},
+			Grantable:  grantable,
+		})
+	}
+
+	return grants, rows.Err()
+}
+
+func (db *Postgres) GetGrantStatement(grant models.Grant, revoke bool) (string, error) {
+	if err := validateGrant(grant); err != nil {
+		return "", err
+	}
+
+	role := QuoteIdentifier(DriverPostgres, grant.Role.Name)
+	option := " WITH GRANT OPTION"
+
+	var privileges, object string
+
+	switch grant.ObjectType {
+	case models.GrantObjectRole:
+		roles := make([]string, len(grant.Privileges))
+		for i, name := range grant.Privileges {
+			roles[i] = QuoteIdentifier(DriverPostgres, name)
+		}
+
+		privileges = strings.Join(roles, ", ")
+		option = " WITH ADMIN OPTION"
+	case models.GrantObjectDatabase:
+		object = " ON DATABASE " + QuoteIdentifier(DriverPostgres, grant.Object)
+	case models.GrantObjectSchema:
+		object = " ON SCHEMA " + QuoteIdentifier(DriverPostgres, grant.Object)
+	case models.GrantObjectTable:
+		schema, table, ok := strings.Cut(grant.Object, ".")
+		if !ok {
+			schema, table = "public", grant.Object
+		}
+
+		object = " ON TABLE " + QuoteIdentifier(DriverPostgres, schema) + "." + QuoteIdentifier(DriverPostgres, table)
+	default:
+		return "", fmt.Errorf("PostgreSQL has no %s privileges, grant them on a database, a schema or a table", grant.ObjectType)
+	}
+
+	if privileges == "" {
+		privileges = strings.Join(grant.Privileges, ", ")
+	}
+
+	if revoke {
+		return fmt.Sprintf("REVOKE %s%s FROM %s", privileges, object, role), nil
+	}
+
+	statement := fmt.Sprintf("GRANT %s%s TO %s", privileges, object, role)
+	if grant.Grantable {
+		statement += option
+	}
+
+	return statement, nil
+}
+
 func (db *Postgres) GetTableColumns(database, table string) (results [][]string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
@@ -162,7 +781,17 @@ func (db *Postgres) GetTableColumns(database, table string) (results [][]string,
 	tableSchema := splitTableString[0]
 	tableName := splitTableString[1]
 
-	query := "SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns WHERE table_catalog = $1 AND table_schema = $2 AND table_name = $3 ORDER by ordinal_position"
+	// data_type is the type as format_type writes it, with its length or precision and the name
+	// of the user defined and array types, so it can be compared and used in DDL
+	query := `SELECT c.column_name,
+		(SELECT format_type(a.atttypid, a.atttypmod)
+			FROM pg_attribute a
+			WHERE a.attrelid = (quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass
+				AND a.attname = c.column_name) AS data_type,
+		c.is_nullable, c.column_default
+		FROM information_schema.columns c
+		WHERE c.table_catalog = $1 AND c.table_schema = $2 AND c.table_name = $3
+		ORDER BY c.ordinal_position`
 
 	rows, err := db.Connection.Query(query, database, tableSchema, tableName)
 	if err != nil {
@@ -370,6 +999,144 @@ func (db *Postgres) GetForeignKeys(database, table string) (foreignKeys [][]stri
 	return
 }
 
+func (db *Postgres) GetReferencedForeignKeys(database, table string) ([]models.ForeignKey, error) {
+	return db.getForeignKeysOf(database, table, "conrelid")
+}
+
+func (db *Postgres) GetReferencingForeignKeys(database, table string) ([]models.ForeignKey, error) {
+	return db.getForeignKeysOf(database, table, "confrelid")
+}
+
+// getForeignKeysOf returns the foreign keys whose relation column, conrelid for the referencing
+// table or confrelid for the referenced one, is the given table.
+func (db *Postgres) getForeignKeysOf(database, table, relationColumn string) ([]models.ForeignKey, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	splitTableString := strings.Split(table, ".")
+
+	if len(splitTableString) == 1 {
+		return nil, errors.New("table must be in the format schema.table")
+	}
+
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := db.Connection.Query(fmt.Sprintf(`
+	SELECT c.conname, tn.nspname || '.' || t.relname, a.attname, fn.nspname || '.' || ft.relname, fa.attname
+	FROM pg_constraint c
+	CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, fattnum, ord)
+	JOIN pg_class t ON t.oid = c.conrelid
+	JOIN pg_namespace tn ON tn.oid = t.relnamespace
+	JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+	JOIN pg_class ft ON ft.oid = c.confrelid
+	JOIN pg_namespace fn ON fn.oid = ft.relnamespace
+	JOIN pg_attribute fa ON fa.attrelid = c.confrelid AND fa.attnum = k.fattnum
+	WHERE c.contype = 'f' AND c.%s = $1::regclass
+	ORDER BY 2, c.conname, k.ord`, relationColumn), db.formatTableName(splitTableString[0], splitTableString[1]))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanForeignKeys(rows)
+}
+
+func (db *Postgres) GetIndexDefinitions(database, table string) ([]models.IndexDefinition, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	splitTableString := strings.Split(table, ".")
+
+	if len(splitTableString) == 1 {
+		return nil, errors.New("table must be in the format schema.table")
+	}
+
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Expressions of an index have the attribute number 0 and no column name
+	rows, err := db.Connection.Query(`
+	SELECT i.relname, ix.indisunique, COALESCE(a.attname, '')
+	FROM pg_index ix
+	JOIN pg_class i ON i.oid = ix.indexrelid
+	CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+	LEFT JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+	WHERE ix.indrelid = $1::regclass AND NOT ix.indisprimary
+	ORDER BY i.relname, k.ord`, db.formatTableName(splitTableString[0], splitTableString[1]))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanIndexDefinitions(rows)
+}
+
+// GetEnumValues returns the labels of the columns of a table whose type is an enum, in the order
+// of the type.
+func (db *Postgres) GetEnumValues(database, table string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	splitTableString := strings.Split(table, ".")
+
+	if len(splitTableString) == 1 {
+		return nil, errors.New("table must be in the format schema.table")
+	}
+
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := db.Connection.Query(`
+	SELECT a.attname, e.enumlabel
+	FROM pg_attribute a
+	JOIN pg_enum e ON e.enumtypid = a.atttypid
+	WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY a.attnum, e.enumsortorder`, db.formatTableName(splitTableString[0], splitTableString[1]))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := map[string][]string{}
+
+	for rows.Next() {
+		var column, label string
+		if err := rows.Scan(&column, &label); err != nil {
+			return nil, err
+		}
+
+		values[column] = append(values[column], label)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
 func (db *Postgres) GetIndexes(database, table string) (indexes [][]string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
@@ -463,7 +1230,7 @@ func (db *Postgres) GetIndexes(database, table string) (indexes [][]string, err
 	return
 }
 
-func (db *Postgres) GetRecords(database, table, where, sort string, offset, limit int) (records [][]string, totalRecords int, err error) {
+func (db *Postgres) GetRecords(database, table string, recordsQuery models.RecordsQuery) (records [][]string, totalRecords int, err error) {
 	if database == "" {
 		return nil, 0, errors.New("database name is required")
 	}
@@ -498,24 +1265,25 @@ func (db *Postgres) GetRecords(database, table, where, sort string, offset, limi
 
 	formattedTableName := db.formatTableName(tableSchema, tableName)
 
+	limit := recordsQuery.Limit
 	if limit == 0 {
 		limit = DefaultRowLimit
 	}
 
-	query := "SELECT * FROM "
-	query += formattedTableName
-
-	if where != "" {
-		query += fmt.Sprintf(" %s", where)
+	where, orderBy, args, err := recordsClauses(DriverPostgres, recordsQuery)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if sort != "" {
-		query += fmt.Sprintf(" ORDER BY %s", sort)
+	selection := "*"
+	if recordsQuery.RowID {
+		selection = "ctid, *"
 	}
 
-	query += " LIMIT $1 OFFSET $2"
+	query := "SELECT " + selection + " FROM " + formattedTableName + where + orderBy
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 
-	paginatedRows, err := db.Connection.Query(query, limit, offset)
+	paginatedRows, err := db.Connection.Query(query, append(args, limit, recordsQuery.Offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -565,9 +1333,8 @@ func (db *Postgres) GetRecords(database, table, where, sort string, offset, limi
 		return nil, 0, err
 	}
 
-	countQuery := "SELECT COUNT(*) FROM "
-	countQuery += formattedTableName
-	row := db.Connection.QueryRow(countQuery)
+	countQuery := "SELECT COUNT(*) FROM " + formattedTableName + where
+	row := db.Connection.QueryRow(countQuery, args...)
 	if err := row.Scan(&totalRecords); err != nil {
 		return nil, 0, err
 	}
@@ -705,36 +1472,20 @@ func (db *Postgres) ExecuteQuery(query string) (results [][]string, err error) {
 	}
 	defer rows.Close()
 
-	columns, err := rows.Columns()
+	return scanQueryResults(rows)
+}
+
+func (db *Postgres) BeginTransaction() (Transaction, error) {
+	transaction, err := beginTransaction(db.Connection, func() {
+		db.openTransactions.Add(-1)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	results = append(results, columns)
-
-	for rows.Next() {
-		rowValues := make([]interface{}, len(columns))
-		for i := range columns {
-			rowValues[i] = new(sql.RawBytes)
-		}
-
-		err = rows.Scan(rowValues...)
-		if err != nil {
-			return nil, err
-		}
+	db.openTransactions.Add(1)
 
-		var row []string
-		for _, col := range rowValues {
-			row = append(row, string(*col.(*sql.RawBytes)))
-		}
-
-		results = append(results, row)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return
+	return transaction, nil
 }
 
 func (db *Postgres) ExecutePendingChanges(changes []models.DbDmlChange) (err error) {
@@ -812,15 +1563,9 @@ func (db *Postgres) ExecutePendingChanges(changes []models.DbDmlChange) (err err
 				}
 			}
 
-			for i, pki := range change.PrimaryKeyInfo {
-				wherePlaceholder++
-				if i == 0 {
-					queryStr += fmt.Sprintf(" WHERE \"%s\" = $%d", pki.Name, wherePlaceholder)
-				} else {
-					queryStr += fmt.Sprintf(" AND \"%s\" = $%d", pki.Name, wherePlaceholder)
-				}
-				args = append(args, pki.Value)
-			}
+			condition, conditionArgs := db.rowCondition(change, wherePlaceholder+1)
+			queryStr += condition
+			args = append(args, conditionArgs...)
 
 			newQuery := models.Query{
 				Query: queryStr,
@@ -830,16 +1575,8 @@ func (db *Postgres) ExecutePendingChanges(changes []models.DbDmlChange) (err err
 			queries = append(queries, newQuery)
 		case models.DmlDeleteType:
 			queryStr := "DELETE FROM " + formattedTableName
-			args := make([]interface{}, len(change.PrimaryKeyInfo))
-
-			for i, pki := range change.PrimaryKeyInfo {
-				if i == 0 {
-					queryStr += fmt.Sprintf(" WHERE \"%s\" = $%d", pki.Name, i+1)
-				} else {
-					queryStr += fmt.Sprintf(" AND \"%s\" = $%d", pki.Name, i+1)
-				}
-				args[i] = pki.Value
-			}
+			condition, args := db.rowCondition(change, 1)
+			queryStr += condition
 
 			newQuery := models.Query{
 				Query: queryStr,
@@ -923,7 +1660,16 @@ func (db *Postgres) GetProvider() string {
 	return db.Provider
 }
 
+func (db *Postgres) Close() error {
+	return db.Connection.Close()
+}
+
 func (db *Postgres) SwitchDatabase(database string) error {
+	// Switching databases replaces the connection pool, which would close the open transactions
+	if db.openTransactions.Load() > 0 {
+		return errors.New("commit or roll back the open transaction before switching databases")
+	}
+
 	parsedConn, err := dburl.Parse(db.Urlstr)
 	if err != nil {
 		return err
@@ -943,23 +1689,215 @@ func (db *Postgres) SwitchDatabase(database string) error {
 		dbname = database
 	}
 
-	connection, err := sql.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s password=%s dbname='%s' sslmode=disable", host, port, user, password, dbname))
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname='%s' sslmode=disable", host, port, user, password, dbname)
+
+	// Keep the session read-only when the connection was opened that way
+	if readOnly := parsedConn.Query().Get("default_transaction_read_only"); readOnly != "" {
+		dsn += fmt.Sprintf(" default_transaction_read_only=%s", readOnly)
+	}
+
+	connector, err := pq.NewConnector(dsn)
 	if err != nil {
 		return err
 	}
 
+	sessions := newSessionConnector(connector, "SELECT pg_backend_pid()")
+	connection := sql.OpenDB(sessions)
+
 	err = db.Connection.Close()
 	if err != nil {
 		return err
 	}
 
 	db.Connection = connection
+	db.sessions = sessions
 	db.PreviousDatabase = db.CurrentDatabase
 	db.CurrentDatabase = database
 
 	return nil
 }
 
+// GetUniqueKeyColumnNames returns the columns of a unique index that can identify the rows of a
+// table without a primary key.
+func (db *Postgres) GetUniqueKeyColumnNames(database, table string) ([]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	splitTableString := strings.Split(table, ".")
+
+	if len(splitTableString) == 1 {
+		return nil, errors.New("table must be in the format schema.table")
+	}
+
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Partial and expression indexes can not identify a row
+	rows, err := db.Connection.Query(`
+	SELECT i.indexrelid::regclass::text, a.attname, NOT a.attnotnull
+	FROM pg_index i
+	CROSS JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+	JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+	WHERE i.indrelid = $1::regclass AND i.indisunique AND i.indpred IS NULL AND i.indexprs IS NULL
+	ORDER BY i.indexrelid, k.ord
+	`, db.formatTableName(splitTableString[0], splitTableString[1]))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns := []indexColumn{}
+
+	for rows.Next() {
+		var column indexColumn
+
+		err = rows.Scan(&column.index, &column.column, &column.nullable)
+		if err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, column)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return notNullableIndexColumns(columns), nil
+}
+
+// GetSchemaChangeStatements returns the statements that apply a change to the structure of a table.
+func (db *Postgres) GetSchemaChangeStatements(change models.SchemaChange) ([]string, error) {
+	if err := validateSchemaChange(change); err != nil {
+		return nil, err
+	}
+
+	splitTableString := strings.Split(change.Table, ".")
+
+	if len(splitTableString) == 1 {
+		return nil, errors.New("table must be in the format schema.table")
+	}
+
+	tableSchema := splitTableString[0]
+	formattedTableName := db.formatTableName(tableSchema, splitTableString[1])
+
+	switch change.Type {
+	case models.SchemaAddColumn:
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", formattedTableName, columnDefinition(change.Column, `"`))}, nil
+	case models.SchemaAlterColumn:
+		return db.alterColumnStatements(formattedTableName, change.OldColumn, change.Column)
+	case models.SchemaDropColumn:
+		return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", formattedTableName, quoteIdentifier(change.Column.Name, `"`))}, nil
+	case models.SchemaCreateIndex:
+		return []string{createIndexStatement(change.Index, formattedTableName, `"`)}, nil
+	case models.SchemaDropIndex:
+		return []string{fmt.Sprintf("DROP INDEX %s.%s", quoteIdentifier(tableSchema, `"`), quoteIdentifier(change.Index.Name, `"`))}, nil
+	case models.SchemaCreateTable:
+		return []string{createTableStatement(formattedTableName, change.Columns, change.PrimaryKey, `"`)}, nil
+	case models.SchemaRenameTable:
+		return []string{fmt.Sprintf("ALTER TABLE %s RENAME TO %s", formattedTableName, quoteIdentifier(change.NewTable, `"`))}, nil
+	case models.SchemaTruncateTable:
+		return []string{fmt.Sprintf("TRUNCATE TABLE %s", formattedTableName)}, nil
+	case models.SchemaDropTable:
+		return []string{fmt.Sprintf("DROP TABLE %s", formattedTableName)}, nil
+	case models.SchemaDuplicateTable:
+		newTableName := db.formatTableName(tableSchema, change.NewTable)
+
+		// INCLUDING ALL copies the defaults, constraints and indexes, but not the foreign keys
+		statements := []string{fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING ALL)", newTableName, formattedTableName)}
+		if change.WithData {
+			// OVERRIDING SYSTEM VALUE keeps the values of GENERATED ALWAYS identity columns
+			statements = append(statements, fmt.Sprintf("INSERT INTO %s OVERRIDING SYSTEM VALUE SELECT * FROM %s", newTableName, formattedTableName))
+		}
+
+		return statements, nil
+	}
+
+	return nil, fmt.Errorf("unsupported schema change type: %d", change.Type)
+}
+
+// alterColumnStatements renames the column first, so the rest of the changes can be made in a
+// single ALTER TABLE with the new name.
+func (db *Postgres) alterColumnStatements(formattedTableName string, oldColumn, column models.ColumnDefinition) ([]string, error) {
+	statements := []string{}
+
+	columnName := quoteIdentifier(column.Name, `"`)
+
+	if column.Name != oldColumn.Name {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", formattedTableName, quoteIdentifier(oldColumn.Name, `"`), columnName))
+	}
+
+	actions := []string{}
+
+	if column.Type != oldColumn.Type {
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s TYPE %s USING %s::%s", columnName, column.Type, columnName, column.Type))
+	}
+
+	if column.Nullable != oldColumn.Nullable {
+		if column.Nullable {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", columnName))
+		} else {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", columnName))
+		}
+	}
+
+	if column.Default != oldColumn.Default {
+		if column.Default == "" {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", columnName))
+		} else {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", columnName, column.Default))
+		}
+	}
+
+	if len(actions) > 0 {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s %s", formattedTableName, strings.Join(actions, ", ")))
+	}
+
+	if len(statements) == 0 {
+		return nil, errors.New("the column has no changes")
+	}
+
+	return statements, nil
+}
+
+// ExecuteSchemaStatements runs the statements of a schema change in a transaction, DDL
+// statements are transactional in PostgreSQL.
+func (db *Postgres) ExecuteSchemaStatements(database string, statements []string) error {
+	if database != db.CurrentDatabase {
+		err := db.SwitchDatabase(database)
+		if err != nil {
+			return err
+		}
+	}
+
+	return queriesInTransaction(db.Connection, statementsToQueries(statements))
+}
+
+// rowCondition returns the WHERE clause that matches the row of an update or delete and its
+// arguments, numbering the placeholders from placeholderIndex. Without a primary or unique key
+// the row is found by the ctid it was read with.
+func (db *Postgres) rowCondition(change models.DbDmlChange, placeholderIndex int) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	for i, pki := range change.PrimaryKeyInfo {
+		conditions = append(conditions, fmt.Sprintf("\"%s\" = $%d", pki.Name, placeholderIndex+i))
+		args = append(args, pki.Value)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
 func (db *Postgres) formatTableName(database, table string) string {
 	return fmt.Sprintf("\"%s\".\"%s\"", database, table)
 }
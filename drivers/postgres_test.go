@@ -0,0 +1,340 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	gomock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestPostgres_GetTableDDL(t *testing.T) {
+	connection, mock, err := gomock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer connection.Close()
+
+	db := &Postgres{Connection: connection, CurrentDatabase: "shop"}
+
+	mock.ExpectQuery("SELECT relkind FROM pg_class").
+		WithArgs(`"public"."orders"`).
+		WillReturnRows(gomock.NewRows([]string{"relkind"}).AddRow("r"))
+	mock.ExpectQuery("FROM pg_attribute").
+		WillReturnRows(gomock.NewRows([]string{"name", "type", "notnull", "default", "identity", "generated"}).
+			AddRow("id", "integer", true, "", "d", "").
+			AddRow("status", "text", false, "'new'::text", "", "").
+			AddRow("total", "numeric(10,2)", true, "", "", ""))
+	mock.ExpectQuery("FROM pg_constraint").
+		WillReturnRows(gomock.NewRows([]string{"name", "definition"}).
+			AddRow("orders_pkey", "PRIMARY KEY (id)"))
+	mock.ExpectQuery("FROM pg_index").
+		WillReturnRows(gomock.NewRows([]string{"definition"}).
+			AddRow("CREATE INDEX orders_status_idx ON public.orders USING btree (status)"))
+
+	ddl, err := db.GetTableDDL("shop", "public.orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `CREATE TABLE "public"."orders" (
+    id integer GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    status text DEFAULT 'new'::text,
+    total numeric(10,2) NOT NULL,
+    CONSTRAINT orders_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX orders_status_idx ON public.orders USING btree (status);`
+
+	if ddl != want {
+		t.Errorf("GetTableDDL() =\n%s\nwant\n%s", ddl, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPostgres_GetSchemaChangeStatements(t *testing.T) {
+	db := &Postgres{}
+
+	oldColumn := models.ColumnDefinition{Name: "status", Type: "text", Nullable: true, Default: "'new'::text"}
+
+	tests := []struct {
+		name    string
+		change  models.SchemaChange
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "add column",
+			change: models.SchemaChange{Type: models.SchemaAddColumn, Column: models.ColumnDefinition{Name: "total", Type: "numeric(10,2)", Default: "0"}},
+			want:   []string{`ALTER TABLE "public"."orders" ADD COLUMN "total" numeric(10,2) NOT NULL DEFAULT 0`},
+		},
+		{
+			name: "rename and alter column",
+			change: models.SchemaChange{
+				Type:      models.SchemaAlterColumn,
+				OldColumn: oldColumn,
+				Column:    models.ColumnDefinition{Name: "state", Type: "varchar(20)", Nullable: false},
+			},
+			want: []string{
+				`ALTER TABLE "public"."orders" RENAME COLUMN "status" TO "state"`,
+				`ALTER TABLE "public"."orders" ALTER COLUMN "state" TYPE varchar(20) USING "state"::varchar(20), ALTER COLUMN "state" SET NOT NULL, ALTER COLUMN "state" DROP DEFAULT`,
+			},
+		},
+		{
+			name:    "alter column without changes",
+			change:  models.SchemaChange{Type: models.SchemaAlterColumn, OldColumn: oldColumn, Column: oldColumn},
+			wantErr: true,
+		},
+		{
+			name:   "create index",
+			change: models.SchemaChange{Type: models.SchemaCreateIndex, Index: models.IndexDefinition{Name: "orders_status_idx", Columns: []string{"status", "id"}, Unique: true}},
+			want:   []string{`CREATE UNIQUE INDEX "orders_status_idx" ON "public"."orders" ("status", "id")`},
+		},
+		{
+			name:   "drop index",
+			change: models.SchemaChange{Type: models.SchemaDropIndex, Index: models.IndexDefinition{Name: "orders_status_idx"}},
+			want:   []string{`DROP INDEX "public"."orders_status_idx"`},
+		},
+		{
+			name: "create table",
+			change: models.SchemaChange{
+				Type:       models.SchemaCreateTable,
+				Columns:    []models.ColumnDefinition{{Name: "id", Type: "serial"}, {Name: "note", Type: "text", Nullable: true}},
+				PrimaryKey: []string{"id"},
+			},
+			want: []string{`CREATE TABLE "public"."orders" ("id" serial NOT NULL, "note" text, PRIMARY KEY ("id"))`},
+		},
+		{
+			name:   "rename table",
+			change: models.SchemaChange{Type: models.SchemaRenameTable, NewTable: "purchases"},
+			want:   []string{`ALTER TABLE "public"."orders" RENAME TO "purchases"`},
+		},
+		{
+			name:   "duplicate table with data",
+			change: models.SchemaChange{Type: models.SchemaDuplicateTable, NewTable: "orders_copy", WithData: true},
+			want: []string{
+				`CREATE TABLE "public"."orders_copy" (LIKE "public"."orders" INCLUDING ALL)`,
+				`INSERT INTO "public"."orders_copy" OVERRIDING SYSTEM VALUE SELECT * FROM "public"."orders"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change.Database = "shop"
+			tt.change.Table = "public.orders"
+
+			got, err := db.GetSchemaChangeStatements(tt.change)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSchemaChangeStatements() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+				t.Errorf("GetSchemaChangeStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgres_GetReferencingForeignKeys(t *testing.T) {
+	connection, mock, err := gomock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer connection.Close()
+
+	db := &Postgres{Connection: connection, CurrentDatabase: "shop"}
+
+	mock.ExpectQuery(`WHERE c.contype = 'f' AND c.confrelid = \$1::regclass`).
+		WithArgs(`"public"."orders"`).
+		WillReturnRows(gomock.NewRows([]string{"conname", "table", "column", "referenced_table", "referenced_column"}).
+			AddRow("order_items_order_fkey", "public.order_items", "order_id", "public.orders", "id").
+			AddRow("shipments_order_fkey", "sales.shipments", "order_id", "public.orders", "id").
+			AddRow("shipments_order_fkey", "sales.shipments", "order_region", "public.orders", "region"))
+
+	got, err := db.GetReferencingForeignKeys("shop", "public.orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.ForeignKey{
+		{Name: "order_items_order_fkey", Table: "public.order_items", Columns: []string{"order_id"}, ReferencedTable: "public.orders", ReferencedColumns: []string{"id"}},
+		{Name: "shipments_order_fkey", Table: "sales.shipments", Columns: []string{"order_id", "order_region"}, ReferencedTable: "public.orders", ReferencedColumns: []string{"id", "region"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetReferencingForeignKeys() = %+v, want %+v", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPostgres_GetRecords(t *testing.T) {
+	connection, mock, err := gomock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer connection.Close()
+
+	db := &Postgres{Connection: connection, CurrentDatabase: "shop"}
+
+	query := models.RecordsQuery{
+		Where:      "total > 10",
+		Conditions: []models.FilterCondition{{Column: "status", Operator: models.FilterEqual, Value: "paid"}},
+		OrderBy:    []models.OrderBy{{Column: "created_at", Descending: true}},
+		Offset:     50,
+		Limit:      25,
+	}
+
+	mock.ExpectQuery(`^SELECT \* FROM "public"."orders" WHERE \(total > 10\) AND "status" = \$1 ORDER BY "created_at" DESC LIMIT \$2 OFFSET \$3$`).
+		WithArgs("paid", 25, 50).
+		WillReturnRows(gomock.NewRows([]string{"id", "status"}).AddRow("7", "paid"))
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM "public"."orders" WHERE \(total > 10\) AND "status" = \$1$`).
+		WithArgs("paid").
+		WillReturnRows(gomock.NewRows([]string{"count"}).AddRow(51))
+
+	records, total, err := db.GetRecords("shop", "public.orders", query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := [][]string{{"id", "status"}, {"7", "paid"}}; !reflect.DeepEqual(records, want) {
+		t.Errorf("GetRecords() records = %v, want %v", records, want)
+	}
+
+	if total != 51 {
+		t.Errorf("GetRecords() total = %d, want 51", total)
+	}
+
+	query.Where = "1 = 1; DROP TABLE orders"
+	if _, _, err := db.GetRecords("shop", "public.orders", query); err == nil {
+		t.Error("GetRecords() accepted a filter that ends the statement")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPostgres_RowID(t *testing.T) {
+	connection, mock, err := gomock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer connection.Close()
+
+	db := &Postgres{Connection: connection, CurrentDatabase: "shop"}
+
+	mock.ExpectQuery(`^SELECT ctid, \* FROM "public"."logs" LIMIT \$1 OFFSET \$2$`).
+		WithArgs(25, 0).
+		WillReturnRows(gomock.NewRows([]string{"ctid", "message"}).AddRow("(0,2)", "started"))
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM "public"."logs"$`).
+		WillReturnRows(gomock.NewRows([]string{"count"}).AddRow(1))
+
+	records, _, err := db.GetRecords("shop", "public.logs", models.RecordsQuery{Limit: 25, RowID: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := [][]string{{"ctid", "message"}, {"(0,2)", "started"}}; !reflect.DeepEqual(records, want) {
+		t.Errorf("GetRecords() records = %v, want %v", records, want)
+	}
+
+	change := models.DbDmlChange{PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: RowIDColumn(DriverPostgres), Value: "(0,2)"}}}
+
+	condition, args := db.rowCondition(change, 3)
+	if condition != ` WHERE "ctid" = $3` || !reflect.DeepEqual(args, []interface{}{"(0,2)"}) {
+		t.Errorf("rowCondition() = %q, %v, want the ctid", condition, args)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPostgres_GetSessions(t *testing.T) {
+	connection, mock, err := gomock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer connection.Close()
+
+	db := &Postgres{Connection: connection, CurrentDatabase: "shop"}
+
+	mock.ExpectQuery(`FROM pg_stat_activity`).
+		WillReturnRows(gomock.NewRows([]string{"pid", "usename", "datname", "state", "wait", "seconds", "query", "blocked_by"}).
+			AddRow("42", "app", "shop", "active", "Lock: transactionid", 12.4, "UPDATE orders SET total = 0", "7,9").
+			AddRow("7", "admin", "shop", "idle in transaction", "", 90.0, "UPDATE orders SET total = 1", ""))
+
+	got, err := db.GetSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.Session{
+		{ID: "42", User: "app", Database: "shop", State: "active (Lock: transactionid)", Query: "UPDATE orders SET total = 0", BlockedBy: []string{"7", "9"}, Duration: 12400 * time.Millisecond},
+		{ID: "7", User: "admin", Database: "shop", State: "idle in transaction", Query: "UPDATE orders SET total = 1", Duration: 90 * time.Second},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSessions() = %+v, want %+v", got, want)
+	}
+
+	if err := db.CancelSession("42; DROP TABLE orders"); err == nil {
+		t.Error("CancelSession() accepted an id that is not a number")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPostgres_GetGrantStatement(t *testing.T) {
+	role := models.Role{Name: "app"}
+
+	tests := []struct {
+		name   string
+		grant  models.Grant
+		revoke bool
+		want   string
+	}{
+		{
+			name:  "grant on a table",
+			grant: models.Grant{Role: role, ObjectType: models.GrantObjectTable, Object: "sales.orders", Privileges: []string{"SELECT", "INSERT"}, Grantable: true},
+			want:  `GRANT SELECT, INSERT ON TABLE "sales"."orders" TO "app" WITH GRANT OPTION`,
+		},
+		{
+			name:   "revoke on a schema",
+			grant:  models.Grant{Role: role, ObjectType: models.GrantObjectSchema, Object: "sales", Privileges: []string{"USAGE"}},
+			revoke: true,
+			want:   `REVOKE USAGE ON SCHEMA "sales" FROM "app"`,
+		},
+		{
+			name:  "grant a role",
+			grant: models.Grant{Role: role, ObjectType: models.GrantObjectRole, Privileges: []string{"readers"}, Grantable: true},
+			want:  `GRANT "readers" TO "app" WITH ADMIN OPTION`,
+		},
+	}
+
+	db := &Postgres{}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := db.GetGrantStatement(test.grant, test.revoke)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != test.want {
+				t.Errorf("GetGrantStatement() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package drivers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// GetTableSchemas returns the columns, primary key and indexes of the tables of a database. On
+// PostgreSQL only the tables of a schema are returned, and their names don't include it.
+func GetTableSchemas(db Driver, database, schema string) ([]models.TableSchema, error) {
+	tables, err := db.GetTables(database)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for key, keyTables := range tables {
+		for _, table := range keyTables {
+			switch {
+			case db.GetProvider() == DriverPostgres:
+				if key == schema {
+					names = append(names, table)
+				}
+			case db.GetProvider() == DriverSqlite && strings.HasPrefix(table, "sqlite_"):
+				// Internal tables, like sqlite_sequence
+			default:
+				names = append(names, table)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	schemas := make([]models.TableSchema, 0, len(names))
+
+	for _, name := range names {
+		table := name
+		if db.GetProvider() == DriverPostgres {
+			table = schema + "." + name
+		}
+
+		columns, err := db.GetTableColumns(database, table)
+		if err != nil {
+			return nil, err
+		}
+
+		primaryKey, err := db.GetPrimaryKeyColumnNames(database, table)
+		if err != nil {
+			return nil, err
+		}
+
+		indexes, err := db.GetIndexDefinitions(database, table)
+		if err != nil {
+			return nil, err
+		}
+
+		tableSchema := models.TableSchema{Name: name, PrimaryKey: primaryKey, Indexes: indexes}
+
+		for row := 1; row < len(columns); row++ {
+			tableSchema.Columns = append(tableSchema.Columns, ColumnDefinitionFromRow(db.GetProvider(), columns, row))
+		}
+
+		schemas = append(schemas, tableSchema)
+	}
+
+	return schemas, nil
+}
+
+// DiffSchemas returns the differences between the tables of a source and a target, with the
+// changes that make the target match the source. They are sorted by table, and within a table
+// the dropped indexes come before the columns and the created indexes after them, so the
+// indexes never refer to missing columns. A changed index is dropped and created again.
+func DiffSchemas(source, target []models.TableSchema) []models.SchemaDifference {
+	sourceTables := map[string]models.TableSchema{}
+	targetTables := map[string]models.TableSchema{}
+	names := []string{}
+
+	for _, table := range source {
+		sourceTables[table.Name] = table
+		names = append(names, table.Name)
+	}
+
+	for _, table := range target {
+		targetTables[table.Name] = table
+		if _, ok := sourceTables[table.Name]; !ok {
+			names = append(names, table.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	differences := []models.SchemaDifference{}
+
+	for _, name := range names {
+		sourceTable, inSource := sourceTables[name]
+		targetTable, inTarget := targetTables[name]
+
+		switch {
+		case !inTarget:
+			changes := []models.SchemaChange{{Type: models.SchemaCreateTable, Table: name, Columns: sourceTable.Columns, PrimaryKey: sourceTable.PrimaryKey}}
+			for _, index := range sourceTable.Indexes {
+				changes = append(changes, models.SchemaChange{Type: models.SchemaCreateIndex, Table: name, Index: index})
+			}
+
+			differences = append(differences, models.SchemaDifference{
+				Type:    models.DiffAdded,
+				Table:   name,
+				Detail:  "table " + name,
+				Changes: changes,
+			})
+		case !inSource:
+			differences = append(differences, models.SchemaDifference{
+				Type:    models.DiffRemoved,
+				Table:   name,
+				Detail:  "table " + name,
+				Changes: []models.SchemaChange{{Type: models.SchemaDropTable, Table: name}},
+			})
+		default:
+			differences = append(differences, diffTables(sourceTable, targetTable)...)
+		}
+	}
+
+	return differences
+}
+
+// diffTables returns the differences between two versions of a table.
+func diffTables(source, target models.TableSchema) []models.SchemaDifference {
+	name := source.Name
+	differences := []models.SchemaDifference{}
+	created := []models.SchemaDifference{}
+
+	sourceIndexes := map[string]models.IndexDefinition{}
+	for _, index := range source.Indexes {
+		sourceIndexes[index.Name] = index
+	}
+
+	targetIndexes := map[string]models.IndexDefinition{}
+	for _, index := range target.Indexes {
+		targetIndexes[index.Name] = index
+
+		sourceIndex, ok := sourceIndexes[index.Name]
+
+		switch {
+		case !ok:
+			differences = append(differences, models.SchemaDifference{
+				Type:    models.DiffRemoved,
+				Table:   name,
+				Index:   index.Name,
+				Detail:  fmt.Sprintf("index %s on %s", index.Name, name),
+				Changes: []models.SchemaChange{{Type: models.SchemaDropIndex, Table: name, Index: index}},
+			})
+		case !equalIndexes(sourceIndex, index):
+			differences = append(differences, models.SchemaDifference{
+				Type:    models.DiffChanged,
+				Table:   name,
+				Index:   index.Name,
+				Detail:  fmt.Sprintf("index %s on %s (%s -> %s)", index.Name, name, describeIndex(index), describeIndex(sourceIndex)),
+				Changes: []models.SchemaChange{{Type: models.SchemaDropIndex, Table: name, Index: index}},
+			})
+			created = append(created, models.SchemaDifference{
+				Type:    models.DiffChanged,
+				Table:   name,
+				Index:   index.Name,
+				Detail:  fmt.Sprintf("index %s on %s, created again", index.Name, name),
+				Changes: []models.SchemaChange{{Type: models.SchemaCreateIndex, Table: name, Index: sourceIndex}},
+			})
+		}
+	}
+
+	for _, index := range source.Indexes {
+		if _, ok := targetIndexes[index.Name]; !ok {
+			created = append(created, models.SchemaDifference{
+				Type:    models.DiffAdded,
+				Table:   name,
+				Index:   index.Name,
+				Detail:  fmt.Sprintf("index %s on %s", index.Name, name),
+				Changes: []models.SchemaChange{{Type: models.SchemaCreateIndex, Table: name, Index: index}},
+			})
+		}
+	}
+
+	targetColumns := map[string]models.ColumnDefinition{}
+	for _, column := range target.Columns {
+		targetColumns[column.Name] = column
+	}
+
+	sourceColumns := map[string]bool{}
+
+	for _, column := range source.Columns {
+		sourceColumns[column.Name] = true
+
+		targetColumn, ok := targetColumns[column.Name]
+
+		switch {
+		case !ok:
+			differences = append(differences, models.SchemaDifference{
+				Type:    models.DiffAdded,
+				Table:   name,
+				Column:  column.Name,
+				Detail:  fmt.Sprintf("column %s.%s %s", name, column.Name, describeColumn(column)),
+				Changes: []models.SchemaChange{{Type: models.SchemaAddColumn, Table: name, Column: column}},
+			})
+		case !equalColumns(column, targetColumn):
+			differences = append(differences, models.SchemaDifference{
+				Type:    models.DiffChanged,
+				Table:   name,
+				Column:  column.Name,
+				Detail:  fmt.Sprintf("column %s.%s (%s -> %s)", name, column.Name, describeColumn(targetColumn), describeColumn(column)),
+				Changes: []models.SchemaChange{{Type: models.SchemaAlterColumn, Table: name, Column: column, OldColumn: targetColumn}},
+			})
+		}
+	}
+
+	for _, column := range target.Columns {
+		if !sourceColumns[column.Name] {
+			differences = append(differences, models.SchemaDifference{
+				Type:    models.DiffRemoved,
+				Table:   name,
+				Column:  column.Name,
+				Detail:  fmt.Sprintf("column %s.%s", name, column.Name),
+				Changes: []models.SchemaChange{{Type: models.SchemaDropColumn, Table: name, Column: column}},
+			})
+		}
+	}
+
+	// The primary key can't be changed with the schema changes of the drivers
+	if !strings.EqualFold(strings.Join(source.PrimaryKey, ","), strings.Join(target.PrimaryKey, ",")) {
+		differences = append(differences, models.SchemaDifference{
+			Type:   models.DiffChanged,
+			Table:  name,
+			Detail: fmt.Sprintf("primary key of %s (%s -> %s)", name, strings.Join(target.PrimaryKey, ", "), strings.Join(source.PrimaryKey, ", ")),
+		})
+	}
+
+	return append(differences, created...)
+}
+
+func equalColumns(a, b models.ColumnDefinition) bool {
+	return strings.EqualFold(a.Type, b.Type) && a.Nullable == b.Nullable && a.Default == b.Default
+}
+
+func equalIndexes(a, b models.IndexDefinition) bool {
+	return a.Unique == b.Unique && strings.EqualFold(strings.Join(a.Columns, ","), strings.Join(b.Columns, ","))
+}
+
+func describeColumn(column models.ColumnDefinition) string {
+	description := column.Type
+
+	if !column.Nullable {
+		description += " NOT NULL"
+	}
+
+	if column.Default != "" {
+		description += " DEFAULT " + column.Default
+	}
+
+	return description
+}
+
+func describeIndex(index models.IndexDefinition) string {
+	description := strings.Join(index.Columns, ", ")
+
+	if index.Unique {
+		description = "unique " + description
+	}
+
+	return description
+}
+
+// MigrationScript returns the statements of the changes of some differences in the dialect of
+// the target driver, each difference preceded by a comment that describes it. The changes that
+// the target can't make are left as comments.
+func MigrationScript(target Driver, database, schema string, differences []models.SchemaDifference) string {
+	var script strings.Builder
+
+	for _, difference := range differences {
+		if script.Len() > 0 {
+			script.WriteString("\n")
+		}
+
+		script.WriteString(fmt.Sprintf("-- %s %s\n", difference.Type, difference.Detail))
+
+		if len(difference.Changes) == 0 {
+			script.WriteString("-- not migrated, it has to be changed by hand\n")
+		}
+
+		for _, change := range difference.Changes {
+			change.Database = database
+			if schema != "" {
+				change.Table = schema + "." + change.Table
+			}
+
+			statements, err := target.GetSchemaChangeStatements(change)
+			if err != nil {
+				script.WriteString(fmt.Sprintf("-- not migrated: %s\n", err.Error()))
+				continue
+			}
+
+			for _, statement := range statements {
+				script.WriteString(statement + ";\n")
+			}
+		}
+	}
+
+	return script.String()
+}
+
+// ColumnDefinitionFromRow reads the definition of a column from a row of the columns of a table,
+// which are the output of DESCRIBE in MySQL, information_schema.columns in PostgreSQL and
+// PRAGMA table_info in SQLite.
+func ColumnDefinitionFromRow(provider string, columns [][]string, row int) models.ColumnDefinition {
+	column := models.ColumnDefinition{
+		Name:    TableColumnValue(columns, row, "Field", "column_name", "name"),
+		Type:    TableColumnValue(columns, row, "Type", "data_type"),
+		Default: TableColumnValue(columns, row, "Default", "column_default", "dflt_value"),
+	}
+
+	if notNull := TableColumnValue(columns, row, "notnull"); notNull != "" {
+		column.Nullable = notNull == "0"
+	} else {
+		column.Nullable = strings.EqualFold(TableColumnValue(columns, row, "Null", "is_nullable"), "YES")
+	}
+
+	if provider == DriverMySQL {
+		extra := TableColumnValue(columns, row, "Extra")
+		column.Default = mysqlDefaultExpression(column.Default, extra)
+		column.Extra = mysqlColumnAttributes(extra)
+	}
+
+	return column
+}
+
+// mysqlDefaultExpression quotes the default value shown by DESCRIBE unless it is a number or an
+// expression, because MySQL shows string defaults without quotes.
+func mysqlDefaultExpression(value, extra string) string {
+	if value == "" || strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") || strings.HasPrefix(strings.ToUpper(value), "CURRENT_TIMESTAMP") {
+		return value
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// mysqlColumnAttributes keeps the attributes of the Extra column of DESCRIBE that MODIFY COLUMN
+// would drop, the rest of them are informative.
+func mysqlColumnAttributes(extra string) string {
+	attributes := []string{}
+	lowerExtra := strings.ToLower(extra)
+
+	if strings.Contains(lowerExtra, "auto_increment") {
+		attributes = append(attributes, "AUTO_INCREMENT")
+	}
+
+	if i := strings.Index(lowerExtra, "on update "); i >= 0 {
+		attributes = append(attributes, "ON UPDATE "+extra[i+len("on update "):])
+	}
+
+	return strings.Join(attributes, " ")
+}
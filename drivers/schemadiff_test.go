@@ -0,0 +1,142 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	id := models.ColumnDefinition{Name: "id", Type: "integer"}
+	name := models.ColumnDefinition{Name: "name", Type: "text", Nullable: true}
+	email := models.ColumnDefinition{Name: "email", Type: "text", Nullable: true}
+
+	source := []models.TableSchema{
+		{
+			Name:       "customers",
+			Columns:    []models.ColumnDefinition{id, {Name: "name", Type: "text"}, email},
+			PrimaryKey: []string{"id"},
+			Indexes:    []models.IndexDefinition{{Name: "idx_customers", Columns: []string{"email"}}},
+		},
+		{Name: "orders", Columns: []models.ColumnDefinition{id}, PrimaryKey: []string{"id"}},
+	}
+
+	target := []models.TableSchema{
+		{
+			Name:       "customers",
+			Columns:    []models.ColumnDefinition{id, name, {Name: "phone", Type: "text", Nullable: true}},
+			PrimaryKey: []string{"id"},
+			Indexes:    []models.IndexDefinition{{Name: "idx_customers", Columns: []string{"name"}, Unique: true}},
+		},
+		{Name: "legacy", Columns: []models.ColumnDefinition{id}},
+	}
+
+	got := DiffSchemas(source, target)
+
+	want := []models.SchemaDifference{
+		{
+			Type:    models.DiffChanged,
+			Table:   "customers",
+			Index:   "idx_customers",
+			Detail:  "index idx_customers on customers (unique name -> email)",
+			Changes: []models.SchemaChange{{Type: models.SchemaDropIndex, Table: "customers", Index: target[0].Indexes[0]}},
+		},
+		{
+			Type:    models.DiffChanged,
+			Table:   "customers",
+			Column:  "name",
+			Detail:  "column customers.name (text -> text NOT NULL)",
+			Changes: []models.SchemaChange{{Type: models.SchemaAlterColumn, Table: "customers", Column: source[0].Columns[1], OldColumn: name}},
+		},
+		{
+			Type:    models.DiffAdded,
+			Table:   "customers",
+			Column:  "email",
+			Detail:  "column customers.email text",
+			Changes: []models.SchemaChange{{Type: models.SchemaAddColumn, Table: "customers", Column: email}},
+		},
+		{
+			Type:    models.DiffRemoved,
+			Table:   "customers",
+			Column:  "phone",
+			Detail:  "column customers.phone",
+			Changes: []models.SchemaChange{{Type: models.SchemaDropColumn, Table: "customers", Column: target[0].Columns[2]}},
+		},
+		{
+			Type:    models.DiffChanged,
+			Table:   "customers",
+			Index:   "idx_customers",
+			Detail:  "index idx_customers on customers, created again",
+			Changes: []models.SchemaChange{{Type: models.SchemaCreateIndex, Table: "customers", Index: source[0].Indexes[0]}},
+		},
+		{
+			Type:    models.DiffRemoved,
+			Table:   "legacy",
+			Detail:  "table legacy",
+			Changes: []models.SchemaChange{{Type: models.SchemaDropTable, Table: "legacy"}},
+		},
+		{
+			Type:    models.DiffAdded,
+			Table:   "orders",
+			Detail:  "table orders",
+			Changes: []models.SchemaChange{{Type: models.SchemaCreateTable, Table: "orders", Columns: []models.ColumnDefinition{id}, PrimaryKey: []string{"id"}}},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffSchemas() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMigrationScript(t *testing.T) {
+	differences := []models.SchemaDifference{
+		{
+			Type:    models.DiffAdded,
+			Table:   "customers",
+			Column:  "email",
+			Detail:  "column customers.email text",
+			Changes: []models.SchemaChange{{Type: models.SchemaAddColumn, Table: "customers", Column: models.ColumnDefinition{Name: "email", Type: "text", Nullable: true}}},
+		},
+		{
+			Type:   models.DiffChanged,
+			Table:  "customers",
+			Detail: "primary key of customers (id -> id, region)",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		target Driver
+		schema string
+		want   string
+	}{
+		{
+			name:   "MySQL",
+			target: &MySQL{},
+			want: "-- + column customers.email text\n" +
+				"ALTER TABLE `shop`.`customers` ADD COLUMN `email` text;\n" +
+				"\n" +
+				"-- ~ primary key of customers (id -> id, region)\n" +
+				"-- not migrated, it has to be changed by hand\n",
+		},
+		{
+			name:   "PostgreSQL",
+			target: &Postgres{},
+			schema: "sales",
+			want: "-- + column customers.email text\n" +
+				`ALTER TABLE "sales"."customers" ADD COLUMN "email" text;` + "\n" +
+				"\n" +
+				"-- ~ primary key of customers (id -> id, region)\n" +
+				"-- not migrated, it has to be changed by hand\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := MigrationScript(test.target, "shop", test.schema, differences); got != test.want {
+				t.Errorf("MigrationScript() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
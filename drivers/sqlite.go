@@ -12,6 +12,14 @@ import (
 	"github.com/jorgerojas26/lazysql/models"
 )
 
+// errSQLiteSessions is returned by the session methods, SQLite is an embedded database without
+// server sessions.
+var errSQLiteSessions = errors.New("SQLite has no server sessions to monitor")
+
+// errSQLiteRoles is returned by the role methods, access to SQLite databases is controlled by the
+// permissions of their files.
+var errSQLiteRoles = errors.New("SQLite has no users or roles, access is controlled by the permissions of the database file")
+
 type SQLite struct {
 	Connection *sql.DB
 	Provider   string
@@ -94,6 +102,179 @@ func (db *SQLite) GetTables(database string) (map[string][]string, error) {
 	return tables, nil
 }
 
+func (db *SQLite) GetObjects(database string) ([]models.DatabaseObject, error) {
+	rows, err := db.Connection.Query(`
+	SELECT '', name, CASE WHEN type = 'trigger' THEN tbl_name ELSE '' END, type
+	FROM sqlite_master
+	WHERE type IN ('view', 'trigger')
+	ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanObjects(rows, database)
+}
+
+func (db *SQLite) GetObjectDefinition(object models.DatabaseObject) (string, error) {
+	var definition sql.NullString
+
+	err := db.Connection.QueryRow("SELECT sql FROM sqlite_master WHERE type = ? AND name = ?", object.Type, object.Name).Scan(&definition)
+	if err != nil {
+		return "", err
+	}
+
+	return definition.String + ";", nil
+}
+
+// GetTableStats counts the rows of a table and sums its pages and the pages of its indexes
+// from the dbstat virtual table.
+func (db *SQLite) GetTableStats(_, table string) ([][]string, error) {
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	var rows, tableSize, indexSize int64
+
+	// SQLite keeps no row count, so the rows are counted
+	err := db.Connection.QueryRow("SELECT COUNT(*) FROM " + db.formatTableName(table)).Scan(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Connection.QueryRow(`
+	SELECT COALESCE(SUM(CASE WHEN name = ? THEN pgsize END), 0),
+		COALESCE(SUM(CASE WHEN name <> ? THEN pgsize END), 0)
+	FROM dbstat
+	WHERE name = ? OR name IN (SELECT name FROM pragma_index_list(?))`, table, table, table, table).
+		Scan(&tableSize, &indexSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// sqlite_sequence only exists once a table with AUTOINCREMENT was created
+	autoIncrement := ""
+
+	var sequences int
+	err = db.Connection.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'sqlite_sequence'").Scan(&sequences)
+	if err != nil {
+		return nil, err
+	}
+
+	if sequences > 0 {
+		err = db.Connection.QueryRow("SELECT CAST(seq AS TEXT) FROM sqlite_sequence WHERE name = ?", table).Scan(&autoIncrement)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	return [][]string{
+		statsHeader,
+		{"Rows", fmt.Sprint(rows)},
+		{"Data size", formatSize(tableSize)},
+		{"Index size", formatSize(indexSize)},
+		{"Total size", formatSize(tableSize + indexSize)},
+		{"Auto increment", autoIncrement},
+	}, nil
+}
+
+// GetDatabaseStats reads the size of the database file from its pragmas and counts its objects.
+func (db *SQLite) GetDatabaseStats(_ string) ([][]string, error) {
+	var pageCount, pageSize, freePages, tables, views, indexes, triggers int64
+	var encoding, journalMode string
+
+	err := db.Connection.QueryRow(`
+	SELECT (SELECT page_count FROM pragma_page_count()), (SELECT page_size FROM pragma_page_size()),
+		(SELECT freelist_count FROM pragma_freelist_count()), (SELECT encoding FROM pragma_encoding()),
+		(SELECT journal_mode FROM pragma_journal_mode()),
+		(SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'),
+		(SELECT COUNT(*) FROM sqlite_master WHERE type = 'view'),
+		(SELECT COUNT(*) FROM sqlite_master WHERE type = 'index'),
+		(SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger')`).
+		Scan(&pageCount, &pageSize, &freePages, &encoding, &journalMode, &tables, &views, &indexes, &triggers)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]string{
+		statsHeader,
+		{"Size", formatSize(pageCount * pageSize)},
+		{"Free space", formatSize(freePages * pageSize)},
+		{"Tables", fmt.Sprint(tables)},
+		{"Views", fmt.Sprint(views)},
+		{"Indexes", fmt.Sprint(indexes)},
+		{"Triggers", fmt.Sprint(triggers)},
+		{"Page size", formatSize(pageSize)},
+		{"Encoding", encoding},
+		{"Journal mode", journalMode},
+	}, nil
+}
+
+// GetTableDDL returns the statements that created a table or view, with its indexes and triggers.
+func (db *SQLite) GetTableDDL(_, table string) (string, error) {
+	if table == "" {
+		return "", errors.New("table name is required")
+	}
+
+	// Automatic indexes of primary and unique keys have no sql
+	rows, err := db.Connection.Query(`
+	SELECT sql FROM sqlite_master
+	WHERE tbl_name = ? AND sql IS NOT NULL
+	ORDER BY type NOT IN ('table', 'view'), type, name
+	`, table)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	statements := []string{}
+
+	for rows.Next() {
+		var statement string
+
+		err = rows.Scan(&statement)
+		if err != nil {
+			return "", err
+		}
+
+		statements = append(statements, statement+";")
+	}
+
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	if len(statements) == 0 {
+		return "", fmt.Errorf("table %s not found", table)
+	}
+
+	return strings.Join(statements, "\n\n"), nil
+}
+
+func (db *SQLite) GetSessions() ([]models.Session, error) {
+	return nil, errSQLiteSessions
+}
+
+func (db *SQLite) CancelSession(_ string) error {
+	return errSQLiteSessions
+}
+
+func (db *SQLite) TerminateSession(_ string) error {
+	return errSQLiteSessions
+}
+
+func (db *SQLite) GetRoles() ([]models.Role, error) {
+	return nil, errSQLiteRoles
+}
+
+func (db *SQLite) GetGrants(_ models.Role) ([]models.Grant, error) {
+	return nil, errSQLiteRoles
+}
+
+func (db *SQLite) GetGrantStatement(_ models.Grant, _ bool) (string, error) {
+	return "", errSQLiteRoles
+}
+
 func (db *SQLite) GetTableColumns(_, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
@@ -239,6 +420,99 @@ func (db *SQLite) GetForeignKeys(_, table string) (results [][]string, err error
 	return
 }
 
+func (db *SQLite) GetReferencedForeignKeys(database, table string) ([]models.ForeignKey, error) {
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	// SQLite foreign keys have no name, their id is unique within the table
+	rows, err := db.Connection.Query(`
+	SELECT CAST(id AS TEXT), ?, "from", "table", "to"
+	FROM pragma_foreign_key_list(?)
+	ORDER BY id, seq`, table, table)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys, err := scanForeignKeys(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.fillReferencedColumns(database, foreignKeys)
+}
+
+func (db *SQLite) GetReferencingForeignKeys(database, table string) ([]models.ForeignKey, error) {
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	rows, err := db.Connection.Query(`
+	SELECT CAST(f.id AS TEXT), m.name, f."from", f."table", f."to"
+	FROM sqlite_master m
+	JOIN pragma_foreign_key_list(m.name) f
+	WHERE m.type = 'table' AND f."table" = ? COLLATE NOCASE
+	ORDER BY m.name, f.id, f.seq`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys, err := scanForeignKeys(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.fillReferencedColumns(database, foreignKeys)
+}
+
+// fillReferencedColumns sets the referenced columns that a foreign key leaves out, which are the
+// primary key of the referenced table.
+func (db *SQLite) fillReferencedColumns(database string, foreignKeys []models.ForeignKey) ([]models.ForeignKey, error) {
+	for i, foreignKey := range foreignKeys {
+		if foreignKey.ReferencedColumns[0] != "" {
+			continue
+		}
+
+		primaryKey, err := db.GetPrimaryKeyColumnNames(database, foreignKey.ReferencedTable)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(primaryKey) != len(foreignKey.Columns) {
+			return nil, fmt.Errorf("the foreign key of %s references the primary key of %s, which has %d columns", foreignKey.Table, foreignKey.ReferencedTable, len(primaryKey))
+		}
+
+		foreignKeys[i].ReferencedColumns = primaryKey
+	}
+
+	return foreignKeys, nil
+}
+
+// GetEnumValues returns no values, SQLite has no enum types.
+func (db *SQLite) GetEnumValues(_, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+func (db *SQLite) GetIndexDefinitions(_, table string) ([]models.IndexDefinition, error) {
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	// Only the indexes created with CREATE INDEX, the ones of the PRIMARY KEY and UNIQUE
+	// constraints are part of the table definition
+	rows, err := db.Connection.Query(`
+	SELECT l.name, l."unique", COALESCE(i.name, '')
+	FROM pragma_index_list(?) l
+	JOIN pragma_index_info(l.name) i
+	WHERE l.origin = 'c'
+	ORDER BY l.name, i.seqno`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanIndexDefinitions(rows)
+}
+
 func (db *SQLite) GetIndexes(_, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
@@ -286,29 +560,31 @@ func (db *SQLite) GetIndexes(_, table string) (results [][]string, err error) {
 	return
 }
 
-func (db *SQLite) GetRecords(_, table, where, sort string, offset, limit int) (paginatedResults [][]string, totalRecords int, err error) {
+func (db *SQLite) GetRecords(_, table string, recordsQuery models.RecordsQuery) (paginatedResults [][]string, totalRecords int, err error) {
 	if table == "" {
 		return nil, 0, errors.New("table name is required")
 	}
 
+	limit := recordsQuery.Limit
 	if limit == 0 {
 		limit = DefaultRowLimit
 	}
 
-	query := "SELECT * FROM "
-	query += db.formatTableName(table)
-
-	if where != "" {
-		query += fmt.Sprintf(" %s", where)
+	where, orderBy, args, err := recordsClauses(DriverSqlite, recordsQuery)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if sort != "" {
-		query += fmt.Sprintf(" ORDER BY %s", sort)
+	formattedTableName := db.formatTableName(table)
+
+	selection := "*"
+	if recordsQuery.RowID {
+		selection = "rowid, *"
 	}
 
-	query += " LIMIT ?, ?"
+	query := "SELECT " + selection + " FROM " + formattedTableName + where + orderBy + " LIMIT ?, ?"
 
-	paginatedRows, err := db.Connection.Query(query, offset, limit)
+	paginatedRows, err := db.Connection.Query(query, append(args, recordsQuery.Offset, limit)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -358,9 +634,8 @@ func (db *SQLite) GetRecords(_, table, where, sort string, offset, limit int) (p
 		return nil, 0, err
 	}
 
-	countQuery := "SELECT COUNT(*) FROM "
-	countQuery += db.formatTableName(table)
-	row := db.Connection.QueryRow(countQuery)
+	countQuery := "SELECT COUNT(*) FROM " + formattedTableName + where
+	row := db.Connection.QueryRow(countQuery, args...)
 	if err := row.Scan(&totalRecords); err != nil {
 		return nil, 0, err
 	}
@@ -375,40 +650,7 @@ func (db *SQLite) ExecuteQuery(query string) (results [][]string, err error) {
 	}
 	defer rows.Close()
 
-	columns, err := rows.Columns()
-	if err != nil {
-		return nil, err
-	}
-
-	results = append(results, columns)
-
-	for rows.Next() {
-		rowValues := make([]interface{}, len(columns))
-		for i := range columns {
-			rowValues[i] = new(sql.RawBytes)
-		}
-
-		err = rows.Scan(rowValues...)
-		if err != nil {
-			return nil, err
-		}
-
-		var row []string
-		for _, col := range rowValues {
-			if col == nil {
-				row = append(row, "NULL")
-			} else {
-				row = append(row, string(*col.(*sql.RawBytes)))
-			}
-		}
-
-		results = append(results, row)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return
+	return scanQueryResults(rows)
 }
 
 func (db *SQLite) UpdateRecord(_, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
@@ -477,6 +719,10 @@ func (db *SQLite) ExecuteDMLStatement(query string) (result string, err error) {
 	return fmt.Sprintf("%d rows affected", rowsAffected), nil
 }
 
+func (db *SQLite) BeginTransaction() (Transaction, error) {
+	return beginTransaction(db.Connection, nil)
+}
+
 func (db *SQLite) ExecutePendingChanges(changes []models.DbDmlChange) (err error) {
 	var queries []models.Query
 
@@ -555,14 +801,9 @@ func (db *SQLite) ExecutePendingChanges(changes []models.DbDmlChange) (err error
 
 			copy(args, values)
 
-			for i, pki := range change.PrimaryKeyInfo {
-				if i == 0 {
-					queryStr += fmt.Sprintf(" WHERE `%s` = ?", pki.Name)
-				} else {
-					queryStr += fmt.Sprintf(" AND `%s` = ?", pki.Name)
-				}
-				args = append(args, pki.Value)
-			}
+			condition, conditionArgs := db.rowCondition(change)
+			queryStr += condition
+			args = append(args, conditionArgs...)
 
 			newQuery := models.Query{
 				Query: queryStr,
@@ -574,16 +815,8 @@ func (db *SQLite) ExecutePendingChanges(changes []models.DbDmlChange) (err error
 			queryStr := "DELETE FROM "
 			queryStr += db.formatTableName(change.Table)
 
-			args := make([]interface{}, len(change.PrimaryKeyInfo))
-
-			for i, pki := range change.PrimaryKeyInfo {
-				if i == 0 {
-					queryStr += fmt.Sprintf(" WHERE `%s` = ?", pki.Name)
-				} else {
-					queryStr += fmt.Sprintf(" AND `%s` = ?", pki.Name)
-				}
-				args[i] = pki.Value
-			}
+			condition, args := db.rowCondition(change)
+			queryStr += condition
 
 			newQuery := models.Query{
 				Query: queryStr,
@@ -627,6 +860,142 @@ func (db *SQLite) GetProvider() string {
 	return db.Provider
 }
 
+func (db *SQLite) Close() error {
+	return db.Connection.Close()
+}
+
+// GetUniqueKeyColumnNames returns the columns of a unique index that can identify the rows of a
+// table without a primary key.
+func (db *SQLite) GetUniqueKeyColumnNames(_, table string) ([]string, error) {
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	// Expression columns have no name and no table column, so they count as nullable
+	rows, err := db.Connection.Query(`
+	SELECT il.name, COALESCE(ii.name, ''), COALESCE(ti."notnull", 0) = 0
+	FROM pragma_index_list(?) il
+	JOIN pragma_index_info(il.name) ii
+	LEFT JOIN pragma_table_info(?) ti ON ti.name = ii.name
+	WHERE il."unique" = 1 AND il.partial = 0
+	ORDER BY il.seq, ii.seqno
+	`, table, table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns := []indexColumn{}
+
+	for rows.Next() {
+		var column indexColumn
+
+		err = rows.Scan(&column.index, &column.column, &column.nullable)
+		if err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, column)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return notNullableIndexColumns(columns), nil
+}
+
+// GetSchemaChangeStatements returns the statements that apply a change to the structure of a table.
+func (db *SQLite) GetSchemaChangeStatements(change models.SchemaChange) ([]string, error) {
+	if err := validateSchemaChange(change); err != nil {
+		return nil, err
+	}
+
+	formattedTableName := db.formatTableName(change.Table)
+
+	switch change.Type {
+	case models.SchemaAddColumn:
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", formattedTableName, columnDefinition(change.Column, "`"))}, nil
+	case models.SchemaAlterColumn:
+		// SQLite can only rename a column, anything else requires rebuilding the table
+		if change.Column.Type != change.OldColumn.Type || change.Column.Nullable != change.OldColumn.Nullable || change.Column.Default != change.OldColumn.Default {
+			return nil, errors.New("SQLite can only rename columns, changing the type, nullability or default requires rebuilding the table")
+		}
+
+		if change.Column.Name == change.OldColumn.Name {
+			return nil, errors.New("the column has no changes")
+		}
+
+		return []string{fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", formattedTableName, quoteIdentifier(change.OldColumn.Name, "`"), quoteIdentifier(change.Column.Name, "`"))}, nil
+	case models.SchemaDropColumn:
+		return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", formattedTableName, quoteIdentifier(change.Column.Name, "`"))}, nil
+	case models.SchemaCreateIndex:
+		return []string{createIndexStatement(change.Index, formattedTableName, "`")}, nil
+	case models.SchemaDropIndex:
+		return []string{fmt.Sprintf("DROP INDEX %s", quoteIdentifier(change.Index.Name, "`"))}, nil
+	case models.SchemaCreateTable:
+		return []string{createTableStatement(formattedTableName, change.Columns, change.PrimaryKey, "`")}, nil
+	case models.SchemaRenameTable:
+		return []string{fmt.Sprintf("ALTER TABLE %s RENAME TO %s", formattedTableName, quoteIdentifier(change.NewTable, "`"))}, nil
+	case models.SchemaTruncateTable:
+		// SQLite has no TRUNCATE, a DELETE without WHERE empties the table just as fast
+		return []string{fmt.Sprintf("DELETE FROM %s", formattedTableName)}, nil
+	case models.SchemaDropTable:
+		return []string{fmt.Sprintf("DROP TABLE %s", formattedTableName)}, nil
+	case models.SchemaDuplicateTable:
+		return db.duplicateTableStatements(change)
+	}
+
+	return nil, fmt.Errorf("unsupported schema change type: %d", change.Type)
+}
+
+// duplicateTableStatements creates the new table with the CREATE TABLE statement of the original
+// one, which keeps its columns and constraints. Indexes and triggers are not copied.
+func (db *SQLite) duplicateTableStatements(change models.SchemaChange) ([]string, error) {
+	var createTable string
+
+	err := db.Connection.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", change.Table).Scan(&createTable)
+	if err != nil {
+		return nil, err
+	}
+
+	// The definition starts at the first parenthesis, whatever the quoting of the table name is
+	definitionStart := strings.Index(createTable, "(")
+	if definitionStart < 0 {
+		return nil, fmt.Errorf("can't duplicate %s, its definition has no columns", change.Table)
+	}
+
+	newTableName := db.formatTableName(change.NewTable)
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s %s", newTableName, createTable[definitionStart:])}
+	if change.WithData {
+		statements = append(statements, fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", newTableName, db.formatTableName(change.Table)))
+	}
+
+	return statements, nil
+}
+
+// ExecuteSchemaStatements runs the statements of a schema change in a transaction, DDL
+// statements are transactional in SQLite.
+func (db *SQLite) ExecuteSchemaStatements(_ string, statements []string) error {
+	return queriesInTransaction(db.Connection, statementsToQueries(statements))
+}
+
+// rowCondition returns the WHERE clause that matches the row of an update or delete and its
+// arguments. Without a primary or unique key the row is found by the rowid it was read with.
+func (db *SQLite) rowCondition(change models.DbDmlChange) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	for _, pki := range change.PrimaryKeyInfo {
+		conditions = append(conditions, fmt.Sprintf("`%s` = ?", pki.Name))
+		args = append(args, pki.Value)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
 func (db *SQLite) formatTableName(table string) string {
 	return fmt.Sprintf("`%s`", table)
 }
@@ -0,0 +1,42 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+
+	gomock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestSQLite_GetSchemaChangeStatements_DuplicateTable(t *testing.T) {
+	connection, mock, err := gomock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer connection.Close()
+
+	db := &SQLite{Connection: connection}
+
+	mock.ExpectQuery("SELECT sql FROM sqlite_master").
+		WithArgs("orders").
+		WillReturnRows(gomock.NewRows([]string{"sql"}).AddRow(`CREATE TABLE "orders" (id integer primary key, total real)`))
+
+	got, err := db.GetSchemaChangeStatements(models.SchemaChange{Type: models.SchemaDuplicateTable, Table: "orders", NewTable: "orders_copy", WithData: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"CREATE TABLE `orders_copy` (id integer primary key, total real)",
+		"INSERT INTO `orders_copy` SELECT * FROM `orders`",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSchemaChangeStatements() = %q, want %q", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
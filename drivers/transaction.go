@@ -0,0 +1,73 @@
+package drivers
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Transaction runs statements on a single connection until it is committed or rolled back.
+type Transaction interface {
+	ExecuteQuery(query string) ([][]string, error)
+	ExecuteDMLStatement(query string) (string, error)
+	Commit() error
+	Rollback() error
+}
+
+type transaction struct {
+	tx *sql.Tx
+	// onDone is called once the transaction is committed or rolled back
+	onDone func()
+}
+
+func beginTransaction(db *sql.DB, onDone func()) (Transaction, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction{tx: tx, onDone: onDone}, nil
+}
+
+func (t *transaction) ExecuteQuery(query string) (results [][]string, err error) {
+	rows, err := t.tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanQueryResults(rows)
+}
+
+func (t *transaction) ExecuteDMLStatement(query string) (string, error) {
+	res, err := t.tx.Exec(query)
+	if err != nil {
+		return "", err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d rows affected", rowsAffected), nil
+}
+
+// Commit ends the transaction even when it returns an error, like Rollback.
+func (t *transaction) Commit() error {
+	defer t.done()
+
+	return t.tx.Commit()
+}
+
+func (t *transaction) Rollback() error {
+	defer t.done()
+
+	return t.tx.Rollback()
+}
+
+func (t *transaction) done() {
+	if t.onDone != nil {
+		t.onDone()
+		t.onDone = nil
+	}
+}
@@ -1,8 +1,16 @@
 package drivers
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/jorgerojas26/lazysql/helpers/logger"
 	"github.com/jorgerojas26/lazysql/models"
@@ -32,3 +40,798 @@ func queriesInTransaction(db *sql.DB, queries []models.Query) (err error) {
 	}
 	return nil
 }
+
+// ReadOnlyURL returns the connection URL with the options that make the
+// database session read-only for the given provider.
+func ReadOnlyURL(provider, urlstr string) (string, error) {
+	switch provider {
+	case DriverMySQL:
+		// The mysql driver sends unknown DSN parameters as session variables on every
+		// new connection, which is the same as SET SESSION TRANSACTION READ ONLY.
+		return appendURLParam(urlstr, "transaction_read_only", "1"), nil
+	case DriverPostgres:
+		return appendURLParam(urlstr, "default_transaction_read_only", "on"), nil
+	case DriverSqlite:
+		if !strings.HasPrefix(urlstr, "file:") {
+			urlstr = "file:" + urlstr
+		}
+		return appendURLParam(urlstr, "mode", "ro"), nil
+	}
+
+	return "", fmt.Errorf("read-only mode is not supported for provider %q", provider)
+}
+
+func appendURLParam(urlstr, key, value string) string {
+	separator := "?"
+	if strings.Contains(urlstr, "?") {
+		separator = "&"
+	}
+
+	return fmt.Sprintf("%s%s%s=%s", urlstr, separator, key, value)
+}
+
+// indexColumn is a column of a unique index, in the order of the index.
+type indexColumn struct {
+	index    string
+	column   string
+	nullable bool
+}
+
+// notNullableIndexColumns returns the columns of the first index whose columns are all not
+// nullable, which identify a row as well as a primary key.
+func notNullableIndexColumns(columns []indexColumn) []string {
+	indexes := []string{}
+	indexColumns := map[string][]string{}
+	hasNullable := map[string]bool{}
+
+	for _, column := range columns {
+		if _, ok := indexColumns[column.index]; !ok {
+			indexes = append(indexes, column.index)
+		}
+
+		indexColumns[column.index] = append(indexColumns[column.index], column.column)
+		hasNullable[column.index] = hasNullable[column.index] || column.nullable
+	}
+
+	for _, index := range indexes {
+		if !hasNullable[index] {
+			return indexColumns[index]
+		}
+	}
+
+	return nil
+}
+
+// scanObjects reads database objects from rows with the schema, name, table and type columns.
+func scanObjects(rows *sql.Rows, database string) ([]models.DatabaseObject, error) {
+	defer rows.Close()
+
+	objects := []models.DatabaseObject{}
+
+	for rows.Next() {
+		object := models.DatabaseObject{Database: database}
+
+		err := rows.Scan(&object.Schema, &object.Name, &object.Table, &object.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, object)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// resultColumnValue returns the value of a column in the first row of a query result.
+func resultColumnValue(results [][]string, column string) (string, error) {
+	if len(results) < 2 {
+		return "", errors.New("no results")
+	}
+
+	for i, name := range results[0] {
+		if strings.EqualFold(name, column) && i < len(results[1]) {
+			return results[1][i], nil
+		}
+	}
+
+	return "", fmt.Errorf("column %s not found", column)
+}
+
+// RowIDColumn returns the column that identifies the rows of a table without a primary or unique
+// key, which GetRecords selects first with RecordsQuery.RowID: ctid in PostgreSQL and rowid in
+// SQLite. MySQL has none, so its rows are matched by all of their values.
+func RowIDColumn(provider string) string {
+	switch provider {
+	case DriverPostgres:
+		return "ctid"
+	case DriverSqlite:
+		return "rowid"
+	}
+
+	return ""
+}
+
+// TableColumnValue returns the value of a row of the columns or indexes of a table, as the
+// drivers list them in GetTableColumns and GetIndexes, looking the column up by the names each
+// driver gives it.
+func TableColumnValue(rows [][]string, row int, names ...string) string {
+	if len(rows) == 0 || row >= len(rows) {
+		return ""
+	}
+
+	for i, header := range rows[0] {
+		for _, name := range names {
+			if strings.EqualFold(header, name) && i < len(rows[row]) {
+				return rows[row][i]
+			}
+		}
+	}
+
+	return ""
+}
+
+// scanQueryResults reads the rows of a query as text, after the names of the columns, the way
+// ExecuteQuery returns them.
+func scanQueryResults(rows *sql.Rows) ([][]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := [][]string{columns}
+
+	for rows.Next() {
+		rowValues := make([]interface{}, len(columns))
+		for i := range columns {
+			rowValues[i] = new(sql.RawBytes)
+		}
+
+		if err := rows.Scan(rowValues...); err != nil {
+			return nil, err
+		}
+
+		var row []string
+		for _, col := range rowValues {
+			row = append(row, string(*col.(*sql.RawBytes)))
+		}
+
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// scanForeignKeys reads foreign keys from rows with the name, table, column, referenced table and
+// referenced column columns, one row per column ordered by key. A NULL referenced column is left
+// empty for the driver to fill in.
+func scanForeignKeys(rows *sql.Rows) ([]models.ForeignKey, error) {
+	defer rows.Close()
+
+	foreignKeys := []models.ForeignKey{}
+
+	for rows.Next() {
+		var name, table, column, referencedTable string
+		var referencedColumn sql.NullString
+
+		err := rows.Scan(&name, &table, &column, &referencedTable, &referencedColumn)
+		if err != nil {
+			return nil, err
+		}
+
+		last := len(foreignKeys) - 1
+		if last < 0 || foreignKeys[last].Name != name || foreignKeys[last].Table != table {
+			foreignKeys = append(foreignKeys, models.ForeignKey{Name: name, Table: table, ReferencedTable: referencedTable})
+			last++
+		}
+
+		foreignKeys[last].Columns = append(foreignKeys[last].Columns, column)
+		foreignKeys[last].ReferencedColumns = append(foreignKeys[last].ReferencedColumns, referencedColumn.String)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return foreignKeys, nil
+}
+
+// scanIndexDefinitions reads rows of index name, uniqueness and column, grouping the consecutive
+// rows of an index.
+func scanIndexDefinitions(rows *sql.Rows) ([]models.IndexDefinition, error) {
+	defer rows.Close()
+
+	indexes := []models.IndexDefinition{}
+
+	for rows.Next() {
+		var name, column string
+		var unique bool
+
+		err := rows.Scan(&name, &unique, &column)
+		if err != nil {
+			return nil, err
+		}
+
+		last := len(indexes) - 1
+		if last < 0 || indexes[last].Name != name {
+			indexes = append(indexes, models.IndexDefinition{Name: name, Unique: unique})
+			last++
+		}
+
+		indexes[last].Columns = append(indexes[last].Columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return indexes, nil
+}
+
+// statsHeader is the header of the rows returned by GetTableStats and GetDatabaseStats.
+var statsHeader = []string{"Statistic", "Value"}
+
+// formatSize returns a size in bytes in the largest unit that keeps it above one.
+func formatSize(size int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+
+	value := float64(size)
+	unit := 0
+
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
+// parseSessionID checks that the id of a session is a number, because KILL takes no parameters.
+func parseSessionID(id string) (uint64, error) {
+	number, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid session id %q", id)
+	}
+
+	return number, nil
+}
+
+// sessionConnector opens the connections of a pool and keeps the ids of their sessions, so the
+// activity monitor can leave out the sessions of lazysql itself.
+type sessionConnector struct {
+	driver.Connector
+	// query selects the id of the session of a connection
+	query string
+	mutex sync.Mutex
+	// ids holds when each session was opened
+	ids map[string]time.Time
+}
+
+func newSessionConnector(connector driver.Connector, query string) *sessionConnector {
+	return &sessionConnector{Connector: connector, query: query, ids: map[string]time.Time{}}
+}
+
+func (connector *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := connector.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := sessionID(ctx, conn, connector.query)
+	if err != nil {
+		return nil, errors.Join(err, conn.Close())
+	}
+
+	connector.mutex.Lock()
+	connector.ids[id] = time.Now()
+	connector.mutex.Unlock()
+
+	return conn, nil
+}
+
+// leaveOut removes the sessions of the pool from the sessions listed at a time. The sessions that
+// were opened before and are no longer listed are forgotten, since their ids can be reused.
+func (connector *sessionConnector) leaveOut(sessions []models.Session, listedAt time.Time) []models.Session {
+	if connector == nil {
+		return sessions
+	}
+
+	connector.mutex.Lock()
+	defer connector.mutex.Unlock()
+
+	listed := map[string]bool{}
+	others := make([]models.Session, 0, len(sessions))
+
+	for _, session := range sessions {
+		listed[session.ID] = true
+
+		if _, ok := connector.ids[session.ID]; !ok {
+			others = append(others, session)
+		}
+	}
+
+	for id, openedAt := range connector.ids {
+		if !listed[id] && openedAt.Before(listedAt) {
+			delete(connector.ids, id)
+		}
+	}
+
+	return others
+}
+
+// sessionID returns the id of the session of a connection that hasn't been handed to the pool yet.
+func sessionID(ctx context.Context, conn driver.Conn, query string) (string, error) {
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return "", errors.New("the connection can't tell the id of its session")
+	}
+
+	rows, err := queryer.QueryContext(ctx, query, nil)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, len(rows.Columns()))
+	if err := rows.Next(values); err != nil {
+		return "", err
+	}
+
+	if value, ok := values[0].([]byte); ok {
+		return string(value), nil
+	}
+
+	return fmt.Sprint(values[0]), nil
+}
+
+// quoteIdentifier quotes a table, column or index name, doubling the quotes inside of it.
+func quoteIdentifier(name, quote string) string {
+	return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+}
+
+// QuoteIdentifier quotes a column name for the WHERE clauses written by the UI.
+func QuoteIdentifier(provider, name string) string {
+	if provider == DriverPostgres {
+		return quoteIdentifier(name, `"`)
+	}
+
+	return quoteIdentifier(name, "`")
+}
+
+// QuoteLiteral quotes a value as a string literal for the WHERE clauses written by the UI. MySQL
+// also treats backslashes as escape characters inside of strings.
+func QuoteLiteral(provider, value string) string {
+	if provider == DriverMySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// recordsClauses returns the WHERE and ORDER BY clauses of a records query, each with a leading
+// space when it is not empty, and the values to bind to the placeholders of the WHERE clause.
+func recordsClauses(provider string, query models.RecordsQuery) (where, orderBy string, args []interface{}, err error) {
+	predicates := []string{}
+
+	if typed := strings.TrimSpace(query.Where); typed != "" {
+		if err := validateWhere(provider, typed); err != nil {
+			return "", "", nil, err
+		}
+
+		predicates = append(predicates, "("+typed+")")
+	}
+
+	if len(query.Conditions) > 0 {
+		conditions, conditionArgs := filterConditionsSQL(provider, query.Conditions)
+		predicates = append(predicates, conditions)
+		args = conditionArgs
+	}
+
+	if len(query.After) > len(query.OrderBy) {
+		return "", "", nil, errors.New("the rows can only come after the values of the sort columns")
+	}
+
+	if len(query.After) > 0 {
+		columns := make([]string, len(query.After))
+		placeholders := make([]string, len(query.After))
+
+		for i, value := range query.After {
+			if value == "EMPTY&" {
+				value = ""
+			}
+
+			args = append(args, value)
+			columns[i] = QuoteIdentifier(provider, query.OrderBy[i].Column)
+			placeholders[i] = "?"
+			if provider == DriverPostgres {
+				placeholders[i] = fmt.Sprintf("$%d", len(args))
+			}
+		}
+
+		// A row value compares the columns in order, like the sort does
+		predicates = append(predicates, fmt.Sprintf("(%s) > (%s)", strings.Join(columns, ", "), strings.Join(placeholders, ", ")))
+	}
+
+	if len(predicates) > 0 {
+		where = " WHERE " + strings.Join(predicates, " AND ")
+	}
+
+	columns := make([]string, 0, len(query.OrderBy))
+	for _, column := range query.OrderBy {
+		direction := "ASC"
+		if column.Descending {
+			direction = "DESC"
+		}
+
+		columns = append(columns, fmt.Sprintf("%s %s", QuoteIdentifier(provider, column.Column), direction))
+	}
+
+	if len(columns) > 0 {
+		orderBy = " ORDER BY " + strings.Join(columns, ", ")
+	}
+
+	return where, orderBy, args, nil
+}
+
+// validateWhere checks that a typed condition stays inside of the WHERE clause: outside of string
+// literals and quoted names it can't end the statement, start a comment or close more
+// parentheses than it opens. The condition is still SQL that runs with the rights of the
+// connection, the check only keeps it from being a different statement.
+func validateWhere(provider, where string) error {
+	depth := 0
+
+	for i := 0; i < len(where); i++ {
+		switch char := where[i]; char {
+		case '\'', '"', '`':
+			// MySQL escapes characters of strings with backslashes, and so does PostgreSQL in the
+			// E'...' strings
+			escapes := provider == DriverMySQL && char != '`'
+			if provider == DriverPostgres && char == '\'' && i > 0 && (where[i-1] == 'E' || where[i-1] == 'e') && (i == 1 || !isIdentifierChar(where[i-2])) {
+				escapes = true
+			}
+
+			end := i + 1
+			for ; end < len(where); end++ {
+				if where[end] == '\\' && escapes {
+					end++
+					continue
+				}
+
+				if where[end] == char {
+					// A doubled quote is a quote inside of the literal
+					if end+1 < len(where) && where[end+1] == char {
+						end++
+						continue
+					}
+
+					break
+				}
+			}
+
+			if end >= len(where) {
+				return fmt.Errorf("the filter has an unterminated %c", char)
+			}
+
+			i = end
+		case '$':
+			// PostgreSQL strings can be quoted with $$ or $tag$, where nothing is escaped
+			if provider != DriverPostgres || (i > 0 && isIdentifierChar(where[i-1])) {
+				continue
+			}
+
+			tag := dollarQuoteTag(where[i:])
+			if tag == "" {
+				continue
+			}
+
+			end := strings.Index(where[i+len(tag):], tag)
+			if end < 0 {
+				return fmt.Errorf("the filter has an unterminated %s", tag)
+			}
+
+			i += len(tag) + end + len(tag) - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return errors.New("the filter closes a parenthesis that it did not open")
+			}
+		case ';':
+			return errors.New("the filter can't have more than one statement")
+		case '-', '/':
+			if strings.HasPrefix(where[i:], "--") || strings.HasPrefix(where[i:], "/*") {
+				return errors.New("the filter can't have comments")
+			}
+		case '#':
+			if provider == DriverMySQL {
+				return errors.New("the filter can't have comments")
+			}
+		}
+	}
+
+	if depth != 0 {
+		return errors.New("the filter has unclosed parentheses")
+	}
+
+	return nil
+}
+
+// dollarQuoteTag returns the $$ or $tag$ that a PostgreSQL dollar-quoted string starts with, or
+// an empty string when the text doesn't start one, like a $1 parameter.
+func dollarQuoteTag(text string) string {
+	for end := 1; end < len(text); end++ {
+		char := text[end]
+		if char == '$' {
+			return text[:end+1]
+		}
+
+		if !isIdentifierChar(char) || (end == 1 && char >= '0' && char <= '9') {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// isIdentifierChar reports whether a character can be part of an unquoted name.
+func isIdentifierChar(char byte) bool {
+	return char == '_' || char == '$' || char >= 0x80 ||
+		(char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9')
+}
+
+// filterConditionsSQL returns the quick filter conditions joined with AND, with the placeholders
+// of the provider for their values, and the values to bind. Excluding a value keeps the rows
+// where the column is NULL. A condition on several columns matches when any of them does.
+func filterConditionsSQL(provider string, conditions []models.FilterCondition) (string, []interface{}) {
+	expressions := make([]string, 0, len(conditions))
+	args := []interface{}{}
+
+	for _, condition := range conditions {
+		value := condition.Value
+		if value == "EMPTY&" {
+			value = ""
+		}
+
+		columnExpressions := []string{}
+
+		for _, name := range append([]string{condition.Column}, condition.OrColumns...) {
+			column := QuoteIdentifier(provider, name)
+
+			switch condition.Operator {
+			case models.FilterIsNull, models.FilterIsNotNull:
+				columnExpressions = append(columnExpressions, fmt.Sprintf("%s %s", column, condition.Operator))
+				continue
+			}
+
+			args = append(args, value)
+
+			placeholder := "?"
+			if provider == DriverPostgres {
+				placeholder = fmt.Sprintf("$%d", len(args))
+			}
+
+			switch {
+			case condition.Operator == models.FilterEqual:
+				columnExpressions = append(columnExpressions, fmt.Sprintf("%s = %s", column, placeholder))
+			case condition.Operator == models.FilterLike && provider == DriverMySQL:
+				columnExpressions = append(columnExpressions, fmt.Sprintf("CAST(%s AS CHAR) LIKE %s", column, placeholder))
+			case condition.Operator == models.FilterLike && provider == DriverPostgres:
+				columnExpressions = append(columnExpressions, fmt.Sprintf("CAST(%s AS TEXT) ILIKE %s", column, placeholder))
+			case condition.Operator == models.FilterLike:
+				columnExpressions = append(columnExpressions, fmt.Sprintf("%s LIKE %s", column, placeholder))
+			case provider == DriverMySQL:
+				columnExpressions = append(columnExpressions, fmt.Sprintf("NOT %s <=> %s", column, placeholder))
+			case provider == DriverPostgres:
+				columnExpressions = append(columnExpressions, fmt.Sprintf("%s IS DISTINCT FROM %s", column, placeholder))
+			default:
+				columnExpressions = append(columnExpressions, fmt.Sprintf("%s IS NOT %s", column, placeholder))
+			}
+		}
+
+		if len(columnExpressions) == 1 {
+			expressions = append(expressions, columnExpressions[0])
+		} else {
+			expressions = append(expressions, "("+strings.Join(columnExpressions, " OR ")+")")
+		}
+	}
+
+	return strings.Join(expressions, " AND "), args
+}
+
+// validateSchemaChange checks that a schema change has the names the statements need.
+func validateSchemaChange(change models.SchemaChange) error {
+	if change.Table == "" {
+		return errors.New("table name is required")
+	}
+
+	switch change.Type {
+	case models.SchemaAddColumn, models.SchemaAlterColumn:
+		if change.Column.Name == "" {
+			return errors.New("column name is required")
+		}
+
+		if change.Column.Type == "" {
+			return errors.New("column type is required")
+		}
+	case models.SchemaDropColumn:
+		if change.Column.Name == "" {
+			return errors.New("column name is required")
+		}
+	case models.SchemaCreateIndex:
+		if change.Index.Name == "" {
+			return errors.New("index name is required")
+		}
+
+		if len(change.Index.Columns) == 0 {
+			return errors.New("at least one index column is required")
+		}
+	case models.SchemaDropIndex:
+		if change.Index.Name == "" {
+			return errors.New("index name is required")
+		}
+	case models.SchemaCreateTable:
+		if len(change.Columns) == 0 {
+			return errors.New("at least one column is required")
+		}
+
+		for _, column := range change.Columns {
+			if column.Name == "" || column.Type == "" {
+				return errors.New("every column needs a name and a type")
+			}
+		}
+	case models.SchemaRenameTable, models.SchemaDuplicateTable:
+		if change.NewTable == "" {
+			return errors.New("new table name is required")
+		}
+	case models.SchemaTruncateTable, models.SchemaDropTable:
+	default:
+		return fmt.Errorf("unsupported schema change type: %d", change.Type)
+	}
+
+	return nil
+}
+
+// columnDefinition returns the definition of a column used by CREATE TABLE, ADD COLUMN and the MySQL MODIFY COLUMN.
+func columnDefinition(column models.ColumnDefinition, quote string) string {
+	definition := quoteIdentifier(column.Name, quote) + " " + column.Type
+
+	if !column.Nullable {
+		definition += " NOT NULL"
+	}
+
+	if column.Default != "" {
+		definition += " DEFAULT " + column.Default
+	}
+
+	if column.Extra != "" {
+		definition += " " + column.Extra
+	}
+
+	return definition
+}
+
+// createIndexStatement returns the CREATE INDEX statement of an index, the table name must be formatted.
+func createIndexStatement(index models.IndexDefinition, formattedTableName, quote string) string {
+	columns := make([]string, 0, len(index.Columns))
+	for _, column := range index.Columns {
+		columns = append(columns, quoteIdentifier(column, quote))
+	}
+
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, quoteIdentifier(index.Name, quote), formattedTableName, strings.Join(columns, ", "))
+}
+
+// createTableStatement returns the CREATE TABLE statement of a table, the table name must be formatted.
+func createTableStatement(formattedTableName string, columns []models.ColumnDefinition, primaryKey []string, quote string) string {
+	definitions := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		definitions = append(definitions, columnDefinition(column, quote))
+	}
+
+	if len(primaryKey) > 0 {
+		primaryKeyColumns := make([]string, 0, len(primaryKey))
+		for _, column := range primaryKey {
+			primaryKeyColumns = append(primaryKeyColumns, quoteIdentifier(column, quote))
+		}
+
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeyColumns, ", ")))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (%s)", formattedTableName, strings.Join(definitions, ", "))
+}
+
+// statementsToQueries wraps statements without arguments to run them with queriesInTransaction.
+func statementsToQueries(statements []string) []models.Query {
+	queries := make([]models.Query, 0, len(statements))
+	for _, statement := range statements {
+		queries = append(queries, models.Query{Query: statement})
+	}
+
+	return queries
+}
+
+// privilegePattern matches a privilege, optionally limited to some columns like SELECT (id, name).
+// Privileges are keywords that can't be quoted, so anything else is rejected.
+var privilegePattern = regexp.MustCompile("^[A-Za-z][A-Za-z ]*( ?\\([\\w, `\"]+\\))?$")
+
+// SplitPrivileges splits a list of privileges by the commas that are not inside the columns of a
+// privilege.
+func SplitPrivileges(text string) []string {
+	privileges := []string{}
+	depth := 0
+	start := 0
+
+	add := func(privilege string) {
+		if privilege = strings.TrimSpace(privilege); privilege != "" {
+			privileges = append(privileges, privilege)
+		}
+	}
+
+	for i, char := range text {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				add(text[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	add(text[start:])
+
+	return privileges
+}
+
+// validateGrant checks that a grant has privileges that can be written in a statement and the
+// object its type needs.
+func validateGrant(grant models.Grant) error {
+	if grant.Role.Name == "" {
+		return errors.New("role name is required")
+	}
+
+	if len(grant.Privileges) == 0 {
+		return errors.New("at least one privilege is required")
+	}
+
+	switch grant.ObjectType {
+	case models.GrantObjectRole, models.GrantObjectGlobal:
+	case models.GrantObjectDatabase, models.GrantObjectSchema, models.GrantObjectTable, models.GrantObjectProcedure, models.GrantObjectFunction:
+		if grant.Object == "" {
+			return fmt.Errorf("the %s is required", grant.ObjectType)
+		}
+	default:
+		return fmt.Errorf("privileges on a %s have to be changed by hand", grant.ObjectType)
+	}
+
+	if grant.ObjectType == models.GrantObjectRole {
+		return nil
+	}
+
+	for _, privilege := range grant.Privileges {
+		if !privilegePattern.MatchString(privilege) {
+			return fmt.Errorf("invalid privilege %q", privilege)
+		}
+	}
+
+	return nil
+}
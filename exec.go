@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers"
+)
+
+// runExec runs the queries of a file, the -query flag or stdin against a connection without
+// starting the TUI and writes the results to stdout. It returns the exit code.
+func runExec(args []string) int {
+	flags := flag.NewFlagSet("exec", flag.ContinueOnError)
+	connectionName := flags.String("c", "", "Name of a saved connection")
+	file := flags.String("f", "", "File with the queries to run, - reads from stdin")
+	query := flags.String("query", "", "Query to run")
+	format := flags.String("format", helpers.ExportFormatTable, fmt.Sprintf("Output format (%s)", strings.Join(helpers.ExportFormats, ", ")))
+
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: lazysql exec [-c name | connection string] [-f file | -query query] [-format format]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	dsn, ok := parseConnectionString(flags)
+	if !ok {
+		return 2
+	}
+
+	if dsn == "" && *connectionName == "" {
+		fmt.Fprintln(os.Stderr, "a connection string or -c is required")
+		flags.Usage()
+		return 2
+	}
+
+	if *file != "" && *query != "" {
+		fmt.Fprintln(os.Stderr, "-f and -query can not be used together")
+		return 2
+	}
+
+	if err := execQueries(dsn, *connectionName, *file, *query, *format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func execQueries(dsn string, connectionName string, file string, query string, format string) error {
+	// Fail on an unknown format before running anything
+	if !helpers.IsExportFormat(format) {
+		return fmt.Errorf("unknown format %q, expected one of: %s", format, strings.Join(helpers.ExportFormats, ", "))
+	}
+
+	script := query
+
+	if script == "" {
+		var content []byte
+		var err error
+
+		if file == "" || file == "-" {
+			content, err = io.ReadAll(os.Stdin)
+		} else {
+			content, err = os.ReadFile(file)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		script = string(content)
+	}
+
+	statements := helpers.SplitStatements(script)
+	if len(statements) == 0 {
+		return errors.New("no queries to run")
+	}
+
+	connection, err := startupConnection(dsn, connectionName)
+	if err != nil {
+		return err
+	}
+
+	if connection.ReadOnly {
+		for _, statement := range statements {
+			if !helpers.IsReadOnlyQuery(statement) && helpers.TransactionCommand(statement) == commands.Noop {
+				return errors.New("this connection is read-only, only SELECT statements are allowed")
+			}
+		}
+	}
+
+	db, err := drivers.Open(connection)
+	if err != nil {
+		return err
+	}
+
+	// Statements between BEGIN and COMMIT run in a transaction, which is rolled
+	// back if the script fails or ends before committing it
+	var transaction drivers.Transaction
+
+	defer func() {
+		if transaction != nil {
+			_ = transaction.Rollback()
+		}
+	}()
+
+	resultSets := 0
+
+	for _, statement := range statements {
+		executeQuery := db.ExecuteQuery
+		executeDMLStatement := db.ExecuteDMLStatement
+
+		if transaction != nil {
+			executeQuery = transaction.ExecuteQuery
+			executeDMLStatement = transaction.ExecuteDMLStatement
+		}
+
+		command := helpers.TransactionCommand(statement)
+
+		switch command {
+		case commands.BeginTransaction:
+			if transaction != nil {
+				return errors.New("a transaction is already open")
+			}
+
+			transaction, err = db.BeginTransaction()
+			if err != nil {
+				return err
+			}
+		case commands.CommitTransaction, commands.RollbackTransaction:
+			if transaction == nil {
+				return errors.New("there is no open transaction")
+			}
+
+			if command == commands.CommitTransaction {
+				err = transaction.Commit()
+			} else {
+				err = transaction.Rollback()
+			}
+
+			transaction = nil
+
+			if err != nil {
+				return err
+			}
+		default:
+			if helpers.ReturnsRows(statement) {
+				rows, err := executeQuery(statement)
+				if err != nil {
+					return err
+				}
+
+				if resultSets > 0 {
+					fmt.Println()
+				}
+
+				err = helpers.ExportRows(os.Stdout, rows, format)
+				if err != nil {
+					return err
+				}
+
+				resultSets++
+			} else {
+				result, err := executeDMLStatement(statement)
+				if err != nil {
+					return err
+				}
+
+				// Keep stdout for the results so it can be redirected to a file
+				fmt.Fprintln(os.Stderr, result)
+			}
+		}
+	}
+
+	if transaction != nil {
+		fmt.Fprintln(os.Stderr, "the transaction was not committed, rolling it back")
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formats of the value of a cell
+const (
+	CellFormatText   = "text"
+	CellFormatJSON   = "json"
+	CellFormatXML    = "xml"
+	CellFormatBinary = "binary"
+)
+
+// TextLine is a line left visible after folding a pretty-printed value.
+type TextLine struct {
+	// Number is the index of the line in the value
+	Number int
+	Text   string
+	// Foldable is true when the line opens a block
+	Foldable bool
+	// Hidden is the number of lines folded under the line
+	Hidden int
+}
+
+// DetectCellFormat tells how the value of a cell is best shown. Values that are not valid
+// UTF-8 are binary, and JSON objects and arrays and XML documents are pretty-printed.
+func DetectCellFormat(value string) string {
+	if !utf8.ValidString(value) || strings.ContainsRune(value, 0) {
+		return CellFormatBinary
+	}
+
+	trimmed := strings.TrimSpace(value)
+
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return CellFormatJSON
+	}
+
+	if strings.HasPrefix(trimmed, "<") && strings.HasSuffix(trimmed, ">") {
+		if _, err := PrettyXML(trimmed); err == nil {
+			return CellFormatXML
+		}
+	}
+
+	return CellFormatText
+}
+
+// PrettyJSON indents a JSON value by two spaces.
+func PrettyJSON(value string) (string, error) {
+	var buffer bytes.Buffer
+
+	if err := json.Indent(&buffer, []byte(strings.TrimSpace(value)), "", "  "); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// PrettyXML indents an XML document by two spaces. The whitespace between the elements is
+// replaced by the indentation.
+func PrettyXML(value string) (string, error) {
+	var buffer bytes.Buffer
+
+	decoder := xml.NewDecoder(strings.NewReader(value))
+	encoder := xml.NewEncoder(&buffer)
+	encoder.Indent("", "  ")
+
+	for {
+		// The raw tokens keep the namespace prefixes as they are written
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if data, ok := token.(xml.CharData); ok && len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		if err := encoder.EncodeToken(rawXMLToken(xml.CopyToken(token))); err != nil {
+			return "", err
+		}
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// rawXMLToken moves the namespace prefixes of the names of a raw token into their local part,
+// as the encoder would otherwise take them for namespaces to declare.
+func rawXMLToken(token xml.Token) xml.Token {
+	rawName := func(name xml.Name) xml.Name {
+		if name.Space == "" {
+			return name
+		}
+
+		return xml.Name{Local: name.Space + ":" + name.Local}
+	}
+
+	switch element := token.(type) {
+	case xml.StartElement:
+		element.Name = rawName(element.Name)
+		for i, attr := range element.Attr {
+			element.Attr[i].Name = rawName(attr.Name)
+		}
+
+		return element
+	case xml.EndElement:
+		element.Name = rawName(element.Name)
+		return element
+	}
+
+	return token
+}
+
+// HexDump shows a value as the hexadecimal and printable characters of its bytes.
+func HexDump(value string) string {
+	return hex.Dump([]byte(value))
+}
+
+// FoldLines returns the lines of a pretty-printed value that are left visible when the blocks
+// opened at the folded lines are collapsed. A block is made of the lines that follow its first
+// one with a deeper indentation, and of the closing line with the same indentation, which is
+// shown after the folded line.
+func FoldLines(lines []string, folded map[int]bool) []TextLine {
+	visible := []TextLine{}
+
+	for i := 0; i < len(lines); i++ {
+		end, closed := blockEnd(lines, i)
+		line := TextLine{Number: i, Text: lines[i], Foldable: end > i}
+
+		if folded[i] && end > i {
+			line.Hidden = end - i
+			line.Text += " …"
+
+			if closed {
+				line.Text += " " + strings.TrimSpace(lines[end])
+			}
+
+			i = end
+		}
+
+		visible = append(visible, line)
+	}
+
+	return visible
+}
+
+// blockEnd returns the index of the last line of the block opened at the line i, which is i
+// when it opens none, and whether that last line closes the block.
+func blockEnd(lines []string, i int) (int, bool) {
+	indent := indentation(lines[i])
+	end := i
+
+	for end+1 < len(lines) && indentation(lines[end+1]) > indent {
+		end++
+	}
+
+	if end == i || end+1 == len(lines) || indentation(lines[end+1]) != indent {
+		return end, false
+	}
+
+	closing := strings.TrimSpace(lines[end+1])
+	if strings.HasPrefix(closing, "}") || strings.HasPrefix(closing, "]") || strings.HasPrefix(closing, "</") {
+		return end + 1, true
+	}
+
+	return end, false
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -0,0 +1,122 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectCellFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain text", value: "hello world", want: CellFormatText},
+		{name: "number", value: "42", want: CellFormatText},
+		{name: "json object", value: `{"a": 1, "b": [true, null]}`, want: CellFormatJSON},
+		{name: "json array with spaces", value: "  [1, 2]\n", want: CellFormatJSON},
+		{name: "broken json", value: `{"a": 1`, want: CellFormatText},
+		{name: "xml document", value: `<?xml version="1.0"?><a><b x="1">text</b></a>`, want: CellFormatXML},
+		{name: "mismatched xml", value: "<a><b></a></b>", want: CellFormatText},
+		{name: "invalid utf-8", value: "\xff\xfe\x00", want: CellFormatBinary},
+		{name: "nul byte", value: "a\x00b", want: CellFormatBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectCellFormat(tt.value); got != tt.want {
+				t.Errorf("DetectCellFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	got, err := PrettyJSON(`{"a":1,"b":[true,null]}`)
+	if err != nil {
+		t.Fatalf("PrettyJSON() error = %v", err)
+	}
+
+	want := "{\n  \"a\": 1,\n  \"b\": [\n    true,\n    null\n  ]\n}"
+	if got != want {
+		t.Errorf("PrettyJSON() = %q, want %q", got, want)
+	}
+}
+
+func TestPrettyXML(t *testing.T) {
+	got, err := PrettyXML("<a xmlns:ns=\"urn:x\">\n <ns:b x=\"1\">text</ns:b><c/></a>")
+	if err != nil {
+		t.Fatalf("PrettyXML() error = %v", err)
+	}
+
+	want := "<a xmlns:ns=\"urn:x\">\n  <ns:b x=\"1\">text</ns:b>\n  <c></c>\n</a>"
+	if got != want {
+		t.Errorf("PrettyXML() = %q, want %q", got, want)
+	}
+}
+
+func TestFoldLines(t *testing.T) {
+	lines := strings.Split("{\n  \"a\": 1,\n  \"b\": [\n    true,\n    null\n  ],\n  \"c\": {}\n}", "\n")
+
+	tests := []struct {
+		name   string
+		folded map[int]bool
+		want   []TextLine
+	}{
+		{
+			name:   "nothing folded",
+			folded: map[int]bool{},
+			want: []TextLine{
+				{Number: 0, Text: "{", Foldable: true},
+				{Number: 1, Text: `  "a": 1,`},
+				{Number: 2, Text: `  "b": [`, Foldable: true},
+				{Number: 3, Text: "    true,"},
+				{Number: 4, Text: "    null"},
+				{Number: 5, Text: "  ],"},
+				{Number: 6, Text: `  "c": {}`},
+				{Number: 7, Text: "}"},
+			},
+		},
+		{
+			name:   "inner array folded",
+			folded: map[int]bool{2: true},
+			want: []TextLine{
+				{Number: 0, Text: "{", Foldable: true},
+				{Number: 1, Text: `  "a": 1,`},
+				{Number: 2, Text: `  "b": [ … ],`, Foldable: true, Hidden: 3},
+				{Number: 6, Text: `  "c": {}`},
+				{Number: 7, Text: "}"},
+			},
+		},
+		{
+			name:   "outer object folded",
+			folded: map[int]bool{0: true, 2: true},
+			want: []TextLine{
+				{Number: 0, Text: "{ … }", Foldable: true, Hidden: 7},
+			},
+		},
+		{
+			name:   "line without a block",
+			folded: map[int]bool{1: true},
+			want: []TextLine{
+				{Number: 0, Text: "{", Foldable: true},
+				{Number: 1, Text: `  "a": 1,`},
+				{Number: 2, Text: `  "b": [`, Foldable: true},
+				{Number: 3, Text: "    true,"},
+				{Number: 4, Text: "    null"},
+				{Number: 5, Text: "  ],"},
+				{Number: 6, Text: `  "c": {}`},
+				{Number: 7, Text: "}"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FoldLines(lines, tt.folded); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FoldLines() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
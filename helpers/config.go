@@ -1,9 +1,12 @@
 package helpers
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/pelletier/go-toml/v2"
 
 	"github.com/jorgerojas26/lazysql/models"
@@ -11,6 +14,77 @@ import (
 
 type Config struct {
 	Connections []models.Connection `toml:"database"`
+	// TagColors maps a connection tag to a color name or hex value.
+	TagColors map[string]string `toml:"tag_colors,omitempty"`
+	// SaveWorkspace reopens the tabs and the editor of a connection as they were when lazysql
+	// quit, it is on unless set to false.
+	SaveWorkspace *bool `toml:"save_workspace,omitempty"`
+	// DisplayColumns maps a table to the column shown next to its key when picking a row it
+	// references, like customers = "name".
+	DisplayColumns map[string]string `toml:"display_columns,omitempty"`
+}
+
+// SavesWorkspace tells whether the workspaces of the connections are saved and restored.
+func (config Config) SavesWorkspace() bool {
+	return config.SaveWorkspace == nil || *config.SaveWorkspace
+}
+
+// displayColumnNames are the columns that usually describe a row, in order of preference, for
+// the tables without a display column in the config.
+var displayColumnNames = []string{"name", "title", "label", "username", "email", "description", "code"}
+
+// DisplayColumn returns the column of a table shown next to its key column when picking one of
+// its rows: the one set in the config for the table, with or without its schema, or else the
+// first column with a descriptive name or the first column that is not the key. It is empty when
+// the table has no other column.
+func DisplayColumn(displayColumns map[string]string, table string, columns []string, key string) string {
+	contains := func(name string) bool {
+		for _, column := range columns {
+			if column == name && column != key {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	_, tableName, found := strings.Cut(table, ".")
+	if !found {
+		tableName = table
+	}
+
+	for _, name := range []string{table, tableName} {
+		if column, ok := displayColumns[name]; ok && contains(column) {
+			return column
+		}
+	}
+
+	for _, name := range displayColumnNames {
+		for _, column := range columns {
+			if strings.EqualFold(column, name) && column != key {
+				return column
+			}
+		}
+	}
+
+	for _, column := range columns {
+		if column != key {
+			return column
+		}
+	}
+
+	return ""
+}
+
+// defaultTagColors are used for the usual environment tags when the config does not set a color for them.
+var defaultTagColors = map[string]string{
+	"prod":        "red",
+	"production":  "red",
+	"staging":     "yellow",
+	"stage":       "yellow",
+	"dev":         "green",
+	"development": "green",
+	"test":        "blue",
 }
 
 func LoadConfig() (config Config, err error) {
@@ -36,7 +110,14 @@ func LoadConnections() (connections []models.Connection, err error) {
 }
 
 func SaveConnectionConfig(connections []models.Connection) (err error) {
-	config := Config{Connections: connections}
+	// Keep the rest of the config as it is, a missing config file is not an error here but a
+	// malformed one is, so it isn't overwritten
+	config, err := LoadConfig()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	config.Connections = connections
 
 	directoriesPath := filepath.Join(os.Getenv("HOME"), ".config", "lazysql")
 	configFilePath := filepath.Join(directoriesPath, "config.toml")
@@ -57,3 +138,44 @@ func SaveConnectionConfig(connections []models.Connection) (err error) {
 
 	return err
 }
+
+// TagColor returns the color configured for the tag, falling back to the default environment colors.
+func TagColor(tagColors map[string]string, tag string) (tcell.Color, bool) {
+	tag = strings.ToLower(tag)
+
+	colorName, ok := tagColors[tag]
+	if !ok {
+		colorName, ok = defaultTagColors[tag]
+	}
+
+	if !ok {
+		return tcell.ColorDefault, false
+	}
+
+	color := tcell.GetColor(colorName)
+
+	return color, color != tcell.ColorDefault
+}
+
+// EnvironmentColor returns the first tag of the connection that has a color, which is used
+// to tell environments apart.
+func EnvironmentColor(connection models.Connection, tagColors map[string]string) (string, tcell.Color, bool) {
+	for _, tag := range connection.Tags {
+		if color, ok := TagColor(tagColors, tag); ok {
+			return tag, color, true
+		}
+	}
+
+	return "", tcell.ColorDefault, false
+}
+
+// FindConnection returns the saved connection with the given name, ignoring case.
+func FindConnection(connections []models.Connection, name string) (models.Connection, bool) {
+	for _, connection := range connections {
+		if strings.EqualFold(connection.Name, name) {
+			return connection, true
+		}
+	}
+
+	return models.Connection{}, false
+}
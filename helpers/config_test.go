@@ -0,0 +1,131 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestDisplayColumn(t *testing.T) {
+	displayColumns := map[string]string{
+		"customers":       "email",
+		"public.products": "sku",
+		"orders":          "missing",
+	}
+
+	tests := []struct {
+		name    string
+		table   string
+		columns []string
+		key     string
+		want    string
+	}{
+		{name: "configured column", table: "customers", columns: []string{"id", "name", "email"}, key: "id", want: "email"},
+		{name: "configured with schema", table: "public.products", columns: []string{"id", "title", "sku"}, key: "id", want: "sku"},
+		{name: "configured without schema", table: "sales.customers", columns: []string{"id", "name", "email"}, key: "id", want: "email"},
+		{name: "configured column missing", table: "orders", columns: []string{"id", "total", "Title"}, key: "id", want: "Title"},
+		{name: "descriptive name", table: "users", columns: []string{"id", "created_at", "username", "name"}, key: "id", want: "name"},
+		{name: "first other column", table: "payments", columns: []string{"id", "amount", "paid_at"}, key: "id", want: "amount"},
+		{name: "key is the name", table: "tags", columns: []string{"name"}, key: "name", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DisplayColumn(displayColumns, tt.table, tt.columns, tt.key); got != tt.want {
+				t.Errorf("DisplayColumn() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagColor(t *testing.T) {
+	tagColors := map[string]string{
+		"prod":  "purple",
+		"qa":    "orange",
+		"local": "not-a-color",
+	}
+
+	tests := []struct {
+		tag       string
+		wantColor tcell.Color
+		wantOk    bool
+	}{
+		{tag: "prod", wantColor: tcell.ColorPurple, wantOk: true},
+		{tag: "QA", wantColor: tcell.ColorOrange, wantOk: true},
+		{tag: "staging", wantColor: tcell.ColorYellow, wantOk: true},
+		{tag: "local", wantColor: tcell.ColorDefault, wantOk: false},
+		{tag: "analytics", wantColor: tcell.ColorDefault, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			color, ok := TagColor(tagColors, tt.tag)
+			if color != tt.wantColor || ok != tt.wantOk {
+				t.Errorf("TagColor() = %v, %v, want %v, %v", color, ok, tt.wantColor, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestEnvironmentColor(t *testing.T) {
+	tagColors := map[string]string{"qa": "orange"}
+
+	tests := []struct {
+		name      string
+		tags      []string
+		wantTag   string
+		wantColor tcell.Color
+		wantOk    bool
+	}{
+		{name: "first tag with a color", tags: []string{"billing", "qa", "prod"}, wantTag: "qa", wantColor: tcell.ColorOrange, wantOk: true},
+		{name: "default color", tags: []string{"billing", "Dev"}, wantTag: "Dev", wantColor: tcell.ColorGreen, wantOk: true},
+		{name: "no tag with a color", tags: []string{"billing"}, wantTag: "", wantColor: tcell.ColorDefault, wantOk: false},
+		{name: "no tags", tags: nil, wantTag: "", wantColor: tcell.ColorDefault, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, color, ok := EnvironmentColor(models.Connection{Tags: tt.tags}, tagColors)
+			if tag != tt.wantTag || color != tt.wantColor || ok != tt.wantOk {
+				t.Errorf("EnvironmentColor() = %q, %v, %v, want %q, %v, %v", tag, color, ok, tt.wantTag, tt.wantColor, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestSaveConnectionConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	connections := []models.Connection{{Name: "local", Provider: "sqlite3", URL: "file:test.db"}}
+
+	// Without a config file, it is created
+	if err := SaveConnectionConfig(connections); err != nil {
+		t.Fatalf("SaveConnectionConfig() without a config error = %v", err)
+	}
+
+	saved, err := LoadConnections()
+	if err != nil || len(saved) != 1 || saved[0].Name != "local" {
+		t.Fatalf("LoadConnections() = %v, %v, want the saved connection", saved, err)
+	}
+
+	// A malformed config is left as it is
+	configFilePath := filepath.Join(home, ".config", "lazysql", "config.toml")
+	malformed := []byte("[[database]\nName = ")
+
+	if err := os.WriteFile(configFilePath, malformed, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveConnectionConfig(connections); err == nil {
+		t.Error("SaveConnectionConfig() with a malformed config error = nil, want an error")
+	}
+
+	if file, _ := os.ReadFile(configFilePath); string(file) != string(malformed) {
+		t.Errorf("SaveConnectionConfig() overwrote the malformed config with %q", file)
+	}
+}
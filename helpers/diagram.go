@@ -0,0 +1,568 @@
+package helpers
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	DiagramFormatDOT     = "dot"
+	DiagramFormatMermaid = "mermaid"
+)
+
+// DiagramFormats are the formats supported by ExportDiagram.
+var DiagramFormats = []string{DiagramFormatDOT, DiagramFormatMermaid}
+
+// ExportDiagram writes a diagram in the DOT language of Graphviz or as a Mermaid ER diagram.
+func ExportDiagram(w io.Writer, diagram models.Diagram, format string) error {
+	switch strings.ToLower(format) {
+	case DiagramFormatDOT:
+		return exportDOT(w, diagram)
+	case DiagramFormatMermaid:
+		return exportMermaid(w, diagram)
+	}
+
+	return fmt.Errorf("unknown format %q, expected one of: %s", format, strings.Join(DiagramFormats, ", "))
+}
+
+// foreignKeyColumns returns the columns of each table that are part of a foreign key.
+func foreignKeyColumns(diagram models.Diagram) map[string]map[string]bool {
+	columns := map[string]map[string]bool{}
+
+	for _, foreignKey := range diagram.ForeignKeys {
+		if columns[foreignKey.Table] == nil {
+			columns[foreignKey.Table] = map[string]bool{}
+		}
+
+		for _, column := range foreignKey.Columns {
+			columns[foreignKey.Table][column] = true
+		}
+	}
+
+	return columns
+}
+
+// columnKeys returns the PK and FK marks of a column.
+func columnKeys(column models.DiagramColumn, isForeignKey bool) []string {
+	keys := []string{}
+
+	if column.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+
+	if isForeignKey {
+		keys = append(keys, "FK")
+	}
+
+	return keys
+}
+
+func exportDOT(w io.Writer, diagram models.Diagram) error {
+	foreignKeys := foreignKeyColumns(diagram)
+	hasColumns := map[string]bool{}
+
+	lines := []string{
+		"digraph diagram {",
+		"\trankdir=LR;",
+		"\tnode [shape=plaintext];",
+		"",
+	}
+
+	for _, table := range diagram.Tables {
+		rows := []string{fmt.Sprintf(`<tr><td bgcolor="lightgrey"><b>%s</b></td></tr>`, html.EscapeString(table.Name))}
+
+		for _, column := range table.Columns {
+			text := strings.Join(append([]string{column.Name, column.Type}, columnKeys(column, foreignKeys[table.Name][column.Name])...), " ")
+			rows = append(rows, fmt.Sprintf(`<tr><td port="%s" align="left">%s</td></tr>`, html.EscapeString(column.Name), html.EscapeString(text)))
+		}
+
+		hasColumns[table.Name] = len(table.Columns) > 0
+
+		lines = append(lines, fmt.Sprintf(`	%s [label=<<table border="0" cellborder="1" cellspacing="0">%s</table>>];`, dotID(table.Name), strings.Join(rows, "")))
+	}
+
+	if len(diagram.ForeignKeys) > 0 {
+		lines = append(lines, "")
+	}
+
+	for _, foreignKey := range diagram.ForeignKeys {
+		tail := dotID(foreignKey.Table)
+		if hasColumns[foreignKey.Table] {
+			tail += ":" + dotID(foreignKey.Columns[0])
+		}
+
+		head := dotID(foreignKey.ReferencedTable)
+		if hasColumns[foreignKey.ReferencedTable] {
+			head += ":" + dotID(foreignKey.ReferencedColumns[0])
+		}
+
+		lines = append(lines, fmt.Sprintf("\t%s -> %s [label=%s];", tail, head, dotID(strings.Join(foreignKey.Columns, ", "))))
+	}
+
+	lines = append(lines, "}")
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// dotID quotes a name as a DOT identifier.
+func dotID(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+}
+
+func exportMermaid(w io.Writer, diagram models.Diagram) error {
+	foreignKeys := foreignKeyColumns(diagram)
+
+	lines := []string{"erDiagram"}
+
+	for _, table := range diagram.Tables {
+		// Tables without columns show up through their relationships
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("    %s {", mermaidName(table.Name)))
+
+		for _, column := range table.Columns {
+			line := fmt.Sprintf("        %s %s", mermaidName(mermaidType(column.Type)), mermaidName(column.Name))
+
+			if keys := columnKeys(column, foreignKeys[table.Name][column.Name]); len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+
+			lines = append(lines, line)
+		}
+
+		lines = append(lines, "    }")
+	}
+
+	for _, foreignKey := range diagram.ForeignKeys {
+		lines = append(lines, fmt.Sprintf(`    %s ||--o{ %s : "%s"`, mermaidName(foreignKey.ReferencedTable), mermaidName(foreignKey.Table), strings.ReplaceAll(strings.Join(foreignKey.Columns, ", "), `"`, "'")))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// mermaidName replaces the characters that Mermaid doesn't allow in entity and attribute names,
+// like the dot between the schema and the table.
+func mermaidName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == '(' || r == ')' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '_'
+	}, name)
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' || name[0] == '(' {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// mermaidType returns a type for the attributes of Mermaid, which are words.
+func mermaidType(columnType string) string {
+	if columnType == "" {
+		return "unknown"
+	}
+
+	return strings.ReplaceAll(strings.TrimSpace(columnType), " ", "_")
+}
+
+// DiagramBox is the position of a table in a diagram drawn by RenderDiagram.
+type DiagramBox struct {
+	Table  string
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// DiagramLayout is a diagram drawn with box-drawing characters, one string per line.
+type DiagramLayout struct {
+	Lines []string
+	Boxes []DiagramBox
+}
+
+// Directions of the lines that go through a cell of the diagram
+const (
+	lineUp uint8 = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+var lineRunes = map[uint8]rune{
+	lineUp: '│', lineDown: '│', lineUp | lineDown: '│',
+	lineLeft: '─', lineRight: '─', lineLeft | lineRight: '─',
+	lineDown | lineRight: '┌', lineDown | lineLeft: '┐', lineUp | lineRight: '└', lineUp | lineLeft: '┘',
+	lineUp | lineDown | lineRight: '├', lineUp | lineDown | lineLeft: '┤',
+	lineLeft | lineRight | lineDown: '┬', lineLeft | lineRight | lineUp: '┴',
+	lineUp | lineDown | lineLeft | lineRight: '┼',
+}
+
+// diagramCanvas is a grid of characters where lines are drawn before the text on top of them.
+type diagramCanvas struct {
+	text  map[[2]int]rune
+	lines map[[2]int]uint8
+}
+
+func (canvas *diagramCanvas) write(x, y int, text string) {
+	for i, r := range []rune(text) {
+		canvas.text[[2]int{x + i, y}] = r
+	}
+}
+
+func (canvas *diagramCanvas) horizontal(x1, x2, y int) {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+
+	for x := x1; x <= x2; x++ {
+		if x > x1 {
+			canvas.lines[[2]int{x, y}] |= lineLeft
+		}
+		if x < x2 {
+			canvas.lines[[2]int{x, y}] |= lineRight
+		}
+	}
+}
+
+func (canvas *diagramCanvas) vertical(x, y1, y2 int) {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+
+	for y := y1; y <= y2; y++ {
+		if y > y1 {
+			canvas.lines[[2]int{x, y}] |= lineUp
+		}
+		if y < y2 {
+			canvas.lines[[2]int{x, y}] |= lineDown
+		}
+	}
+}
+
+func (canvas *diagramCanvas) render(width, height int) []string {
+	lines := make([]string, 0, height)
+
+	for y := 0; y < height; y++ {
+		line := make([]rune, width)
+
+		for x := 0; x < width; x++ {
+			line[x] = ' '
+
+			if r, ok := canvas.text[[2]int{x, y}]; ok {
+				line[x] = r
+			} else if mask := canvas.lines[[2]int{x, y}]; mask != 0 {
+				line[x] = lineRunes[mask]
+			}
+		}
+
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+
+	return lines
+}
+
+// diagramLayers returns the column of each table. Tables that reference no other table go in the
+// first column and the others go to the right of every table they reference, leaving out the
+// references that close a cycle.
+func diagramLayers(diagram models.Diagram, index map[string]int) []int {
+	references := make([][]int, len(diagram.Tables))
+
+	for _, foreignKey := range diagram.ForeignKeys {
+		source, ok := index[foreignKey.Table]
+		target, targetOk := index[foreignKey.ReferencedTable]
+
+		if ok && targetOk && source != target {
+			references[source] = append(references[source], target)
+		}
+	}
+
+	layers := make([]int, len(diagram.Tables))
+	visited := make([]int, len(diagram.Tables)) // 0 not visited, 1 visiting, 2 done
+
+	var visit func(table int) int
+	visit = func(table int) int {
+		switch visited[table] {
+		case 1:
+			return -1
+		case 2:
+			return layers[table]
+		}
+
+		visited[table] = 1
+
+		for _, reference := range references[table] {
+			if layer := visit(reference); layer >= 0 && layer+1 > layers[table] {
+				layers[table] = layer + 1
+			}
+		}
+
+		visited[table] = 2
+
+		return layers[table]
+	}
+
+	for table := range diagram.Tables {
+		visit(table)
+	}
+
+	return layers
+}
+
+// diagramBoxLines returns the title and the column lines of the box of a table.
+func diagramBoxLines(table models.DiagramTable, foreignKeys map[string]bool) []string {
+	nameWidth, typeWidth := 0, 0
+
+	for _, column := range table.Columns {
+		if len([]rune(column.Name)) > nameWidth {
+			nameWidth = len([]rune(column.Name))
+		}
+
+		if len([]rune(column.Type)) > typeWidth {
+			typeWidth = len([]rune(column.Type))
+		}
+	}
+
+	lines := []string{table.Name}
+
+	for _, column := range table.Columns {
+		line := fmt.Sprintf("%-*s %-*s %s", nameWidth, column.Name, typeWidth, column.Type, strings.Join(columnKeys(column, foreignKeys[column.Name]), " "))
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+
+	return lines
+}
+
+// RenderDiagram draws the tables of a diagram as boxes in columns, with a line from the columns
+// of each foreign key to the table it references. References that can't be drawn from right to
+// left, because they close a cycle or reference the same table, are listed below the diagram.
+func RenderDiagram(diagram models.Diagram) DiagramLayout {
+	index := map[string]int{}
+	for i, table := range diagram.Tables {
+		index[table.Name] = i
+	}
+
+	layers := diagramLayers(diagram, index)
+	foreignKeys := foreignKeyColumns(diagram)
+
+	columnCount := 0
+	for _, layer := range layers {
+		if layer+1 > columnCount {
+			columnCount = layer + 1
+		}
+	}
+
+	boxLines := make([][]string, len(diagram.Tables))
+	boxes := make([]DiagramBox, len(diagram.Tables))
+	columnWidths := make([]int, columnCount)
+	columnHeights := make([]int, columnCount)
+
+	for i, table := range diagram.Tables {
+		boxLines[i] = diagramBoxLines(table, foreignKeys[table.Name])
+
+		width := 0
+		for _, line := range boxLines[i] {
+			if len([]rune(line)) > width {
+				width = len([]rune(line))
+			}
+		}
+
+		height := 3
+		if len(table.Columns) > 0 {
+			height += len(table.Columns) + 1
+		}
+
+		layer := layers[i]
+		boxes[i] = DiagramBox{Table: table.Name, Y: columnHeights[layer], Width: width + 4, Height: height}
+
+		if width+4 > columnWidths[layer] {
+			columnWidths[layer] = width + 4
+		}
+
+		columnHeights[layer] += height + 1
+	}
+
+	// Each foreign key goes down the gap to the left of its table, and the ones that skip columns
+	// go down the gap to the right of the referenced table too, each one in its own lane
+	type route struct {
+		foreignKey models.ForeignKey
+		source     int
+		target     int
+		sourceLane int
+		targetLane int
+	}
+
+	routes := []route{}
+	notDrawn := []models.ForeignKey{}
+	gapLanes := make([]int, columnCount)
+
+	for _, foreignKey := range diagram.ForeignKeys {
+		source, ok := index[foreignKey.Table]
+		target, targetOk := index[foreignKey.ReferencedTable]
+
+		if !ok || !targetOk || layers[target] >= layers[source] {
+			notDrawn = append(notDrawn, foreignKey)
+			continue
+		}
+
+		r := route{foreignKey: foreignKey, source: source, target: target}
+
+		r.sourceLane = gapLanes[layers[source]-1]
+		gapLanes[layers[source]-1]++
+
+		if layers[source]-layers[target] > 1 {
+			r.targetLane = gapLanes[layers[target]]
+			gapLanes[layers[target]]++
+		}
+
+		routes = append(routes, r)
+	}
+
+	columnX := make([]int, columnCount)
+	gapX := make([]int, columnCount)
+	width := 0
+
+	for column := 0; column < columnCount; column++ {
+		if column > 0 {
+			columnX[column] = gapX[column-1] + gapLanes[column-1] + 2
+		}
+
+		gapX[column] = columnX[column] + columnWidths[column] + 2
+		width = columnX[column] + columnWidths[column]
+	}
+
+	height := 0
+	for i := range boxes {
+		boxes[i].X = columnX[layers[i]]
+
+		if boxes[i].Y+boxes[i].Height > height {
+			height = boxes[i].Y + boxes[i].Height
+		}
+	}
+
+	canvas := &diagramCanvas{text: map[[2]int]rune{}, lines: map[[2]int]uint8{}}
+
+	// columnRow returns the row of a column in the box of a table, or the row of its title
+	columnRow := func(table int, column string) int {
+		for i, tableColumn := range diagram.Tables[table].Columns {
+			if tableColumn.Name == column {
+				return boxes[table].Y + 3 + i
+			}
+		}
+
+		return boxes[table].Y + 1
+	}
+
+	// isFree reports whether a row is outside of the boxes of some columns
+	isFree := func(y, firstColumn, lastColumn int) bool {
+		for i, box := range boxes {
+			if layers[i] >= firstColumn && layers[i] <= lastColumn && y >= box.Y && y < box.Y+box.Height {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	exits := map[[2]int]bool{}
+	entries := map[[2]int]bool{}
+	crossingRows := map[int]bool{}
+
+	for _, r := range routes {
+		source, target := boxes[r.source], boxes[r.target]
+		sourceRow := columnRow(r.source, r.foreignKey.Columns[0])
+		targetRow := columnRow(r.target, r.foreignKey.ReferencedColumns[0])
+
+		sourceLaneX := gapX[layers[r.source]-1] + r.sourceLane
+		targetLaneX := sourceLaneX
+		row := targetRow
+
+		if layers[r.source]-layers[r.target] > 1 {
+			targetLaneX = gapX[layers[r.target]] + r.targetLane
+
+			// Cross the columns in between through the free row closest to both ends, a different
+			// one for each foreign key so that their lines don't merge
+			row = height
+			for crossingRows[row] {
+				row++
+			}
+
+			for y := 0; y < height; y++ {
+				if !crossingRows[y] && isFree(y, layers[r.target]+1, layers[r.source]-1) && abs(y-sourceRow)+abs(y-targetRow) < abs(row-sourceRow)+abs(row-targetRow) {
+					row = y
+				}
+			}
+
+			crossingRows[row] = true
+
+			if row >= height {
+				height = row + 1
+			}
+		}
+
+		canvas.horizontal(source.X-1, sourceLaneX, sourceRow)
+		canvas.vertical(sourceLaneX, sourceRow, row)
+		canvas.horizontal(sourceLaneX, targetLaneX, row)
+		canvas.vertical(targetLaneX, row, targetRow)
+		canvas.horizontal(targetLaneX, target.X+target.Width, targetRow)
+
+		exits[[2]int{source.X, sourceRow}] = true
+		entries[[2]int{target.X + target.Width - 1, targetRow}] = true
+	}
+
+	for i, box := range boxes {
+		canvas.write(box.X, box.Y, "┌"+strings.Repeat("─", box.Width-2)+"┐")
+
+		for j, line := range boxLines[i] {
+			y := box.Y + 1 + j
+			if j > 0 {
+				y++
+			}
+
+			canvas.write(box.X, y, "│ "+line+strings.Repeat(" ", box.Width-4-len([]rune(line)))+" │")
+		}
+
+		if len(boxLines[i]) > 1 {
+			canvas.write(box.X, box.Y+2, "├"+strings.Repeat("─", box.Width-2)+"┤")
+		}
+
+		canvas.write(box.X, box.Y+box.Height-1, "└"+strings.Repeat("─", box.Width-2)+"┘")
+	}
+
+	for exit := range exits {
+		canvas.write(exit[0], exit[1], "┤")
+	}
+
+	for entry := range entries {
+		canvas.write(entry[0], entry[1], "├<")
+	}
+
+	layout := DiagramLayout{Lines: canvas.render(width, height), Boxes: boxes}
+
+	if len(notDrawn) > 0 {
+		layout.Lines = append(layout.Lines, "", "Not drawn:")
+
+		for _, foreignKey := range notDrawn {
+			layout.Lines = append(layout.Lines, fmt.Sprintf("  %s (%s) -> %s (%s)", foreignKey.Table, strings.Join(foreignKey.Columns, ", "), foreignKey.ReferencedTable, strings.Join(foreignKey.ReferencedColumns, ", ")))
+		}
+	}
+
+	return layout
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+var testDiagram = models.Diagram{
+	Tables: []models.DiagramTable{
+		{Name: "public.customers", Columns: []models.DiagramColumn{{Name: "id", Type: "integer", PrimaryKey: true}}},
+		{Name: "public.orders", Columns: []models.DiagramColumn{{Name: "id", Type: "integer", PrimaryKey: true}, {Name: "customer_id", Type: "character varying"}}},
+	},
+	ForeignKeys: []models.ForeignKey{
+		{Name: "orders_customer_fkey", Table: "public.orders", Columns: []string{"customer_id"}, ReferencedTable: "public.customers", ReferencedColumns: []string{"id"}},
+	},
+}
+
+func TestExportDiagram(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "dot",
+			format: DiagramFormatDOT,
+			want: "digraph diagram {\n\trankdir=LR;\n\tnode [shape=plaintext];\n\n" +
+				"\t\"public.customers\" [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\"><tr><td bgcolor=\"lightgrey\"><b>public.customers</b></td></tr><tr><td port=\"id\" align=\"left\">id integer PK</td></tr></table>>];\n" +
+				"\t\"public.orders\" [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\"><tr><td bgcolor=\"lightgrey\"><b>public.orders</b></td></tr><tr><td port=\"id\" align=\"left\">id integer PK</td></tr><tr><td port=\"customer_id\" align=\"left\">customer_id character varying FK</td></tr></table>>];\n\n" +
+				"\t\"public.orders\":\"customer_id\" -> \"public.customers\":\"id\" [label=\"customer_id\"];\n}\n",
+		},
+		{
+			name:   "mermaid",
+			format: DiagramFormatMermaid,
+			want: "erDiagram\n" +
+				"    public_customers {\n        integer id PK\n    }\n" +
+				"    public_orders {\n        integer id PK\n        character_varying customer_id FK\n    }\n" +
+				"    public_customers ||--o{ public_orders : \"customer_id\"\n",
+		},
+		{
+			name:    "unknown format",
+			format:  "png",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+
+			err := ExportDiagram(&buffer, testDiagram, tt.format)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportDiagram() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := buffer.String(); got != tt.want {
+				t.Errorf("ExportDiagram() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderDiagram(t *testing.T) {
+	layout := RenderDiagram(testDiagram)
+
+	want := []string{
+		"┌──────────────────┐     ┌──────────────────────────────────┐",
+		"│ public.customers │     │ public.orders                    │",
+		"├──────────────────┤     ├──────────────────────────────────┤",
+		"│ id integer PK    ├<─┐  │ id          integer           PK │",
+		"└──────────────────┘  └──┤ customer_id character varying FK │",
+		"                         └──────────────────────────────────┘",
+	}
+
+	if !reflect.DeepEqual(layout.Lines, want) {
+		t.Errorf("RenderDiagram() lines =\n%s\nwant\n%s", strings.Join(layout.Lines, "\n"), strings.Join(want, "\n"))
+	}
+
+	wantBoxes := []DiagramBox{
+		{Table: "public.customers", X: 0, Y: 0, Width: 20, Height: 5},
+		{Table: "public.orders", X: 25, Y: 0, Width: 36, Height: 6},
+	}
+
+	if !reflect.DeepEqual(layout.Boxes, wantBoxes) {
+		t.Errorf("RenderDiagram() boxes = %+v, want %+v", layout.Boxes, wantBoxes)
+	}
+}
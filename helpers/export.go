@@ -0,0 +1,204 @@
+package helpers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	ExportFormatCSV      = "csv"
+	ExportFormatTSV      = "tsv"
+	ExportFormatJSON     = "json"
+	ExportFormatMarkdown = "markdown"
+	ExportFormatTable    = "table"
+)
+
+// ExportFormats are the formats supported by ExportRows.
+var ExportFormats = []string{ExportFormatCSV, ExportFormatTSV, ExportFormatJSON, ExportFormatMarkdown, ExportFormatTable}
+
+// IsExportFormat reports whether the format is supported by ExportRows.
+func IsExportFormat(format string) bool {
+	for _, exportFormat := range ExportFormats {
+		if strings.EqualFold(exportFormat, format) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExportRows writes the rows in the given format. The first row holds the column names,
+// like the results returned by the drivers.
+func ExportRows(w io.Writer, rows [][]string, format string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	switch strings.ToLower(format) {
+	case ExportFormatCSV:
+		return exportDelimited(w, rows, ',')
+	case ExportFormatTSV:
+		return exportDelimited(w, rows, '\t')
+	case ExportFormatJSON:
+		return exportJSON(w, rows)
+	case ExportFormatMarkdown:
+		return exportMarkdown(w, rows)
+	case ExportFormatTable:
+		return exportTable(w, rows)
+	}
+
+	return fmt.Errorf("unknown format %q, expected one of: %s", format, strings.Join(ExportFormats, ", "))
+}
+
+// exportValue returns the value of the cell and whether it is NULL, translating the
+// placeholders the drivers use for NULL and empty values.
+func exportValue(cell string) (string, bool) {
+	switch cell {
+	case "NULL&":
+		return "", true
+	case "EMPTY&", "DEFAULT&":
+		return "", false
+	}
+
+	return cell, false
+}
+
+func exportDelimited(w io.Writer, rows [][]string, delimiter rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+
+	for _, row := range rows {
+		record := make([]string, len(row))
+
+		for i, cell := range row {
+			record[i], _ = exportValue(cell)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func exportJSON(w io.Writer, rows [][]string) error {
+	columns := rows[0]
+
+	// Each row is written as an object keeping the order of the columns
+	var builder strings.Builder
+
+	builder.WriteString("[")
+
+	for i, row := range rows[1:] {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+
+		builder.WriteString("\n  {")
+
+		for j, column := range columns {
+			if j > 0 {
+				builder.WriteString(", ")
+			}
+
+			key, err := json.Marshal(column)
+			if err != nil {
+				return err
+			}
+
+			builder.Write(key)
+			builder.WriteString(": ")
+
+			value, isNull := "", true
+			if j < len(row) {
+				value, isNull = exportValue(row[j])
+			}
+
+			if isNull {
+				builder.WriteString("null")
+				continue
+			}
+
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+
+			builder.Write(encoded)
+		}
+
+		builder.WriteString("}")
+	}
+
+	if len(rows) > 1 {
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("]\n")
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+func exportMarkdown(w io.Writer, rows [][]string) error {
+	var builder strings.Builder
+
+	for i, row := range rows {
+		cells := make([]string, len(row))
+
+		for j, cell := range row {
+			value, isNull := exportValue(cell)
+			if isNull {
+				value = "NULL"
+			}
+
+			cells[j] = strings.ReplaceAll(strings.ReplaceAll(value, "|", "\\|"), "\n", " ")
+		}
+
+		builder.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+
+		if i == 0 {
+			separators := make([]string, len(row))
+			for j := range separators {
+				separators[j] = "---"
+			}
+
+			builder.WriteString("| " + strings.Join(separators, " | ") + " |\n")
+		}
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+func exportTable(w io.Writer, rows [][]string) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+
+		for i, cell := range row {
+			value, isNull := exportValue(cell)
+			if isNull {
+				value = "NULL"
+			}
+
+			cells[i] = strings.ReplaceAll(value, "\n", " ")
+		}
+
+		if _, err := fmt.Fprintln(writer, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExportRows(t *testing.T) {
+	rows := [][]string{
+		{"id", "name"},
+		{"1", "a, \"b\""},
+		{"2", "NULL&"},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "csv",
+			format: ExportFormatCSV,
+			want:   "id,name\n1,\"a, \"\"b\"\"\"\n2,\n",
+		},
+		{
+			name:   "tsv",
+			format: ExportFormatTSV,
+			want:   "id\tname\n1\t\"a, \"\"b\"\"\"\n2\t\n",
+		},
+		{
+			name:   "json",
+			format: ExportFormatJSON,
+			want:   "[\n  {\"id\": \"1\", \"name\": \"a, \\\"b\\\"\"},\n  {\"id\": \"2\", \"name\": null}\n]\n",
+		},
+		{
+			name:   "markdown",
+			format: ExportFormatMarkdown,
+			want:   "| id | name |\n| --- | --- |\n| 1 | a, \"b\" |\n| 2 | NULL |\n",
+		},
+		{
+			name:   "table",
+			format: ExportFormatTable,
+			want:   "id  name\n1   a, \"b\"\n2   NULL\n",
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+
+			err := ExportRows(&buffer, rows, tt.format)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := buffer.String(); got != tt.want {
+				t.Errorf("ExportRows() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/tview"
+)
+
+// SQLHighlightColors are the tview color names HighlightSQL uses for each kind of token.
+type SQLHighlightColors struct {
+	Keyword string
+	String  string
+	Number  string
+	Comment string
+}
+
+var sqlKeywords = map[string]bool{}
+
+func init() {
+	keywords := `ADD ALL ALTER ALWAYS AND AS ASC AUTOINCREMENT AUTO_INCREMENT BEFORE AFTER BEGIN BETWEEN BY
+	CASCADE CASE CHARSET CHARACTER CHECK COLLATE COLUMN COMMENT CONSTRAINT CREATE CROSS CURRENT_TIMESTAMP CYCLE
+	DATABASE DECLARE DEFAULT DEFERRABLE DEFINER DELETE DESC DISTINCT DOMAIN DROP EACH ELSE END ENGINE ENUM
+	EXCLUDE EXECUTE EXISTS FOR FOREIGN FROM FULL FUNCTION GENERATED GROUP HAVING IDENTITY IF IN INCREMENT
+	INDEX INITIALLY INNER INSERT INSTEAD INTO IS JOIN KEY LANGUAGE LEFT LIKE LIMIT MATERIALIZED MAXVALUE
+	MINVALUE NO NOT NULL OF OFFSET ON OR ORDER OUTER PRIMARY PROCEDURE RANGE REFERENCES REPLACE RESTRICT
+	RETURN RETURNS RIGHT ROW SCHEMA SELECT SEQUENCE SET START STORED TABLE TEMPORARY THEN TO TRIGGER TYPE
+	UNION UNIQUE UNSIGNED UPDATE USING VALUES VIEW WHEN WHERE WITH WITHOUT`
+
+	for _, keyword := range strings.Fields(keywords) {
+		sqlKeywords[keyword] = true
+	}
+}
+
+// HighlightSQL returns the SQL with tview color tags around keywords, strings, numbers and
+// comments. The rest of the text is escaped, so it can be shown in views with dynamic colors.
+func HighlightSQL(sql string, colors SQLHighlightColors) string {
+	var builder strings.Builder
+	var plain strings.Builder
+
+	runes := []rune(sql)
+
+	// Plain text is escaped in one piece, escaping it a rune at a time would miss the
+	// brackets that look like color tags
+	flushPlain := func() {
+		builder.WriteString(tview.Escape(plain.String()))
+		plain.Reset()
+	}
+
+	colorize := func(color string, text string) {
+		flushPlain()
+
+		if color == "" {
+			builder.WriteString(tview.Escape(text))
+			return
+		}
+
+		builder.WriteString(fmt.Sprintf("[%s]%s[-]", color, tview.Escape(text)))
+	}
+
+	for i := 0; i < len(runes); {
+		current := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		end := i + 1
+
+		switch {
+		case current == '-' && next == '-':
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+
+			colorize(colors.Comment, string(runes[i:end]))
+		case current == '/' && next == '*':
+			end = i + 2
+			for end+1 < len(runes) && !(runes[end] == '*' && runes[end+1] == '/') {
+				end++
+			}
+
+			end += 2
+			if end > len(runes) {
+				end = len(runes)
+			}
+
+			colorize(colors.Comment, string(runes[i:end]))
+		case current == '\'':
+			for end < len(runes) {
+				if runes[end] == '\\' {
+					end += 2
+					continue
+				}
+
+				if runes[end] == '\'' {
+					// A doubled quote is an escaped quote
+					if end+1 < len(runes) && runes[end+1] == '\'' {
+						end += 2
+						continue
+					}
+
+					end++
+					break
+				}
+
+				end++
+			}
+
+			if end > len(runes) {
+				end = len(runes)
+			}
+
+			colorize(colors.String, string(runes[i:end]))
+		case current == '"' || current == '`':
+			// Quoted identifiers are not highlighted, but keywords inside them must not be either
+			for end < len(runes) && runes[end] != current {
+				end++
+			}
+
+			if end < len(runes) {
+				end++
+			}
+
+			plain.WriteString(string(runes[i:end]))
+		case unicode.IsLetter(current) || current == '_':
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '$') {
+				end++
+			}
+
+			word := string(runes[i:end])
+
+			if sqlKeywords[strings.ToUpper(word)] {
+				colorize(colors.Keyword, word)
+			} else {
+				plain.WriteString(word)
+			}
+		case unicode.IsDigit(current):
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+
+			colorize(colors.Number, string(runes[i:end]))
+		default:
+			plain.WriteRune(current)
+		}
+
+		i = end
+	}
+
+	flushPlain()
+
+	return builder.String()
+}
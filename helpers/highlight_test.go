@@ -0,0 +1,42 @@
+package helpers
+
+import "testing"
+
+func TestHighlightSQL(t *testing.T) {
+	colors := SQLHighlightColors{Keyword: "yellow", String: "green", Number: "blue", Comment: "gray"}
+
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "keywords, strings and numbers",
+			sql:  "CREATE TABLE t (id int DEFAULT 1, name text DEFAULT 'it''s')",
+			want: "[yellow]CREATE[-] [yellow]TABLE[-] t (id int [yellow]DEFAULT[-] [blue]1[-], name text [yellow]DEFAULT[-] [green]'it''s'[-])",
+		},
+		{
+			name: "comments",
+			sql:  "-- select\nSELECT /* from */ 1",
+			want: "[gray]-- select[-]\n[yellow]SELECT[-] [gray]/* from */[-] [blue]1[-]",
+		},
+		{
+			name: "quoted identifiers are not highlighted",
+			sql:  "SELECT \"select\", `from` FROM t",
+			want: "[yellow]SELECT[-] \"select\", `from` [yellow]FROM[-] t",
+		},
+		{
+			name: "brackets are escaped",
+			sql:  "SELECT a[red]",
+			want: "[yellow]SELECT[-] a[red[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HighlightSQL(tt.sql, colors); got != tt.want {
+				t.Errorf("HighlightSQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
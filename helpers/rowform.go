@@ -0,0 +1,170 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of the inputs of the row form
+const (
+	FieldText     = "text"
+	FieldBoolean  = "boolean"
+	FieldEnum     = "enum"
+	FieldInteger  = "integer"
+	FieldDecimal  = "decimal"
+	FieldDate     = "date"
+	FieldDateTime = "datetime"
+	FieldTime     = "time"
+)
+
+var fieldKinds = map[string]string{
+	"bool":                        FieldBoolean,
+	"boolean":                     FieldBoolean,
+	"int":                         FieldInteger,
+	"integer":                     FieldInteger,
+	"tinyint":                     FieldInteger,
+	"smallint":                    FieldInteger,
+	"mediumint":                   FieldInteger,
+	"bigint":                      FieldInteger,
+	"int2":                        FieldInteger,
+	"int4":                        FieldInteger,
+	"int8":                        FieldInteger,
+	"serial":                      FieldInteger,
+	"smallserial":                 FieldInteger,
+	"bigserial":                   FieldInteger,
+	"numeric":                     FieldDecimal,
+	"decimal":                     FieldDecimal,
+	"real":                        FieldDecimal,
+	"float":                       FieldDecimal,
+	"float4":                      FieldDecimal,
+	"float8":                      FieldDecimal,
+	"double":                      FieldDecimal,
+	"double precision":            FieldDecimal,
+	"date":                        FieldDate,
+	"datetime":                    FieldDateTime,
+	"timestamp":                   FieldDateTime,
+	"timestamptz":                 FieldDateTime,
+	"timestamp with time zone":    FieldDateTime,
+	"timestamp without time zone": FieldDateTime,
+	"time":                        FieldTime,
+	"timetz":                      FieldTime,
+	"time with time zone":         FieldTime,
+	"time without time zone":      FieldTime,
+}
+
+// Layouts accepted by the date and time fields, the fractions of a second are always accepted
+var (
+	dateLayouts     = []string{"2006-01-02"}
+	dateTimeLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04:05Z07", "2006-01-02 15:04:05Z07:00", "2006-01-02T15:04:05", "2006-01-02T15:04:05Z07:00", "2006-01-02 15:04", "2006-01-02"}
+	timeLayouts     = []string{"15:04:05", "15:04:05Z07", "15:04:05Z07:00", "15:04"}
+)
+
+// FieldKind tells which input of the row form suits a column of the given type. MySQL stores
+// booleans as tinyint(1).
+func FieldKind(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+
+	if strings.HasPrefix(columnType, "enum(") {
+		return FieldEnum
+	}
+
+	if strings.HasPrefix(columnType, "tinyint(1)") {
+		return FieldBoolean
+	}
+
+	base, _, _ := strings.Cut(columnType, "(")
+	base = strings.TrimSpace(strings.NewReplacer(" unsigned", "", " zerofill", "").Replace(base))
+
+	if kind, ok := fieldKinds[base]; ok {
+		return kind
+	}
+
+	return FieldText
+}
+
+// EnumValues returns the values of a MySQL enum type, like enum('a','b'), or of a list of
+// quoted values.
+func EnumValues(definition string) []string {
+	values := []string{}
+
+	var value strings.Builder
+	quoted := false
+
+	for i := 0; i < len(definition); i++ {
+		char := definition[i]
+
+		switch {
+		case char == '\'' && !quoted:
+			quoted = true
+			value.Reset()
+		case char == '\'' && i+1 < len(definition) && definition[i+1] == '\'':
+			value.WriteByte(char)
+			i++
+		case char == '\'':
+			quoted = false
+			values = append(values, value.String())
+		case quoted:
+			value.WriteByte(char)
+		}
+	}
+
+	return values
+}
+
+// ValidateField returns an error when the value does not suit a field of the kind.
+func ValidateField(kind, value string) error {
+	switch kind {
+	case FieldInteger:
+		// Unsigned columns hold values above the largest signed one
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+				return fmt.Errorf("%q is not a whole number", value)
+			}
+		}
+	case FieldDecimal:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case FieldDate:
+		if !matchesTimeLayout(value, dateLayouts) {
+			return fmt.Errorf("%q is not a date like 2006-01-02", value)
+		}
+	case FieldDateTime:
+		if !matchesTimeLayout(value, dateTimeLayouts) {
+			return fmt.Errorf("%q is not a date and time like 2006-01-02 15:04:05", value)
+		}
+	case FieldTime:
+		if !matchesTimeLayout(value, timeLayouts) {
+			return fmt.Errorf("%q is not a time like 15:04:05", value)
+		}
+	}
+
+	return nil
+}
+
+// IsTrue tells whether the value of a boolean column is true, as the drivers show them.
+func IsTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true
+	}
+
+	return false
+}
+
+func matchesTimeLayout(value string, layouts []string) bool {
+	// MySQL accepts zero dates
+	if strings.HasPrefix(value, "0000-00-00") {
+		return true
+	}
+
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldKind(t *testing.T) {
+	tests := []struct {
+		columnType string
+		want       string
+	}{
+		{columnType: "varchar(255)", want: FieldText},
+		{columnType: "character varying", want: FieldText},
+		{columnType: "tinyint(1)", want: FieldBoolean},
+		{columnType: "tinyint(4)", want: FieldInteger},
+		{columnType: "boolean", want: FieldBoolean},
+		{columnType: "int(10) unsigned", want: FieldInteger},
+		{columnType: "INTEGER", want: FieldInteger},
+		{columnType: "interval", want: FieldText},
+		{columnType: "decimal(10,2)", want: FieldDecimal},
+		{columnType: "double precision", want: FieldDecimal},
+		{columnType: "enum('small','large')", want: FieldEnum},
+		{columnType: "date", want: FieldDate},
+		{columnType: "timestamp without time zone", want: FieldDateTime},
+		{columnType: "datetime(6)", want: FieldDateTime},
+		{columnType: "time", want: FieldTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.columnType, func(t *testing.T) {
+			if got := FieldKind(tt.columnType); got != tt.want {
+				t.Errorf("FieldKind(%q) = %q, want %q", tt.columnType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnumValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		want       []string
+	}{
+		{name: "mysql enum", definition: "enum('small','large')", want: []string{"small", "large"}},
+		{name: "quoted list", definition: "'a b', 'it''s', ''", want: []string{"a b", "it's", ""}},
+		{name: "no values", definition: "varchar(10)", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EnumValues(tt.definition); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EnumValues(%q) = %q, want %q", tt.definition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateField(t *testing.T) {
+	tests := []struct {
+		kind    string
+		value   string
+		wantErr bool
+	}{
+		{kind: FieldText, value: "anything", wantErr: false},
+		{kind: FieldInteger, value: "-42", wantErr: false},
+		{kind: FieldInteger, value: "4.2", wantErr: true},
+		{kind: FieldInteger, value: "18446744073709551615", wantErr: false},
+		{kind: FieldInteger, value: "18446744073709551616", wantErr: true},
+		{kind: FieldDecimal, value: "4.2", wantErr: false},
+		{kind: FieldDecimal, value: "four", wantErr: true},
+		{kind: FieldDate, value: "2024-02-29", wantErr: false},
+		{kind: FieldDate, value: "2023-02-29", wantErr: true},
+		{kind: FieldDate, value: "0000-00-00", wantErr: false},
+		{kind: FieldDateTime, value: "2024-01-02 10:20:30.123456", wantErr: false},
+		{kind: FieldDateTime, value: "2024-01-02 10:20:30+02", wantErr: false},
+		{kind: FieldDateTime, value: "2024-01-02T10:20:30Z", wantErr: false},
+		{kind: FieldDateTime, value: "yesterday", wantErr: true},
+		{kind: FieldTime, value: "23:59", wantErr: false},
+		{kind: FieldTime, value: "25:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+" "+tt.value, func(t *testing.T) {
+			if err := ValidateField(tt.kind, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateField(%q, %q) error = %v, wantErr %v", tt.kind, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// State is what lazysql remembers between runs. It is kept apart from the config because it is
+// written as lazysql is used, not edited by hand.
+type State struct {
+	// Layouts maps a connection, database and table, as returned by TableLayoutKey, to the
+	// layout of its records
+	Layouts map[string]models.TableLayout `toml:"layouts,omitempty"`
+	// Workspaces maps the name of a connection to what was open on it
+	Workspaces map[string]models.Workspace `toml:"workspaces,omitempty"`
+}
+
+func stateFilePath() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "lazysql", "state.toml")
+}
+
+// LoadState reads the state file, a missing file is an empty state.
+func LoadState() (state State, err error) {
+	file, err := os.ReadFile(stateFilePath())
+	if os.IsNotExist(err) {
+		return State{}, nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	err = toml.Unmarshal(file, &state)
+
+	return
+}
+
+func SaveState(state State) error {
+	err := os.MkdirAll(filepath.Dir(stateFilePath()), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(stateFilePath())
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return toml.NewEncoder(file).Encode(state)
+}
+
+// TableLayoutKey returns the key of the layout of a table of a connection in the state.
+func TableLayoutKey(connection, database, table string) string {
+	return connection + "/" + database + "/" + table
+}
+
+// LoadTableLayout returns the saved layout of a table, which is empty when there is none.
+func LoadTableLayout(key string) (models.TableLayout, error) {
+	state, err := LoadState()
+	if err != nil {
+		return models.TableLayout{}, err
+	}
+
+	return state.Layouts[key], nil
+}
+
+// SaveTableLayout saves the layout of a table, keeping the rest of the state as it is.
+func SaveTableLayout(key string, layout models.TableLayout) error {
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	if state.Layouts == nil {
+		state.Layouts = map[string]models.TableLayout{}
+	}
+
+	if len(layout.Order) == 0 && len(layout.Hidden) == 0 && layout.Pinned == 0 && len(layout.Widths) == 0 {
+		delete(state.Layouts, key)
+	} else {
+		state.Layouts[key] = layout
+	}
+
+	return SaveState(state)
+}
+
+// LoadWorkspace returns the saved workspace of a connection, which is empty when there is none.
+func LoadWorkspace(connection string) (models.Workspace, error) {
+	state, err := LoadState()
+	if err != nil {
+		return models.Workspace{}, err
+	}
+
+	return state.Workspaces[connection], nil
+}
+
+// SaveWorkspaces saves the workspaces of some connections, keeping the rest of the state as it
+// is. Empty workspaces are removed.
+func SaveWorkspaces(workspaces map[string]models.Workspace) error {
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	if state.Workspaces == nil {
+		state.Workspaces = map[string]models.Workspace{}
+	}
+
+	for connection, workspace := range workspaces {
+		if len(workspace.Tabs) == 0 && !workspace.EditorOpen && len(workspace.PendingChanges) == 0 {
+			delete(state.Workspaces, connection)
+		} else {
+			state.Workspaces[connection] = workspace
+		}
+	}
+
+	return SaveState(state)
+}
+
+// maxDescribedValueLength is the number of characters of a value that DescribeChange shows.
+const maxDescribedValueLength = 30
+
+// DescribeChange describes a pending change in a line, with its table, the key of its row and
+// the new values of its columns, like: update orders (id = 5): status → 'paid'.
+func DescribeChange(change models.DbDmlChange) string {
+	keys := make([]string, 0, len(change.PrimaryKeyInfo))
+	for _, key := range change.PrimaryKeyInfo {
+		if key.IsNull {
+			keys = append(keys, key.Name+" IS NULL")
+		} else {
+			keys = append(keys, fmt.Sprintf("%s = %s", key.Name, describeValue(key.Value)))
+		}
+	}
+
+	values := make([]string, 0, len(change.Values))
+	for _, value := range change.Values {
+		switch value.Type {
+		case models.Null:
+			values = append(values, value.Column+" → NULL")
+		case models.Default:
+			values = append(values, value.Column+" → DEFAULT")
+		case models.Empty:
+			values = append(values, value.Column+" → ''")
+		default:
+			values = append(values, fmt.Sprintf("%s → %s", value.Column, describeValue(fmt.Sprint(value.Value))))
+		}
+	}
+
+	switch change.Type {
+	case models.DmlInsertType:
+		return fmt.Sprintf("insert into %s: %s", change.Table, strings.Join(values, ", "))
+	case models.DmlDeleteType:
+		return fmt.Sprintf("delete from %s (%s)", change.Table, strings.Join(keys, ", "))
+	}
+
+	return fmt.Sprintf("update %s (%s): %s", change.Table, strings.Join(keys, ", "), strings.Join(values, ", "))
+}
+
+// describeValue quotes a value, cut to maxDescribedValueLength characters.
+func describeValue(value string) string {
+	if runes := []rune(value); len(runes) > maxDescribedValueLength {
+		value = string(runes[:maxDescribedValueLength]) + "…"
+	}
+
+	return "'" + value + "'"
+}
+
+// ArrangeColumns returns the positions of the columns in the order of a layout, the visible
+// columns first and then the hidden ones, along with the number of visible columns.
+func ArrangeColumns(columns []string, layout models.TableLayout) (order []int, visible int) {
+	hidden := map[string]bool{}
+	for _, column := range layout.Hidden {
+		hidden[column] = true
+	}
+
+	positions := map[string]int{}
+	for i, column := range columns {
+		positions[column] = i
+	}
+
+	arranged := make([]bool, len(columns))
+	visibleOrder := []int{}
+	hiddenOrder := []int{}
+
+	add := func(i int) {
+		if arranged[i] {
+			return
+		}
+
+		arranged[i] = true
+
+		if hidden[columns[i]] {
+			hiddenOrder = append(hiddenOrder, i)
+		} else {
+			visibleOrder = append(visibleOrder, i)
+		}
+	}
+
+	for _, column := range layout.Order {
+		if i, ok := positions[column]; ok {
+			add(i)
+		}
+	}
+
+	for i := range columns {
+		add(i)
+	}
+
+	return append(visibleOrder, hiddenOrder...), len(visibleOrder)
+}
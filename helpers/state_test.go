@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestArrangeColumns(t *testing.T) {
+	columns := []string{"id", "name", "email", "notes"}
+
+	tests := []struct {
+		name        string
+		layout      models.TableLayout
+		wantOrder   []int
+		wantVisible int
+	}{
+		{
+			name:        "No layout",
+			wantOrder:   []int{0, 1, 2, 3},
+			wantVisible: 4,
+		},
+		{
+			name:        "Ordered columns first",
+			layout:      models.TableLayout{Order: []string{"email", "id"}},
+			wantOrder:   []int{2, 0, 1, 3},
+			wantVisible: 4,
+		},
+		{
+			name:        "Hidden columns last",
+			layout:      models.TableLayout{Order: []string{"notes", "email"}, Hidden: []string{"notes", "name"}},
+			wantOrder:   []int{2, 0, 3, 1},
+			wantVisible: 2,
+		},
+		{
+			name:        "Dropped columns",
+			layout:      models.TableLayout{Order: []string{"old", "name"}, Hidden: []string{"gone"}},
+			wantOrder:   []int{1, 0, 2, 3},
+			wantVisible: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, visible := ArrangeColumns(columns, tt.layout)
+			if !reflect.DeepEqual(order, tt.wantOrder) || visible != tt.wantVisible {
+				t.Errorf("ArrangeColumns() = %v, %d, want %v, %d", order, visible, tt.wantOrder, tt.wantVisible)
+			}
+		})
+	}
+}
+
+func TestDescribeChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change models.DbDmlChange
+		want   string
+	}{
+		{
+			name: "Update",
+			change: models.DbDmlChange{
+				Type:           models.DmlUpdateType,
+				Table:          "orders",
+				PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "id", Value: "5"}},
+				Values:         []models.CellValue{{Column: "status", Type: models.String, Value: "paid"}, {Column: "note", Type: models.Null}},
+			},
+			want: "update orders (id = '5'): status → 'paid', note → NULL",
+		},
+		{
+			name: "Delete",
+			change: models.DbDmlChange{
+				Type:           models.DmlDeleteType,
+				Table:          "public.orders",
+				PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "id", Value: "5"}, {Name: "region", IsNull: true}},
+			},
+			want: "delete from public.orders (id = '5', region IS NULL)",
+		},
+		{
+			name: "Insert with a long value",
+			change: models.DbDmlChange{
+				Type:   models.DmlInsertType,
+				Table:  "notes",
+				Values: []models.CellValue{{Column: "id", Type: models.Default}, {Column: "body", Type: models.String, Value: strings.Repeat("a", 40)}},
+			},
+			want: "insert into notes: id → DEFAULT, body → '" + strings.Repeat("a", 30) + "…'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeChange(tt.change); got != tt.want {
+				t.Errorf("DescribeChange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,34 @@
 package helpers
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/xo/dburl"
 
 	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/models"
 )
 
+// readOnlyKeywords are the leading keywords of statements that do not modify data.
+var readOnlyKeywords = []string{"select", "with", "show", "explain", "describe", "desc", "values", "table"}
+
+// writeKeywords are the keywords that make a statement that starts like a read write data, as
+// in a data-modifying CTE or SELECT ... INTO.
+var writeKeywords = map[string]bool{
+	"insert":   true,
+	"update":   true,
+	"delete":   true,
+	"merge":    true,
+	"into":     true,
+	"create":   true,
+	"drop":     true,
+	"alter":    true,
+	"truncate": true,
+	"grant":    true,
+	"revoke":   true,
+}
+
 func ParseConnectionString(url string) (*dburl.URL, error) {
 	return dburl.Parse(url)
 }
@@ -18,3 +41,319 @@ func ContainsCommand(commands []commands.Command, command commands.Command) bool
 	}
 	return false
 }
+
+// IsReadOnlyQuery reports whether the query only reads data: it starts with the keyword of a
+// statement that returns rows and has no keyword that writes outside strings and comments.
+// The row locks of SELECT ... FOR UPDATE are allowed.
+func IsReadOnlyQuery(query string) bool {
+	if !ReturnsRows(query) {
+		return false
+	}
+
+	words := queryWords(query)
+
+	for i, word := range words {
+		if !writeKeywords[word] {
+			continue
+		}
+
+		if word == "update" && i > 0 && (words[i-1] == "for" || words[i-1] == "key") {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// ReturnsRows reports whether the query starts with the keyword of a statement
+// that returns rows. Leading comments and parentheses are ignored.
+func ReturnsRows(query string) bool {
+	keyword := strings.ToLower(FirstKeyword(query))
+
+	for _, readOnlyKeyword := range readOnlyKeywords {
+		if keyword == readOnlyKeyword {
+			return true
+		}
+	}
+
+	return false
+}
+
+// queryWords returns the words of the query in lower case, leaving out the strings, quoted
+// identifiers, comments and the names qualified by a dot.
+func queryWords(query string) []string {
+	words := []string{}
+	runes := []rune(query)
+
+	isWordRune := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && r != '`' {
+					i++
+				}
+			}
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i++
+		case r == '$':
+			// PostgreSQL dollar quoted strings, $$...$$ or $tag$...$tag$
+			end := i + 1
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+
+			if end < len(runes) && runes[end] == '$' {
+				tag := string(runes[i : end+1])
+				closing := strings.Index(string(runes[end+1:]), tag)
+
+				if closing == -1 {
+					i = len(runes)
+				} else {
+					i = end + len([]rune(string(runes[end+1:])[:closing])) + len([]rune(tag))
+				}
+			} else {
+				i = end - 1
+			}
+		case isWordRune(r):
+			start := i
+			for i+1 < len(runes) && isWordRune(runes[i+1]) {
+				i++
+			}
+
+			if start == 0 || runes[start-1] != '.' {
+				words = append(words, strings.ToLower(string(runes[start:i+1])))
+			}
+		}
+	}
+
+	return words
+}
+
+// FirstKeyword returns the first word of the query, skipping whitespace,
+// comments and opening parentheses.
+func FirstKeyword(query string) string {
+	for {
+		query = strings.TrimLeft(query, " \t\r\n(")
+
+		if strings.HasPrefix(query, "--") || strings.HasPrefix(query, "#") {
+			end := strings.Index(query, "\n")
+			if end == -1 {
+				return ""
+			}
+			query = query[end+1:]
+		} else if strings.HasPrefix(query, "/*") {
+			end := strings.Index(query, "*/")
+			if end == -1 {
+				return ""
+			}
+			query = query[end+2:]
+		} else {
+			break
+		}
+	}
+
+	end := strings.IndexFunc(query, func(r rune) bool {
+		return !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'))
+	})
+	if end == -1 {
+		return query
+	}
+
+	return query[:end]
+}
+
+// FuzzyMatch reports whether all the characters of pattern appear in text in
+// the same order. The comparison is case insensitive.
+func FuzzyMatch(pattern, text string) bool {
+	patternRunes := []rune(strings.ToLower(pattern))
+	if len(patternRunes) == 0 {
+		return true
+	}
+
+	index := 0
+
+	for _, r := range strings.ToLower(text) {
+		if r == patternRunes[index] {
+			index++
+			if index == len(patternRunes) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ConnectionFromURL builds a connection from a connection string, taking the
+// provider and database name from it.
+func ConnectionFromURL(name string, url string) (models.Connection, error) {
+	parsed, err := ParseConnectionString(url)
+	if err != nil {
+		return models.Connection{}, err
+	}
+
+	DBName := strings.Split(parsed.Normalize(",", "NULL", 0), ",")[3]
+
+	if DBName == "NULL" {
+		DBName = ""
+	}
+
+	return models.Connection{
+		Name:     name,
+		Provider: parsed.Driver,
+		DBName:   DBName,
+		URL:      url,
+	}, nil
+}
+
+// ConnectionNameFromURL names a connection opened from a connection string by its user, host and
+// database, or its file, leaving out the password and the parameters, since the name is shown and
+// saved with the workspaces.
+func ConnectionNameFromURL(url string) string {
+	parsed, err := ParseConnectionString(url)
+	if err != nil {
+		return ""
+	}
+
+	if parsed.Opaque != "" {
+		return parsed.Opaque
+	}
+
+	name := parsed.Host + parsed.Path
+	if parsed.User != nil && parsed.User.Username() != "" {
+		name = parsed.User.Username() + "@" + name
+	}
+
+	if name == "" {
+		return parsed.Driver
+	}
+
+	return name
+}
+
+// SplitStatements splits a script into its statements on the semicolons that are
+// not inside quotes or comments. Empty statements are dropped.
+func SplitStatements(script string) []string {
+	statements := []string{}
+
+	var quote rune
+
+	start := 0
+	runes := []rune(script)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote != '`' {
+				i++
+			}
+			continue
+		}
+
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i++
+		case r == '$':
+			// PostgreSQL dollar quoted strings, $$...$$ or $tag$...$tag$
+			end := i + 1
+			for end < len(runes) && (runes[end] == '_' || unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+				end++
+			}
+
+			if end < len(runes) && runes[end] == '$' {
+				tag := string(runes[i : end+1])
+				closing := strings.Index(string(runes[end+1:]), tag)
+
+				if closing == -1 {
+					i = len(runes)
+				} else {
+					i = end + len([]rune(string(runes[end+1:])[:closing])) + len([]rune(tag))
+				}
+			}
+		case r == ';':
+			statements = appendStatement(statements, string(runes[start:i]))
+			start = i + 1
+		}
+	}
+
+	if start < len(runes) {
+		statements = appendStatement(statements, string(runes[start:]))
+	}
+
+	return statements
+}
+
+func appendStatement(statements []string, statement string) []string {
+	statement = strings.TrimSpace(statement)
+
+	if FirstKeyword(statement) == "" {
+		return statements
+	}
+
+	return append(statements, statement)
+}
+
+// transactionStatements maps the statements that control transactions to their command.
+var transactionStatements = map[string]commands.Command{
+	"begin":                       commands.BeginTransaction,
+	"begin transaction":           commands.BeginTransaction,
+	"begin work":                  commands.BeginTransaction,
+	"begin deferred":              commands.BeginTransaction,
+	"begin deferred transaction":  commands.BeginTransaction,
+	"begin immediate":             commands.BeginTransaction,
+	"begin immediate transaction": commands.BeginTransaction,
+	"begin exclusive":             commands.BeginTransaction,
+	"begin exclusive transaction": commands.BeginTransaction,
+	"start transaction":           commands.BeginTransaction,
+	"commit":                      commands.CommitTransaction,
+	"commit transaction":          commands.CommitTransaction,
+	"commit work":                 commands.CommitTransaction,
+	"end":                         commands.CommitTransaction,
+	"end transaction":             commands.CommitTransaction,
+	"end work":                    commands.CommitTransaction,
+	"rollback":                    commands.RollbackTransaction,
+	"rollback transaction":        commands.RollbackTransaction,
+	"rollback work":               commands.RollbackTransaction,
+	"abort":                       commands.RollbackTransaction,
+}
+
+// TransactionCommand returns the command of a query that only begins, commits or rolls
+// back a transaction, or Noop for any other query.
+func TransactionCommand(query string) commands.Command {
+	statement := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+
+	if command, ok := transactionStatements[strings.Join(strings.Fields(statement), " ")]; ok {
+		return command
+	}
+
+	return commands.Noop
+}
@@ -5,12 +5,17 @@ import "github.com/gdamore/tcell/v2"
 // Key is a structure that represents a key that can be bound
 // to an command
 type Key struct {
-	Code tcell.Key // Special character codes.
-	Char rune      // used when the key represents a single ascii char like "a" or "2".
+	Code tcell.Key     // Special character codes.
+	Char rune          // used when the key represents a single ascii char like "a" or "2".
+	Mod  tcell.ModMask // Modifier held with the char, only tcell.ModAlt is told apart.
 }
 
 func (k Key) String() string {
 	if k.Char != 0 {
+		if k.Mod&tcell.ModAlt != 0 {
+			return "Alt+" + string(k.Char)
+		}
+
 		return string(k.Char)
 	}
 
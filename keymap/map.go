@@ -16,7 +16,7 @@ type Map []Bind
 func (m Map) Resolve(event *tcell.EventKey) commands.Command {
 	for _, bind := range m {
 		if event.Key() == tcell.KeyRune {
-			if bind.Key.Char == event.Rune() {
+			if bind.Key.Char == event.Rune() && bind.Key.Mod == event.Modifiers()&tcell.ModAlt {
 				return bind.Cmd
 			}
 		} else if event.Key() == bind.Key.Code {
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -10,16 +12,42 @@ import (
 
 	"github.com/jorgerojas26/lazysql/app"
 	"github.com/jorgerojas26/lazysql/components"
+	"github.com/jorgerojas26/lazysql/helpers"
 	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
 )
 
 var version = "dev"
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "exec" {
+		os.Exit(runExec(os.Args[2:]))
+	}
+
 	rawLogLvl := flag.String("loglvl", "info", "Log level")
 	logFile := flag.String("logfile", "", "Log file")
+	connectionName := flag.String("c", "", "Name of a saved connection to open")
+	database := flag.String("database", "", "Database to select on startup")
+	table := flag.String("table", "", "Table to open on startup")
+	query := flag.String("query", "", "Query to run in the SQL editor on startup")
 	flag.Parse()
 
+	// check if "version" arg is passed
+	argsWithProg := os.Args
+
+	if len(argsWithProg) > 1 {
+		switch argsWithProg[1] {
+		case "version":
+			println("LazySQL version: ", version)
+			os.Exit(0)
+		}
+	}
+
+	dsn, ok := parseConnectionString(flag.CommandLine)
+	if !ok {
+		os.Exit(2)
+	}
+
 	logLvl, parseError := logger.ParseLogLevel(*rawLogLvl)
 	if parseError != nil {
 		panic(parseError)
@@ -40,15 +68,30 @@ func main() {
 		panic(mysqlError)
 	}
 
-	// check if "version" arg is passed
-	argsWithProg := os.Args
+	// Errors of the connection opened on startup, reported once the app stops
+	startupErrors := make(chan error, 1)
 
-	if len(argsWithProg) > 1 {
-		switch argsWithProg[1] {
-		case "version":
-			println("LazySQL version: ", version)
-			os.Exit(0)
+	if dsn != "" || *connectionName != "" {
+		connection, err := startupConnection(dsn, *connectionName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+
+		// Drawing needs the app running, so connect once it has started. The queued
+		// update only runs after Run has set up the screen and the root.
+		go func() {
+			app.App.QueueUpdate(func() {})
+
+			home, err := components.ConnectToDatabase(connection)
+			if err != nil {
+				startupErrors <- err
+				app.App.Stop()
+				return
+			}
+
+			home.RunStartupActions(*database, *table, *query)
+		}()
 	}
 
 	if err := app.App.
@@ -57,4 +100,60 @@ func main() {
 		Run(); err != nil {
 		panic(err)
 	}
+
+	if err := components.SaveWorkspaces(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	select {
+	case err := <-startupErrors:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	default:
+	}
+}
+
+// startupConnection returns the connection passed on the command line, either
+// as a connection string or as the name of a saved connection.
+func startupConnection(dsn string, connectionName string) (models.Connection, error) {
+	if dsn != "" && connectionName != "" {
+		return models.Connection{}, errors.New("a connection string and -c can not be used together")
+	}
+
+	if dsn != "" {
+		return helpers.ConnectionFromURL(helpers.ConnectionNameFromURL(dsn), dsn)
+	}
+
+	connections, err := helpers.LoadConnections()
+	if err != nil {
+		return models.Connection{}, err
+	}
+
+	connection, ok := helpers.FindConnection(connections, connectionName)
+	if !ok {
+		return models.Connection{}, fmt.Errorf("connection %q not found", connectionName)
+	}
+
+	return connection, nil
+}
+
+// parseConnectionString returns the connection string left in the arguments of parsed flags and
+// parses the flags that follow it, which the first parse stops at. It reports an unexpected
+// argument after them on stderr and returns false on errors.
+func parseConnectionString(flags *flag.FlagSet) (string, bool) {
+	dsn := flags.Arg(0)
+	if dsn == "" {
+		return "", true
+	}
+
+	if err := flags.Parse(flags.Args()[1:]); err != nil {
+		return "", false
+	}
+
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected argument: %s\n", flags.Arg(0))
+		return "", false
+	}
+
+	return dsn, true
 }
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/rivo/tview"
 )
 
@@ -9,6 +12,9 @@ type Connection struct {
 	Provider string
 	DBName   string
 	URL      string
+	Group    string   `toml:",omitempty"`
+	Tags     []string `toml:",omitempty"`
+	ReadOnly bool     `toml:"read_only,omitempty"`
 }
 
 type StateChange struct {
@@ -51,12 +57,13 @@ const (
 )
 
 type PrimaryKeyInfo struct {
-	Name  string
-	Value string
+	Name   string
+	Value  string
+	IsNull bool
 }
 
 func (pki PrimaryKeyInfo) Equal(other PrimaryKeyInfo) bool {
-	return pki.Name == other.Name && pki.Value == other.Value
+	return pki.Name == other.Name && pki.Value == other.Value && pki.IsNull == other.IsNull
 }
 
 type DbDmlChange struct {
@@ -65,6 +72,10 @@ type DbDmlChange struct {
 	PrimaryKeyInfo []PrimaryKeyInfo
 	Values         []CellValue
 	Type           DmlType
+	// FullRowMatch is set for MySQL tables without a primary or unique key, which have no row
+	// identity. PrimaryKeyInfo then holds the original value of every column and only the first
+	// matching row is changed.
+	FullRowMatch bool
 }
 
 type DatabaseTableColumn struct {
@@ -76,11 +87,346 @@ type DatabaseTableColumn struct {
 	Extra   string
 }
 
+// Types of the database objects other than tables
+const (
+	ObjectTypeView             = "view"
+	ObjectTypeMaterializedView = "materialized view"
+	ObjectTypeFunction         = "function"
+	ObjectTypeProcedure        = "procedure"
+	ObjectTypeTrigger          = "trigger"
+	ObjectTypeSequence         = "sequence"
+	ObjectTypeType             = "type"
+)
+
+// ObjectTypes lists the object types in the order they are shown.
+var ObjectTypes = []string{
+	ObjectTypeView,
+	ObjectTypeMaterializedView,
+	ObjectTypeFunction,
+	ObjectTypeProcedure,
+	ObjectTypeTrigger,
+	ObjectTypeSequence,
+	ObjectTypeType,
+}
+
+// DatabaseObject is a database object other than a table, like a view or a function.
+type DatabaseObject struct {
+	Database string
+	// Schema is only set by the drivers that group the tables by schema
+	Schema string
+	// Name of the object, functions include their arguments to tell overloads apart
+	Name string
+	// Table is the table of a trigger
+	Table string
+	Type  string
+}
+
+// IsView reports whether the records of the object can be browsed like the ones of a table.
+func (object DatabaseObject) IsView() bool {
+	return object.Type == ObjectTypeView || object.Type == ObjectTypeMaterializedView
+}
+
+// ForeignKey is a foreign key between two tables. The table names are in the format the driver
+// uses for its records, so Postgres tables include the schema.
+type ForeignKey struct {
+	Name              string
+	Table             string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+}
+
+// Diagram is an entity relationship diagram of the tables of a database or schema.
+type Diagram struct {
+	Tables      []DiagramTable
+	ForeignKeys []ForeignKey
+}
+
+// DiagramTable is a table of a diagram. Tables referenced from outside of the database or
+// schema of the diagram have no columns.
+type DiagramTable struct {
+	Name    string
+	Columns []DiagramColumn
+}
+
+type DiagramColumn struct {
+	Name       string
+	Type       string
+	PrimaryKey bool
+}
+
 type Query struct {
 	Query string
 	Args  []interface{}
 }
 
+type SchemaChangeType int8
+
+const (
+	SchemaAddColumn SchemaChangeType = iota
+	SchemaAlterColumn
+	SchemaDropColumn
+	SchemaCreateIndex
+	SchemaDropIndex
+	SchemaCreateTable
+	SchemaRenameTable
+	SchemaTruncateTable
+	SchemaDropTable
+	SchemaDuplicateTable
+)
+
+// ColumnDefinition is a column as it is written in an ALTER TABLE statement.
+type ColumnDefinition struct {
+	Name string
+	Type string
+	// Default is a SQL expression, so strings must be quoted. It is empty when there is no default.
+	Default string
+	// Extra holds the MySQL attributes that must be repeated when a column is redefined, like AUTO_INCREMENT
+	Extra    string
+	Nullable bool
+}
+
+type IndexDefinition struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// SchemaChange is a change to the structure of a table. Altering a column compares Column with
+// OldColumn, so a different name renames the column too.
+type SchemaChange struct {
+	Database  string
+	Table     string
+	Column    ColumnDefinition
+	OldColumn ColumnDefinition
+	Index     IndexDefinition
+	// Columns and PrimaryKey define the table to create
+	Columns    []ColumnDefinition
+	PrimaryKey []string
+	// NewTable is the name of the renamed or duplicated table, without the schema
+	NewTable string
+	// WithData copies the rows of a duplicated table too
+	WithData bool
+	Type     SchemaChangeType
+}
+
+// TableSchema is the structure of a table that a schema diff compares. Name doesn't include the
+// schema, so tables of different schemas can be compared.
+type TableSchema struct {
+	Name       string
+	Columns    []ColumnDefinition
+	PrimaryKey []string
+	Indexes    []IndexDefinition
+}
+
+type DiffType int8
+
+const (
+	DiffAdded DiffType = iota
+	DiffRemoved
+	DiffChanged
+)
+
+// String returns the sign that marks the type of a difference.
+func (diffType DiffType) String() string {
+	switch diffType {
+	case DiffAdded:
+		return "+"
+	case DiffRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// SchemaDifference is a table, column or index that is only in the source of a schema diff, only
+// in its target or different in both. Changes make the target match the source, their tables
+// don't include the schema.
+type SchemaDifference struct {
+	Table  string
+	Column string
+	Index  string
+	// Detail describes the difference, like the old and new type of a column
+	Detail  string
+	Changes []SchemaChange
+	Type    DiffType
+}
+
+// TableDataDiff holds the rows of a table that differ between two databases, the left and the
+// right, matched by primary key. The rows have the values of Columns, in the format of the
+// records, so they can be NULL& or EMPTY&.
+type TableDataDiff struct {
+	Columns    []string
+	PrimaryKey []string
+	OnlyLeft   [][]string
+	OnlyRight  [][]string
+	Changed    []ChangedRow
+	// Rows is the number of rows read from both sides
+	Rows int
+}
+
+type ChangedRow struct {
+	Left  []string
+	Right []string
+}
+
+// Session is a connection to the database server, as listed by the activity monitor.
+type Session struct {
+	ID       string
+	User     string
+	Database string
+	State    string
+	Query    string
+	// BlockedBy holds the ids of the sessions that hold the locks the session waits for
+	BlockedBy []string
+	// Duration is how long the session has been running its query, or in its state when idle
+	Duration time.Duration
+}
+
+// Operators of the quick filters
+const (
+	FilterEqual     = "="
+	FilterNotEqual  = "<>"
+	FilterIsNull    = "IS NULL"
+	FilterIsNotNull = "IS NOT NULL"
+	// FilterLike matches the text of the column with a pattern, regardless of its type
+	FilterLike = "LIKE"
+)
+
+// FilterCondition is a condition on a column added from a cell of the records, the drivers bind
+// its value instead of writing it in the query.
+type FilterCondition struct {
+	Column   string
+	Operator string
+	// Value is the value of the cell, it is not used by IS NULL and IS NOT NULL
+	Value string
+	// OrColumns are other columns the rows match the condition on instead of Column
+	OrColumns []string
+}
+
+// String returns the condition as it is shown in the filter.
+func (condition FilterCondition) String() string {
+	switch condition.Operator {
+	case FilterIsNull, FilterIsNotNull:
+		return condition.Column + " " + condition.Operator
+	}
+
+	value := condition.Value
+	if value == "EMPTY&" {
+		value = ""
+	}
+
+	return fmt.Sprintf("%s %s '%s'", condition.Column, condition.Operator, value)
+}
+
+// Workspace is what was open on a connection when lazysql quit, it is reopened on the next
+// connection.
+type Workspace struct {
+	Tabs []WorkspaceTab `toml:"tabs,omitempty"`
+	// CurrentTab is the reference of the tab that was shown
+	CurrentTab string `toml:"current_tab,omitempty"`
+	// EditorOpen tells whether the SQL editor tab was open, Editor is its text
+	EditorOpen bool   `toml:"editor_open,omitempty"`
+	Editor     string `toml:"editor,omitempty"`
+	// PendingChanges are the changes that were not saved, they are only queued again when the
+	// user recovers them
+	PendingChanges []DbDmlChange `toml:"pending_changes,omitempty"`
+}
+
+// WorkspaceTab is a tab with the records of a table.
+type WorkspaceTab struct {
+	Database   string            `toml:"database"`
+	Table      string            `toml:"table"`
+	Name       string            `toml:"name"`
+	Reference  string            `toml:"reference"`
+	IsView     bool              `toml:"is_view,omitempty"`
+	Where      string            `toml:"where,omitempty"`
+	Conditions []FilterCondition `toml:"conditions,omitempty"`
+	Sort       []OrderBy         `toml:"sort,omitempty"`
+	Offset     int               `toml:"offset,omitempty"`
+}
+
+// TableLayout is how the columns of the records of a table are shown, it is remembered per
+// connection and table.
+type TableLayout struct {
+	// Order is the order of the columns, the columns missing from it keep the order of the table
+	// after the ordered ones
+	Order  []string `toml:"order,omitempty"`
+	Hidden []string `toml:"hidden,omitempty"`
+	// Pinned is the number of leading columns kept in view while scrolling horizontally
+	Pinned int `toml:"pinned,omitempty"`
+	// Widths are the widths of the columns, longer values are truncated
+	Widths map[string]int `toml:"widths,omitempty"`
+}
+
+// OrderBy is a column that the records are sorted by.
+type OrderBy struct {
+	Column     string
+	Descending bool
+}
+
+// RecordsQuery selects a page of the records of a table. The drivers quote the names of the
+// columns and bind the values of the conditions.
+type RecordsQuery struct {
+	// Where is a condition typed by the user. It is trusted SQL that runs as is, the drivers only
+	// reject the ones that could reach out of the WHERE clause
+	Where string
+	// Conditions are the quick filters, the rows have to match all of them and Where
+	Conditions []FilterCondition
+	OrderBy    []OrderBy
+	// After keeps the rows that come after these values of the first OrderBy columns, which have
+	// to be ascending. It pages through a table by its key instead of an offset
+	After  []string
+	Offset int
+	Limit  int
+	// RowID selects the identity of the rows as their first column, for the tables without a
+	// key. Only the drivers with a RowIDColumn read it
+	RowID bool
+}
+
+// Role is a user or a role of the database server.
+type Role struct {
+	Name string
+	// Host is the host part of the MySQL accounts
+	Host string
+	// Attributes are the abilities of the role, like logging in or being a superuser
+	Attributes []string
+}
+
+// String returns the name of the role, with the host of MySQL accounts.
+func (role Role) String() string {
+	if role.Host != "" {
+		return role.Name + "@" + role.Host
+	}
+
+	return role.Name
+}
+
+// Types of the objects that privileges are granted on
+const (
+	GrantObjectGlobal    = "global"
+	GrantObjectDatabase  = "database"
+	GrantObjectSchema    = "schema"
+	GrantObjectTable     = "table"
+	GrantObjectProcedure = "procedure"
+	GrantObjectFunction  = "function"
+	GrantObjectRole      = "role"
+)
+
+// Grant is a set of privileges of a role on an object, or the membership of the role in the roles
+// of Privileges when the object type is GrantObjectRole.
+type Grant struct {
+	Role       Role
+	ObjectType string
+	// Object is the database or the schema, database.table in MySQL and schema.table in
+	// PostgreSQL, database.routine for the procedures and functions of MySQL, and empty for
+	// global privileges and roles
+	Object     string
+	Privileges []string
+	// Grantable is set when the role can grant the privileges to others
+	Grantable bool
+}
+
 type SidebarEditingCommitParams struct {
 	ColumnName string
 	NewValue   string